@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// The calibrated probability of a decision function value f
+func sigmoidCalibration(a, b, f float64) float64 { return 1 / (1 + math.Exp(a*f+b)) }
+
+// Rows of a [3, 4] one-vs-rest decision function on the iris measurements
+var calibFolds = []struct {
+	coef      [][]float64
+	intercept []float64
+	a, b      []float64
+}{
+	{
+		[][]float64{{0.4, 1.3, -2.1, -0.9}, {0.3, -1.5, 0.5, -1.2}, {-1.4, -1.3, 2.2, 2.4}},
+		[]float64{0.3, 1.2, -1.5},
+		[]float64{-1.6, -2.3, -1.1}, []float64{-0.4, 0.9, 0.2},
+	},
+	{
+		[][]float64{{0.5, 1.1, -2.3, -1.0}, {0.2, -1.4, 0.6, -1.3}, {-1.5, -1.2, 2.0, 2.6}},
+		[]float64{0.2, 1.1, -1.4},
+		[]float64{-1.4, -2.0, -1.3}, []float64{-0.2, 0.7, 0.5},
+	},
+}
+
+// The one-vs-rest decision function of x
+func decision(coef [][]float64, intercept []float64, x []float64) []float64 {
+	out := make([]float64, len(coef))
+	for c := range coef {
+		out[c] = r32(intercept[c])
+		for f := range x {
+			out[c] += r32(coef[c][f]) * float64(float32(x[f]))
+		}
+	}
+	return out
+}
+
+// Divides p by its sum, as normaliseNodes does
+func normaliseRows(p []float64) []float64 {
+	s := 0.0
+	for _, v := range p {
+		s += v
+	}
+	out := make([]float64, len(p))
+	for i := range p {
+		if s == 0 {
+			out[i] = 1 / float64(len(p))
+		} else {
+			out[i] = p[i] / s
+		}
+	}
+	return out
+}
+
+// The ops of sklearn's 1 / (1 + exp(a * f + b))
+func sigmoidNodes(g *ir.GraphProto, f, prefix string, a, b []float64) string {
+	g.Initializer = append(g.Initializer,
+		floats(prefix+"a", []int64{int64(len(a))}, f32(a)), floats(prefix+"b", []int64{int64(len(b))}, f32(b)))
+	g.Node = append(g.Node,
+		node("Mul", "", []string{f, prefix + "a"}, []string{prefix + "af"}),
+		node("Add", "", []string{prefix + "af", prefix + "b"}, []string{prefix + "afb"}),
+		node("Exp", "", []string{prefix + "afb"}, []string{prefix + "exp"}),
+		node("Add", "", []string{prefix + "exp", "one"}, []string{prefix + "denominator"}),
+		node("Reciprocal", "", []string{prefix + "denominator"}, []string{prefix + "calibrated"}),
+	)
+	return prefix + "calibrated"
+}
+
+// Divides each row by its sum, a row summing to 0 gets the same probability for every class
+func normaliseNodes(g *ir.GraphProto, in, prefix string, nc int) string {
+	g.Initializer = append(g.Initializer, floats(prefix+"uniform", []int64{1}, []float32{float32(1 / float64(nc))}))
+	g.Node = append(g.Node,
+		node("ReduceSum", "", []string{in}, []string{prefix + "sum"}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+		node("Div", "", []string{in, prefix + "sum"}, []string{prefix + "divided"}),
+		node("Equal", "", []string{prefix + "sum", "zero"}, []string{prefix + "zero_sum"}),
+		node("Where", "", []string{prefix + "zero_sum", prefix + "uniform", prefix + "divided"}, []string{prefix + "normalised"}),
+	)
+	return prefix + "normalised"
+}
+
+// A CalibratedClassifierCV with a sigmoid calibration of each of the folds, their probabilities
+// are averaged
+func calibratedSigmoid() *ir.GraphProto {
+	g := &ir.GraphProto{Name: "CalibratedClassifierCV"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("one", []int64{1}, []float32{1}), floats("zero", []int64{1}, []float32{0}),
+		floats("n_folds", []int64{1}, []float32{2}), ints("classes", []int64{3}, []int64{0, 1, 2}),
+		ints("label_shape", []int64{1}, []int64{-1}),
+	}
+	var folds []string
+	for i, fold := range calibFolds {
+		p := fmt.Sprintf("fold%d_", i)
+		g.Node = append(g.Node, node("LinearClassifier", "ai.onnx.ml", []string{"X"}, []string{p + "label", p + "decision"},
+			attrInts("classlabels_ints", []int64{0, 1, 2}), attrFloats("coefficients", flat(fold.coef)),
+			attrFloats("intercepts", f32(fold.intercept)), attrS("post_transform", "NONE")))
+		calibrated := sigmoidNodes(g, p+"decision", p, fold.a, fold.b)
+		folds = append(folds, normaliseNodes(g, calibrated, p, 3))
+	}
+	g.Node = append(g.Node,
+		node("Add", "", folds, []string{"fold_sum"}),
+		node("Div", "", []string{"fold_sum", "n_folds"}, []string{"probabilities"}),
+		node("ArgMax", "", []string{"probabilities"}, []string{"label_index"}, attrI("axis", 1), attrI("keepdims", 0)),
+		node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"classes", "label_index"}, []string{"label_row"}),
+		node("Reshape", "", []string{"label_row", "label_shape"}, []string{"label"}),
+	)
+	g.Output = []*ir.ValueInfoProto{tensorInfo("label", "INT64", -1), tensorInfo("probabilities", "FLOAT", -1, 3)}
+
+	var labels []int64
+	var probs [][]float64
+	for _, x := range knnInputs {
+		mean := make([]float64, 3)
+		for _, fold := range calibFolds {
+			d := decision(fold.coef, fold.intercept, x)
+			p := make([]float64, 3)
+			for c := range 3 {
+				p[c] = sigmoidCalibration(r32(fold.a[c]), r32(fold.b[c]), float64(float32(d[c])))
+			}
+			for c, v := range normaliseRows(p) {
+				mean[c] += v / 2
+			}
+		}
+		labels = append(labels, int64(argmax(mean)))
+		probs = append(probs, mean)
+	}
+	fmt.Printf("calibrated sigmoid %#v\n%#v\n", labels, probs)
+	return g
+}
+
+// Isotonic regressions of each class, the thresholds increase and the last calibrated values are 0
+var isotonicX = [][]float64{{-4, -2, 0.5, 2, 5}, {-3, -2.5, -0.5, 1, 4}, {-5, -3, 0, 1.5, 6}}
+var isotonicY = [][]float64{{0, 0, 0.4, 0.9, 1}, {0, 0, 0.2, 0.7, 0.95}, {0, 0, 0.3, 0.85, 1}}
+
+// The isotonic regression of xs to ys at t, interpolated linearly and clipped at both ends
+func isotonic(xs, ys []float64, t float64) float64 {
+	t = math.Max(xs[0], math.Min(xs[len(xs)-1], t))
+	i := 0
+	for j := 1; j < len(xs)-1; j++ {
+		if xs[j] < t {
+			i = j
+		}
+	}
+	slope := r32((ys[i+1] - ys[i]) / (xs[i+1] - xs[i]))
+	return ys[i] + (t-xs[i])*slope
+}
+
+// A CalibratedClassifierCV with an isotonic calibration of a single fold
+func calibratedIsotonic() *ir.GraphProto {
+	fold := calibFolds[0]
+	g := &ir.GraphProto{Name: "CalibratedClassifierCV"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{floats("zero", []int64{1}, []float32{0}), strs("classes", []int64{3}, irisNames),
+		ints("label_shape", []int64{1}, []int64{-1})}
+	g.Node = append(g.Node, node("LinearClassifier", "ai.onnx.ml", []string{"X"}, []string{"base_label", "decision"},
+		attrStrings("classlabels_strings", irisNames), attrFloats("coefficients", flat(fold.coef)),
+		attrFloats("intercepts", f32(fold.intercept)), attrS("post_transform", "NONE")))
+	var columns []string
+	for c := range 3 {
+		p := fmt.Sprintf("class%d_", c)
+		xs, ys := isotonicX[c], isotonicY[c]
+		slopes := make([]float64, len(xs)-1)
+		for i := range slopes {
+			slopes[i] = (ys[i+1] - ys[i]) / (xs[i+1] - xs[i])
+		}
+		g.Initializer = append(g.Initializer,
+			ints(p+"column", []int64{1}, []int64{int64(c)}),
+			floats(p+"x_min", nil, []float32{float32(xs[0])}), floats(p+"x_max", nil, []float32{float32(xs[len(xs)-1])}),
+			floats(p+"x_inner", []int64{int64(len(xs) - 2)}, f32(xs[1:len(xs)-1])),
+			floats(p+"x", []int64{int64(len(xs))}, f32(xs)), floats(p+"y", []int64{int64(len(ys))}, f32(ys)),
+			floats(p+"slopes", []int64{int64(len(slopes))}, f32(slopes)),
+		)
+		g.Node = append(g.Node,
+			node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"decision", p + "column"}, []string{p + "decision"}),
+			node("Clip", "", []string{p + "decision", p + "x_min", p + "x_max"}, []string{p + "clipped"}),
+			node("Less", "", []string{p + "x_inner", p + "clipped"}, []string{p + "above"}),
+			node("Cast", "", []string{p + "above"}, []string{p + "above_count"}, attrI("to", int64(dt("INT64")))),
+			node("ReduceSum", "", []string{p + "above_count"}, []string{p + "segment"}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+			node("Gather", "", []string{p + "x", p + "segment"}, []string{p + "segment_x"}),
+			node("Gather", "", []string{p + "y", p + "segment"}, []string{p + "segment_y"}),
+			node("Gather", "", []string{p + "slopes", p + "segment"}, []string{p + "segment_slope"}),
+			node("Sub", "", []string{p + "clipped", p + "segment_x"}, []string{p + "offset"}),
+			node("Mul", "", []string{p + "offset", p + "segment_slope"}, []string{p + "rise"}),
+			node("Add", "", []string{p + "segment_y", p + "rise"}, []string{p + "calibrated"}),
+		)
+		columns = append(columns, p+"calibrated")
+	}
+	g.Node = append(g.Node, node("Concat", "", columns, []string{"calibrated"}, attrI("axis", 1)))
+	probabilities := normaliseNodes(g, "calibrated", "", 3)
+	g.Node = append(g.Node,
+		node("Identity", "", []string{probabilities}, []string{"probabilities"}),
+		node("ArgMax", "", []string{"probabilities"}, []string{"label_index"}, attrI("axis", 1), attrI("keepdims", 0)),
+		node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"classes", "label_index"}, []string{"label_row"}),
+		node("Reshape", "", []string{"label_row", "label_shape"}, []string{"label"}),
+	)
+	g.Output = []*ir.ValueInfoProto{tensorInfo("label", "STRING", -1), tensorInfo("probabilities", "FLOAT", -1, 3)}
+
+	var labels []string
+	var probs [][]float64
+	for _, x := range calibInputs {
+		d := decision(fold.coef, fold.intercept, x)
+		p := make([]float64, 3)
+		for c := range 3 {
+			xs, ys := make([]float64, 5), make([]float64, 5)
+			for i := range xs {
+				xs[i], ys[i] = r32(isotonicX[c][i]), r32(isotonicY[c][i])
+			}
+			p[c] = isotonic(xs, ys, float64(float32(d[c])))
+		}
+		fmt.Printf("  decision %v calibrated %v\n", d, p)
+		pr := normaliseRows(p)
+		labels = append(labels, irisNames[argmax(pr)])
+		probs = append(probs, pr)
+	}
+	fmt.Printf("calibrated isotonic %#v\n%#v\n", labels, probs)
+	return g
+}
+
+var calibInputs = append(knnInputs, []float64{7, 5, 5, 1.5})
+
+// Versicolor against virginica, a sigmoid calibration of a logistic regression's decision function
+var binaryCoef = []float64{0, 0, 1.2, 2.5}
+
+const (
+	binaryIntercept = -9.5
+	binaryA         = -1.3
+	binaryB         = 0.4
+	threshold       = 0.25
+)
+
+// The nodes of the binary calibrated classifier, up to the probabilities of both classes
+func calibratedBinaryNodes(g *ir.GraphProto) {
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = append(g.Initializer, floats("one", []int64{1}, []float32{1}), ints("positive_column", []int64{1}, []int64{1}))
+	g.Node = append(g.Node,
+		node("LinearClassifier", "ai.onnx.ml", []string{"X"}, []string{"base_label", "decision"},
+			attrStrings("classlabels_strings", []string{"versicolor", "virginica"}), attrFloats("coefficients", f32(binaryCoef)),
+			attrFloats("intercepts", []float32{binaryIntercept}), attrI("multi_class", 1), attrS("post_transform", "NONE")),
+		node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"decision", "positive_column"}, []string{"positive_decision"}),
+	)
+	positive := sigmoidNodes(g, "positive_decision", "", []float64{binaryA}, []float64{binaryB})
+	g.Node = append(g.Node,
+		node("Sub", "", []string{"one", positive}, []string{"negative"}),
+		node("Concat", "", []string{"negative", positive}, []string{"probabilities"}, attrI("axis", 1)),
+	)
+}
+
+// The calibrated probability of virginica for x
+func binaryPositive(x []float64) float64 {
+	z := r32(binaryIntercept)
+	for f := range 4 {
+		z += r32(binaryCoef[f]) * float64(float32(x[f]))
+	}
+	return sigmoidCalibration(r32(binaryA), r32(binaryB), float64(float32(z)))
+}
+
+// A binary CalibratedClassifierCV, sigmoid calibrated
+func calibratedBinary() *ir.GraphProto {
+	g := &ir.GraphProto{Name: "CalibratedClassifierCV"}
+	calibratedBinaryNodes(g)
+	g.Initializer = append(g.Initializer, strs("classes", []int64{2}, []string{"versicolor", "virginica"}), ints("label_shape", []int64{1}, []int64{-1}))
+	g.Node = append(g.Node,
+		node("ArgMax", "", []string{"probabilities"}, []string{"label_index"}, attrI("axis", 1), attrI("keepdims", 0)),
+		node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"classes", "label_index"}, []string{"label_row"}),
+		node("Reshape", "", []string{"label_row", "label_shape"}, []string{"label"}),
+	)
+	g.Output = []*ir.ValueInfoProto{tensorInfo("label", "STRING", -1), tensorInfo("probabilities", "FLOAT", -1, 2)}
+	var positive []float64
+	for _, x := range knnInputs {
+		positive = append(positive, binaryPositive(x))
+	}
+	fmt.Printf("calibrated binary positive %#v\n", positive)
+	return g
+}
+
+// TunedThresholdClassifierCV around the calibrated classifier, the positive class is predicted
+// from a probability of threshold
+func tunedThreshold() *ir.GraphProto {
+	g := &ir.GraphProto{Name: "TunedThresholdClassifierCV"}
+	calibratedBinaryNodes(g)
+	g.Initializer = append(g.Initializer,
+		floats("threshold", []int64{1}, []float32{threshold}),
+		strs("negative_label", []int64{1}, []string{"versicolor"}), strs("positive_label", []int64{1}, []string{"virginica"}),
+		ints("label_shape", []int64{1}, []int64{-1}),
+	)
+	g.Node = append(g.Node,
+		node("Less", "", []string{"calibrated", "threshold"}, []string{"below_threshold"}),
+		node("Where", "", []string{"below_threshold", "negative_label", "positive_label"}, []string{"label_column"}),
+		node("Reshape", "", []string{"label_column", "label_shape"}, []string{"label"}),
+	)
+	g.Output = []*ir.ValueInfoProto{tensorInfo("label", "STRING", -1), tensorInfo("probabilities", "FLOAT", -1, 2)}
+	var labels []string
+	for _, x := range knnInputs {
+		if binaryPositive(x) < r32(threshold) {
+			labels = append(labels, "versicolor")
+		} else {
+			labels = append(labels, "virginica")
+		}
+	}
+	fmt.Printf("tuned threshold %#v\n", labels)
+	return g
+}
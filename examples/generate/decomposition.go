@@ -0,0 +1,379 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// Eigenvalues in decreasing order and the eigenvectors as rows, by Jacobi rotations
+func eigh(a [][]float64) ([]float64, [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	v := make([][]float64, n)
+	for i := range n {
+		m[i] = append([]float64(nil), a[i]...)
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := range n {
+			for q := p + 1; q < n; q++ {
+				if math.Abs(m[p][q]) < 1e-300 {
+					continue
+				}
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := range n {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+				for k := range n {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+				for k := range n {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return m[order[i]][order[i]] > m[order[j]][order[j]] })
+	values := make([]float64, n)
+	vectors := make([][]float64, n)
+	for i, o := range order {
+		values[i] = m[o][o]
+		vectors[i] = make([]float64, n)
+		for k := range n {
+			vectors[i][k] = v[k][o]
+		}
+		// sklearn's svd_flip makes the largest absolute value of each component positive
+		big := 0
+		for k := range n {
+			if math.Abs(vectors[i][k]) > math.Abs(vectors[i][big]) {
+				big = k
+			}
+		}
+		if vectors[i][big] < 0 {
+			for k := range n {
+				vectors[i][k] = -vectors[i][k]
+			}
+		}
+	}
+	return values, vectors
+}
+
+// The iris training samples of the k-nearest neighbours examples, in float64
+func trainRows() [][]float64 {
+	x := make([][]float64, len(knnTrain))
+	for i, r := range knnTrain {
+		x[i] = make([]float64, 4)
+		for f := range 4 {
+			x[i][f] = float64(r[f])
+		}
+	}
+	return x
+}
+
+// Rounds every value of a matrix to the float it is stored as
+func round32(m [][]float64) [][]float64 {
+	out := make([][]float64, len(m))
+	for i := range m {
+		out[i] = make([]float64, len(m[i]))
+		for j := range m[i] {
+			out[i][j] = r32(m[i][j])
+		}
+	}
+	return out
+}
+
+// The test inputs as the float tensor the tests feed
+func inputs32() [][]float64 {
+	return round32(knnInputs)
+}
+
+// Projects each row of x on the components
+func project(x [][]float64, components [][]float64) [][]float64 {
+	out := make([][]float64, len(x))
+	for i := range x {
+		out[i] = make([]float64, len(components))
+		for c := range components {
+			out[i][c] = dot(x[i], components[c])
+		}
+	}
+	return out
+}
+
+// Prints a reference as the rows of a [][]float64 literal
+func printMatrix(name string, m [][]float64) {
+	fmt.Printf("%s\n", name)
+	for _, r := range m {
+		fmt.Print("\t\t{")
+		for j, v := range r {
+			if j > 0 {
+				fmt.Print(", ")
+			}
+			fmt.Printf("%.7g", v)
+		}
+		fmt.Println("},")
+	}
+}
+
+// The mean of the training samples, and the nc components of largest variance with their variances
+func pcaFit(nc int) (mean []float64, components [][]float64, variance []float64) {
+	x := trainRows()
+	mean = make([]float64, 4)
+	for _, r := range x {
+		for f := range 4 {
+			mean[f] += r[f] / float64(len(x))
+		}
+	}
+	cov := make([][]float64, 4)
+	for i := range cov {
+		cov[i] = make([]float64, 4)
+		for j := range 4 {
+			for _, r := range x {
+				cov[i][j] += (r[i] - mean[i]) * (r[j] - mean[j]) / float64(len(x)-1)
+			}
+		}
+	}
+	values, vectors := eigh(cov)
+	return mean, vectors[:nc], values[:nc]
+}
+
+// PCA with 2 components, whitened
+func pca() *ir.GraphProto {
+	mean, components, variance := pcaFit(2)
+	mean, components = f64(f32(mean)), round32(components)
+	std := make([]float64, 2)
+	for i := range std {
+		std[i] = r32(math.Sqrt(variance[i]))
+	}
+	g := &ir.GraphProto{Name: "PCA"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("mean", []int64{4}, f32(mean)), floats("components", []int64{4, 2}, transposed(components)),
+		floats("std", []int64{2}, f32(std)),
+	}
+	g.Node = []*ir.NodeProto{
+		node("Sub", "", []string{"X", "mean"}, []string{"centred"}),
+		node("MatMul", "", []string{"centred", "components"}, []string{"projected"}),
+		node("Div", "", []string{"projected", "std"}, []string{"variable"}),
+	}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 2)}
+	x := inputs32()
+	for i := range x {
+		for f := range 4 {
+			x[i][f] = r32(x[i][f] - mean[f])
+		}
+	}
+	out := project(x, components)
+	for i := range out {
+		for c := range out[i] {
+			out[i][c] /= std[c]
+		}
+	}
+	printMatrix("pca", out)
+	return g
+}
+
+// IncrementalPCA with 3 components, which skl2onnx writes as a single Gemm
+func incrementalPCA() *ir.GraphProto {
+	mean, components, _ := pcaFit(3)
+	components = round32(components)
+	shift := make([]float64, 3)
+	for c := range 3 {
+		shift[c] = r32(-dot(mean, components[c]))
+	}
+	g := &ir.GraphProto{Name: "IncrementalPCA"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("components", []int64{3, 4}, flat(components)), floats("shift", []int64{3}, f32(shift)),
+	}
+	g.Node = []*ir.NodeProto{node("Gemm", "", []string{"X", "components", "shift"}, []string{"variable"}, attrI("transB", 1))}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 3)}
+	out := project(inputs32(), components)
+	for i := range out {
+		for c := range out[i] {
+			out[i][c] += shift[c]
+		}
+	}
+	printMatrix("incremental pca", out)
+	return g
+}
+
+// TruncatedSVD doesn't centre the data, its components are the right singular vectors of X
+func truncatedSVD() *ir.GraphProto {
+	x := trainRows()
+	xtx := make([][]float64, 4)
+	for i := range xtx {
+		xtx[i] = make([]float64, 4)
+		for j := range 4 {
+			for _, r := range x {
+				xtx[i][j] += r[i] * r[j]
+			}
+		}
+	}
+	_, vectors := eigh(xtx)
+	components := round32(vectors[:2])
+	g := &ir.GraphProto{Name: "TruncatedSVD"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{floats("components", []int64{4, 2}, transposed(components))}
+	g.Node = []*ir.NodeProto{node("MatMul", "", []string{"X", "components"}, []string{"variable"})}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 2)}
+	printMatrix("truncated svd", project(inputs32(), components))
+	return g
+}
+
+// GaussianRandomProjection to 3 components drawn from N(0, 1/3)
+func gaussianRandomProjection() *ir.GraphProto {
+	s := lcg(11)
+	components := make([][]float64, 3)
+	for c := range components {
+		components[c] = make([]float64, 4)
+		for f := range components[c] {
+			// Box-Muller
+			u, v := (s.next()+1)/2, (s.next()+1)/2
+			components[c][f] = r32(math.Sqrt(-2*math.Log(1-u)) * math.Cos(2*math.Pi*v) / math.Sqrt(3))
+		}
+	}
+	g := &ir.GraphProto{Name: "GaussianRandomProjection"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{floats("components", []int64{4, 3}, transposed(components))}
+	g.Node = []*ir.NodeProto{node("MatMul", "", []string{"X", "components"}, []string{"variable"})}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 3)}
+	printMatrix("gaussian random projection", project(inputs32(), components))
+	return g
+}
+
+/*
+ * KernelPCA with 2 components. The kernel of the input and the training samples is centred with
+ * the means of the training kernel, then projected on the eigenvectors divided by the square roots
+ * of their eigenvalues.
+ */
+func kernelPCA(kernel string) *ir.GraphProto {
+	x := trainRows()
+	const gamma, coef0, degree = 0.25, 1.0, 3.0
+	k := func(a, b []float64) float64 {
+		if kernel == "rbf" {
+			d := 0.0
+			for f := range a {
+				d += (a[f] - b[f]) * (a[f] - b[f])
+			}
+			return math.Exp(-gamma * d)
+		}
+		return math.Pow(gamma*dot(a, b)+coef0, degree)
+	}
+	n := len(x)
+	kf := make([][]float64, n)
+	for i := range kf {
+		kf[i] = make([]float64, n)
+		for j := range kf[i] {
+			kf[i][j] = k(x[i], x[j])
+		}
+	}
+	colMeans := make([]float64, n)
+	all := 0.0
+	for i := range n {
+		for j := range n {
+			colMeans[j] += kf[i][j] / float64(n)
+			all += kf[i][j] / float64(n*n)
+		}
+	}
+	centred := make([][]float64, n)
+	for i := range centred {
+		centred[i] = make([]float64, n)
+		for j := range centred[i] {
+			centred[i][j] = kf[i][j] - colMeans[i] - colMeans[j] + all
+		}
+	}
+	values, vectors := eigh(centred)
+	alphas := make([][]float64, 2)
+	for c := range alphas {
+		alphas[c] = make([]float64, n)
+		for i := range n {
+			alphas[c][i] = r32(vectors[c][i] / math.Sqrt(values[c]))
+		}
+	}
+
+	g := &ir.GraphProto{Name: "KernelPCA"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("X_fit", []int64{int64(n), 4}, flat(x)),
+		floats("fit_rows", []int64{int64(n)}, f32(colMeans)), floats("fit_all", []int64{1}, []float32{float32(all)}),
+		floats("alphas", []int64{int64(n), 2}, transposed(alphas)),
+	}
+	if kernel == "rbf" {
+		g.Initializer = append(g.Initializer, floats("minus_gamma", []int64{1}, []float32{-gamma}))
+		g.Node = []*ir.NodeProto{
+			node("CDist", "com.microsoft", []string{"X", "X_fit"}, []string{"distances"}, attrS("metric", "sqeuclidean")),
+			node("Mul", "", []string{"distances", "minus_gamma"}, []string{"scaled"}),
+			node("Exp", "", []string{"scaled"}, []string{"kernel"}),
+		}
+	} else {
+		g.Initializer = append(g.Initializer,
+			floats("gamma", []int64{1}, []float32{gamma}), floats("coef0", []int64{1}, []float32{coef0}),
+			floats("degree", []int64{1}, []float32{degree}),
+			floats("X_fit_T", []int64{4, int64(n)}, transposed(x)))
+		g.Node = []*ir.NodeProto{
+			node("MatMul", "", []string{"X", "X_fit_T"}, []string{"dots"}),
+			node("Mul", "", []string{"dots", "gamma"}, []string{"scaled"}),
+			node("Add", "", []string{"scaled", "coef0"}, []string{"shifted"}),
+			node("Pow", "", []string{"shifted", "degree"}, []string{"kernel"}),
+		}
+	}
+	g.Node = append(g.Node,
+		node("ReduceMean", "", []string{"kernel"}, []string{"pred_rows"}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+		node("Sub", "", []string{"kernel", "fit_rows"}, []string{"kernel_rows"}),
+		node("Sub", "", []string{"kernel_rows", "pred_rows"}, []string{"kernel_cols"}),
+		node("Add", "", []string{"kernel_cols", "fit_all"}, []string{"centred"}),
+		node("MatMul", "", []string{"centred", "alphas"}, []string{"variable"}),
+	)
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 2)}
+
+	xs := inputs32()
+	out := make([][]float64, len(xs))
+	for i, r := range xs {
+		kr := make([]float64, n)
+		m := 0.0
+		for j := range n {
+			kr[j] = k(r, x[j])
+			m += kr[j] / float64(n)
+		}
+		out[i] = make([]float64, 2)
+		for c := range 2 {
+			for j := range n {
+				out[i][c] += (kr[j] - r32(colMeans[j]) - m + r32(all)) * alphas[c][j]
+			}
+		}
+	}
+	printMatrix("kernel pca "+kernel, out)
+	return g
+}
+
+// Widens stored floats back to float64
+func f64(v []float32) []float64 {
+	out := make([]float64, len(v))
+	for i := range v {
+		out[i] = float64(v[i])
+	}
+	return out
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// Two features of a service: latency and error rate, the last rows are outliers
+var ifTrain = [][]float64{
+	{1.0, 0.10}, {1.2, 0.12}, {0.9, 0.08}, {1.1, 0.11}, {1.05, 0.09}, {0.95, 0.10},
+	{1.15, 0.13}, {1.0, 0.07}, {0.85, 0.12}, {1.25, 0.10}, {1.1, 0.095}, {0.98, 0.105},
+	{4.0, 0.9}, {0.2, 0.7},
+}
+var ifInput = [][]float64{{1.02, 0.1}, {3.5, 0.8}, {1.3, 0.15}, {0.1, 0.9}, {0.95, 0.11}}
+
+const ifTrees = 6
+const ifMaxSamples = 10
+
+type iNode struct {
+	feature     int
+	threshold   float64
+	left, right int // -1 for leaves
+	depth       int
+	samples     int
+}
+
+// The average path length of an unsuccessful search in a binary search tree of n samples
+func averagePathLength(n float64) float64 {
+	switch {
+	case n <= 1:
+		return 0
+	case n == 2:
+		return 1
+	}
+	return 2*(math.Log(n-1)+0.5772156649015329) - 2*(n-1)/n
+}
+
+// Grows an isolation tree on rows of x, appending its nodes depth first
+func growTree(s *lcg, x [][]float64, rows []int, depth, maxDepth int, nodes *[]iNode) int {
+	id := len(*nodes)
+	*nodes = append(*nodes, iNode{left: -1, right: -1, depth: depth, samples: len(rows)})
+	if depth >= maxDepth || len(rows) <= 1 {
+		return id
+	}
+	// A random feature with different values, split uniformly between its extremes
+	features := []int{0, 1}
+	if s.next() > 0 {
+		features = []int{1, 0}
+	}
+	for _, f := range features {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, r := range rows {
+			lo, hi = min(lo, x[r][f]), max(hi, x[r][f])
+		}
+		if lo == hi {
+			continue
+		}
+		threshold := float64(float32(lo + (s.next()+1)/2*(hi-lo)))
+		var left, right []int
+		for _, r := range rows {
+			if x[r][f] <= threshold {
+				left = append(left, r)
+			} else {
+				right = append(right, r)
+			}
+		}
+		if len(left) == 0 || len(right) == 0 {
+			continue
+		}
+		(*nodes)[id].feature, (*nodes)[id].threshold = f, threshold
+		l := growTree(s, x, left, depth+1, maxDepth, nodes)
+		r := growTree(s, x, right, depth+1, maxDepth, nodes)
+		(*nodes)[id].left, (*nodes)[id].right = l, r
+		return id
+	}
+	return id
+}
+
+/*
+ * An IsolationForest with the graph skl2onnx gives it: every tree scores the features it was
+ * fitted on, its leaf index gives the depth and the number of samples of the leaf, and the mean
+ * path length gives score_samples, the decision_function and the label. The trees are grown here
+ * with lcg.
+ */
+func isolationForest() *ir.GraphProto {
+	s := lcg(5)
+	g := &ir.GraphProto{Name: "IsolationForest"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 2)}
+	euler := 0.5772156649015329
+	g.Initializer = []*ir.TensorProto{
+		floats("zero", []int64{1}, []float32{0}),
+		floats("one", []int64{1}, []float32{1}),
+		floats("onehalf", []int64{1}, []float32{1.5}),
+		floats("two", []int64{1}, []float32{2}),
+		floats("euler_gamma", []int64{1}, []float32{float32(euler)}),
+		floats("divisor", []int64{1}, []float32{float32(ifTrees * averagePathLength(ifMaxSamples))}),
+		floats("offset", []int64{1}, []float32{-0.5}),
+		ints("shape_column", []int64{2}, []int64{-1, 1}),
+		ints("shape_label", []int64{1}, []int64{-1}),
+		ints("inlier", []int64{1}, []int64{1}),
+		ints("outlier", []int64{1}, []int64{-1}),
+	}
+	maxDepth := int(math.Ceil(math.Log2(ifMaxSamples)))
+	paths := make([][]float64, len(ifInput))
+	for i := range paths {
+		paths[i] = make([]float64, ifTrees)
+	}
+	total := ""
+	for t := range ifTrees {
+		p := fmt.Sprint(t)
+		// Subsample without replacement, the features are permuted as in estimators_features_
+		perm := make([]int, len(ifTrain))
+		for i := range perm {
+			perm[i] = i
+		}
+		for i := len(perm) - 1; i > 0; i-- {
+			j := int((s.next() + 1) / 2 * float64(i+1))
+			perm[i], perm[j] = perm[j], perm[i]
+		}
+		rows := slices.Clone(perm[:ifMaxSamples])
+		features := []int64{0, 1}
+		if s.next() > 0 {
+			features = []int64{1, 0}
+		}
+		x := make([][]float64, len(ifTrain))
+		for i, r := range ifTrain {
+			x[i] = []float64{r[features[0]], r[features[1]]}
+		}
+		var nodes []iNode
+		growTree(&s, x, rows, 0, maxDepth, &nodes)
+
+		// Reference path lengths
+		for i, in := range ifInput {
+			xi := []float64{in[features[0]], in[features[1]]}
+			n := 0
+			for nodes[n].left >= 0 {
+				if float32(xi[nodes[n].feature]) <= float32(nodes[n].threshold) {
+					n = nodes[n].left
+				} else {
+					n = nodes[n].right
+				}
+			}
+			paths[i][t] = float64(nodes[n].depth) + averagePathLength(float64(nodes[n].samples))
+		}
+
+		tree := []*ir.AttributeProto{attrS("aggregate_function", "SUM"), attrI("n_targets", 1), attrS("post_transform", "NONE")}
+		var ids, treeIDs, featureIDs, trueIDs, falseIDs, targetNodes, targetTrees, targetIDs []int64
+		var values, weights, depths, samples []float32
+		var modes [][]byte
+		for id, n := range nodes {
+			ids = append(ids, int64(id))
+			treeIDs = append(treeIDs, 0)
+			depths = append(depths, float32(n.depth))
+			samples = append(samples, float32(n.samples))
+			if n.left < 0 {
+				featureIDs = append(featureIDs, 0)
+				values = append(values, 0)
+				modes = append(modes, []byte("LEAF"))
+				trueIDs = append(trueIDs, 0)
+				falseIDs = append(falseIDs, 0)
+				targetNodes = append(targetNodes, int64(id))
+				targetTrees = append(targetTrees, 0)
+				targetIDs = append(targetIDs, 0)
+				weights = append(weights, float32(id))
+				continue
+			}
+			featureIDs = append(featureIDs, int64(n.feature))
+			values = append(values, float32(n.threshold))
+			modes = append(modes, []byte("BRANCH_LEQ"))
+			trueIDs = append(trueIDs, int64(n.left))
+			falseIDs = append(falseIDs, int64(n.right))
+		}
+		tree = append(tree,
+			attrInts("nodes_nodeids", ids), attrInts("nodes_treeids", treeIDs), attrInts("nodes_featureids", featureIDs),
+			&ir.AttributeProto{Name: "nodes_values", Floats: values, Type: ir.AttributeProto_FLOATS},
+			&ir.AttributeProto{Name: "nodes_modes", Strings: modes, Type: ir.AttributeProto_STRINGS},
+			attrInts("nodes_truenodeids", trueIDs), attrInts("nodes_falsenodeids", falseIDs),
+			attrInts("target_nodeids", targetNodes), attrInts("target_treeids", targetTrees), attrInts("target_ids", targetIDs),
+			&ir.AttributeProto{Name: "target_weights", Floats: weights, Type: ir.AttributeProto_FLOATS},
+		)
+		g.Initializer = append(g.Initializer,
+			ints("features"+p, []int64{2}, features),
+			floats("node_depths"+p, []int64{int64(len(nodes))}, depths),
+			floats("node_samples"+p, []int64{int64(len(nodes))}, samples),
+		)
+		g.Node = append(g.Node,
+			node("Gather", "", []string{"X", "features" + p}, []string{"X_subset" + p}, attrI("axis", 1)),
+			node("TreeEnsembleRegressor", "ai.onnx.ml", []string{"X_subset" + p}, []string{"leaf" + p}, tree...),
+			node("Cast", "", []string{"leaf" + p}, []string{"leaf_index" + p}, attrI("to", int64(dt("INT64")))),
+			node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"node_depths" + p, "leaf_index" + p}, []string{"depth_row" + p}),
+			node("Reshape", "", []string{"depth_row" + p, "shape_column"}, []string{"depth" + p}),
+			node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"node_samples" + p, "leaf_index" + p}, []string{"samples_row" + p}),
+			node("Reshape", "", []string{"samples_row" + p, "shape_column"}, []string{"samples" + p}),
+			// The average path length of the samples left in the leaf
+			node("Less", "", []string{"samples" + p, "onehalf"}, []string{"single" + p}),
+			node("Equal", "", []string{"samples" + p, "two"}, []string{"pair" + p}),
+			node("Sub", "", []string{"samples" + p, "one"}, []string{"samples_minus_one" + p}),
+			node("Log", "", []string{"samples_minus_one" + p}, []string{"log" + p}),
+			node("Add", "", []string{"log" + p, "euler_gamma"}, []string{"harmonic" + p}),
+			node("Mul", "", []string{"harmonic" + p, "two"}, []string{"harmonic2" + p}),
+			node("Div", "", []string{"samples_minus_one" + p, "samples" + p}, []string{"ratio" + p}),
+			node("Mul", "", []string{"ratio" + p, "two"}, []string{"ratio2" + p}),
+			node("Sub", "", []string{"harmonic2" + p, "ratio2" + p}, []string{"average" + p}),
+			node("Where", "", []string{"pair" + p, "one", "average" + p}, []string{"average_pair" + p}),
+			node("Where", "", []string{"single" + p, "zero", "average_pair" + p}, []string{"average_path" + p}),
+			node("Add", "", []string{"depth" + p, "average_path" + p}, []string{"path" + p}),
+		)
+		if t == 0 {
+			total = "path" + p
+			continue
+		}
+		g.Node = append(g.Node, node("Add", "", []string{total, "path" + p}, []string{"total" + p}))
+		total = "total" + p
+	}
+	g.Node = append(g.Node,
+		node("Div", "", []string{total, "divisor"}, []string{"mean_ratio"}),
+		node("Neg", "", []string{"mean_ratio"}, []string{"exponent"}),
+		node("Pow", "", []string{"two", "exponent"}, []string{"power"}),
+		node("Neg", "", []string{"power"}, []string{"score_samples"}),
+		node("Sub", "", []string{"score_samples", "offset"}, []string{"scores"}),
+		node("Less", "", []string{"scores", "zero"}, []string{"is_outlier"}),
+		node("Where", "", []string{"is_outlier", "outlier", "inlier"}, []string{"label_column"}),
+		node("Reshape", "", []string{"label_column", "shape_label"}, []string{"label"}),
+	)
+	g.Output = []*ir.ValueInfoProto{tensorInfo("label", "INT64", -1), tensorInfo("scores", "FLOAT", -1, 1),
+		tensorInfo("score_samples", "FLOAT", -1, 1)}
+
+	var labels []int64
+	var decision, scoreSamples []float64
+	for _, p := range paths {
+		mean := 0.0
+		for _, v := range p {
+			mean += v / ifTrees
+		}
+		score := -math.Pow(2, -mean/averagePathLength(ifMaxSamples))
+		scoreSamples = append(scoreSamples, score)
+		decision = append(decision, score+0.5)
+		if score+0.5 < 0 {
+			labels = append(labels, -1)
+		} else {
+			labels = append(labels, 1)
+		}
+	}
+	fmt.Printf("IsolationForest labels %#v\ndecision_function %#v\nscore_samples %#v\n", labels, decision, scoreSamples)
+	return g
+}
@@ -0,0 +1,36 @@
+package main
+
+import "github.com/systemEng-Learning/go-ml-deployment/ir"
+
+// The graph skl2onnx gives KMeans: the squared distances to the centers as |x|² - 2 x.c + |c|²,
+// the label is the closest center and the scores the distances
+func kmeans(name string, centers [][]float32) *ir.GraphProto {
+	var flat, norms []float32
+	for _, c := range centers {
+		flat = append(flat, c...)
+		n := float32(0)
+		for _, v := range c {
+			n += v * v
+		}
+		norms = append(norms, n)
+	}
+	nc := int64(len(centers))
+	g := &ir.GraphProto{Name: name}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("Ad_Addcst", []int64{nc}, norms),
+		floats("Ge_Gemmcst", []int64{nc, 4}, flat),
+		floats("Mu_Mulcst", []int64{1}, []float32{0}),
+	}
+	g.Node = []*ir.NodeProto{
+		node("ReduceSumSquare", "", []string{"X"}, []string{"Re_reduced0"}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+		node("Mul", "", []string{"Re_reduced0", "Mu_Mulcst"}, []string{"Mu_C0"}),
+		node("Gemm", "", []string{"X", "Ge_Gemmcst", "Mu_C0"}, []string{"Ge_Y0"}, attrF("alpha", -2), attrI("transB", 1)),
+		node("Add", "", []string{"Re_reduced0", "Ge_Y0"}, []string{"Ad_C01"}),
+		node("Add", "", []string{"Ad_Addcst", "Ad_C01"}, []string{"Ad_C0"}),
+		node("ArgMin", "", []string{"Ad_C0"}, []string{"label"}, attrI("axis", 1), attrI("keepdims", 0)),
+		node("Sqrt", "", []string{"Ad_C0"}, []string{"scores"}),
+	}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("label", "INT64", -1), tensorInfo("scores", "FLOAT", -1, nc)}
+	return g
+}
@@ -0,0 +1,112 @@
+package main
+
+import "github.com/systemEng-Learning/go-ml-deployment/ir"
+
+// 15 iris samples, 5 of each species, the models keep them as their training set
+var knnTrain = [][]float32{
+	{5.1, 3.5, 1.4, 0.2}, {4.9, 3.0, 1.4, 0.2}, {4.7, 3.2, 1.3, 0.2}, {4.6, 3.1, 1.5, 0.2}, {5.0, 3.6, 1.4, 0.2},
+	{7.0, 3.2, 4.7, 1.4}, {6.4, 3.2, 4.5, 1.5}, {6.9, 3.1, 4.9, 1.5}, {5.5, 2.3, 4.0, 1.3}, {6.5, 2.8, 4.6, 1.5},
+	{6.3, 3.3, 6.0, 2.5}, {5.8, 2.7, 5.1, 1.9}, {7.1, 3.0, 5.9, 2.1}, {6.3, 2.9, 5.6, 1.8}, {6.5, 3.0, 5.8, 2.2},
+}
+var knnClasses = []int64{0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2}
+
+const knnK = 3
+
+// KNeighborsClassifier with the cdist optimisation, the neighbors vote for their class
+func knnClassifier() *ir.GraphProto {
+	var flat []float32
+	for _, r := range knnTrain {
+		flat = append(flat, r...)
+	}
+	g := &ir.GraphProto{Name: "KNN classifier"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("input", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("Tr", []int64{15, 4}, flat),
+		floats("Mu", []int64{1}, []float32{-1}),
+		ints("K", []int64{1}, []int64{knnK}),
+		ints("ArrayFeatureExtractorCst", []int64{15}, knnClasses),
+		ints("Shape", []int64{2}, []int64{-1, knnK}),
+		ints("Axis1", []int64{1}, []int64{1}),
+		ints("Classes", []int64{3}, []int64{0, 1, 2}),
+		ints("ShapeLabel", []int64{1}, []int64{-1}),
+	}
+	g.Node = []*ir.NodeProto{
+		node("CDist", "com.microsoft", []string{"input", "Tr"}, []string{"dist"}, attrS("metric", "euclidean")),
+		node("Mul", "", []string{"dist", "Mu"}, []string{"neg_dist"}),
+		node("TopK", "", []string{"neg_dist", "K"}, []string{"topk_values", "topk_indices"}, attrI("axis", -1), attrI("largest", 1), attrI("sorted", 1)),
+		node("Flatten", "", []string{"topk_indices"}, []string{"flattened"}),
+		node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"ArrayFeatureExtractorCst", "flattened"}, []string{"extracted"}),
+		node("Reshape", "", []string{"extracted", "Shape"}, []string{"reshaped"}),
+	}
+	var votes []string
+	for c := range 3 {
+		cst := []string{"C0", "C1", "C2"}[c]
+		g.Initializer = append(g.Initializer, ints(cst, []int64{1}, []int64{int64(c)}))
+		eq, cast, sum := "equal"+cst, "cast"+cst, "votes"+cst
+		g.Node = append(g.Node,
+			node("Equal", "", []string{"reshaped", cst}, []string{eq}),
+			node("Cast", "", []string{eq}, []string{cast}, attrI("to", int64(dt("FLOAT")))),
+			node("ReduceSum", "", []string{cast, "Axis1"}, []string{sum}, attrI("keepdims", 1)),
+		)
+		votes = append(votes, sum)
+	}
+	g.Node = append(g.Node,
+		node("Concat", "", votes, []string{"all_votes"}, attrI("axis", 1)),
+		node("ReduceSum", "", []string{"all_votes", "Axis1"}, []string{"sum_votes"}, attrI("keepdims", 1)),
+		node("Div", "", []string{"all_votes", "sum_votes"}, []string{"probabilities"}),
+		node("ArgMax", "", []string{"probabilities"}, []string{"best"}, attrI("axis", 1)),
+		node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"Classes", "best"}, []string{"labels"}),
+		node("Reshape", "", []string{"labels", "ShapeLabel"}, []string{"label"}),
+		node("ZipMap", "ai.onnx.ml", []string{"probabilities"}, []string{"output_probability"}, attrInts("classlabels_int64s", []int64{0, 1, 2})),
+	)
+	probType := &ir.TypeProto{Value: &ir.TypeProto_SequenceType{SequenceType: &ir.TypeProto_Sequence{
+		ElemType: &ir.TypeProto{Value: &ir.TypeProto_MapType{MapType: &ir.TypeProto_Map{
+			KeyType:   dt("INT64"),
+			ValueType: &ir.TypeProto{Value: &ir.TypeProto_TensorType{TensorType: &ir.TypeProto_Tensor{ElemType: dt("FLOAT")}}},
+		}}},
+	}}}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("label", "INT64", -1), {Name: "output_probability", Type: probType}}
+	return g
+}
+
+// KNeighborsRegressor without it, the distances are computed with MatMul
+func knnRegressor() *ir.GraphProto {
+	// Petal width from the other three features
+	trT := make([]float32, 3*15)
+	var norms, targets []float32
+	for i, r := range knnTrain {
+		n := float32(0)
+		for f := range 3 {
+			trT[f*15+i] = r[f]
+			n += r[f] * r[f]
+		}
+		norms = append(norms, n)
+		targets = append(targets, r[3])
+	}
+	g := &ir.GraphProto{Name: "KNN regressor"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("input", "FLOAT", -1, 3)}
+	g.Initializer = []*ir.TensorProto{
+		floats("TrT", []int64{3, 15}, trT),
+		floats("TrNorms", []int64{15}, norms),
+		floats("M2", []int64{1}, []float32{-2}),
+		floats("Mu", []int64{1}, []float32{-1}),
+		ints("K", []int64{1}, []int64{knnK}),
+		floats("ArrayFeatureExtractorCst", []int64{15}, targets),
+		ints("Shape", []int64{2}, []int64{-1, knnK}),
+	}
+	g.Node = []*ir.NodeProto{
+		node("ReduceSumSquare", "", []string{"input"}, []string{"input_norms"}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+		node("MatMul", "", []string{"input", "TrT"}, []string{"dots"}),
+		node("Mul", "", []string{"dots", "M2"}, []string{"dots2"}),
+		node("Add", "", []string{"input_norms", "dots2"}, []string{"partial"}),
+		node("Add", "", []string{"partial", "TrNorms"}, []string{"dist"}),
+		node("Mul", "", []string{"dist", "Mu"}, []string{"neg_dist"}),
+		node("TopK", "", []string{"neg_dist", "K"}, []string{"topk_values", "topk_indices"}),
+		node("Flatten", "", []string{"topk_indices"}, []string{"flattened"}),
+		node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"ArrayFeatureExtractorCst", "flattened"}, []string{"extracted"}),
+		node("Reshape", "", []string{"extracted", "Shape"}, []string{"reshaped"}),
+		node("ReduceMean", "", []string{"reshaped"}, []string{"variable"}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+	}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 1)}
+	return g
+}
@@ -0,0 +1,248 @@
+/*
+ * generate writes the example models the ops tests run. They aren't exported from scikit-learn:
+ * each graph is built here with the nodes skl2onnx gives the estimator, from parameters chosen or
+ * fitted here on a few iris samples. The expected outputs in the tests are the predictions of the
+ * estimator computed in float64 from the same parameters, some generators print them.
+ *
+ * Run from the root of the repository:
+ *
+ *	go run ./examples/generate
+ */
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"google.golang.org/protobuf/proto"
+)
+
+const producer = "go-ml-deployment"
+
+var examples = []struct {
+	file  string
+	graph func() *ir.GraphProto
+}{
+	{"knn_classifier.onnx", knnClassifier},
+	{"knn_regressor.onnx", knnRegressor},
+	{"kmeans.onnx", func() *ir.GraphProto {
+		return kmeans("KMeans", [][]float32{{4.86, 3.28, 1.4, 0.2}, {6.46, 2.92, 4.54, 1.44}, {6.4, 2.98, 5.68, 2.1}})
+	}},
+	{"minibatch_kmeans.onnx", func() *ir.GraphProto {
+		return kmeans("MiniBatchKMeans", [][]float32{{4.9, 3.3, 1.4, 0.2}, {6.4, 2.95, 5.1, 1.75}})
+	}},
+	{"gaussian_nb.onnx", gaussianNB},
+	{"multinomial_nb.onnx", func() *ir.GraphProto { return multinomialNB(false) }},
+	{"complement_nb.onnx", func() *ir.GraphProto { return multinomialNB(true) }},
+	{"bernoulli_nb.onnx", bernoulliNB},
+	{"categorical_nb.onnx", categoricalNB},
+	{"mlp_classifier.onnx", func() *ir.GraphProto {
+		return mlpClassifier("MLPClassifier", 350, func(s *lcg) []layer {
+			return []layer{newLayer(s, 4, 5, "Relu"), newLayer(s, 5, 3, "Tanh"), newLayer(s, 3, 3, "Softmax")}
+		}, false)
+	}},
+	{"mlp_binary_classifier.onnx", func() *ir.GraphProto {
+		return mlpClassifier("MLPBinaryClassifier", 259, func(s *lcg) []layer {
+			return []layer{newLayer(s, 4, 4, "Sigmoid"), newLayer(s, 4, 1, "Sigmoid")}
+		}, true)
+	}},
+	{"mlp_regressor.onnx", mlpRegressor},
+	{"isolation_forest.onnx", isolationForest},
+	{"gaussian_mixture.onnx", gaussianMixture},
+	{"bayesian_gaussian_mixture.onnx", bayesianGaussianMixture},
+	{"voting_classifier_soft.onnx", softVoting},
+	{"voting_classifier_hard.onnx", hardVoting},
+	{"stacking_classifier.onnx", stackingClassifier},
+	{"voting_regressor.onnx", votingRegressor},
+	{"stacking_regressor.onnx", stackingRegressor},
+	{"calibrated_sigmoid.onnx", calibratedSigmoid},
+	{"calibrated_isotonic.onnx", calibratedIsotonic},
+	{"calibrated_binary.onnx", calibratedBinary},
+	{"tuned_threshold.onnx", tunedThreshold},
+	{"pca.onnx", pca},
+	{"incremental_pca.onnx", incrementalPCA},
+	{"truncated_svd.onnx", truncatedSVD},
+	{"gaussian_random_projection.onnx", gaussianRandomProjection},
+	{"kernel_pca_rbf.onnx", func() *ir.GraphProto { return kernelPCA("rbf") }},
+	{"kernel_pca_poly.onnx", func() *ir.GraphProto { return kernelPCA("poly") }},
+	{"min_max_scaler.onnx", minMaxScaler},
+	{"max_abs_scaler.onnx", maxAbsScaler},
+	{"robust_scaler.onnx", robustScaler},
+	{"power_transformer_box_cox.onnx", func() *ir.GraphProto { return powerTransformer("box-cox") }},
+	{"power_transformer_yeo_johnson.onnx", func() *ir.GraphProto { return powerTransformer("yeo-johnson") }},
+	{"kbins_discretizer_onehot.onnx", func() *ir.GraphProto { return kbinsDiscretizer("uniform", "onehot") }},
+	{"kbins_discretizer_ordinal.onnx", func() *ir.GraphProto { return kbinsDiscretizer("quantile", "ordinal") }},
+	{"polynomial_features.onnx", polynomialFeatures},
+}
+
+// The iris samples the tests feed to the models fitted on iris
+var knnInputs = [][]float64{
+	{5.0, 3.4, 1.5, 0.2},
+	{6.1, 2.8, 4.7, 1.2},
+	{6.7, 3.1, 5.6, 2.4},
+	{6.0, 2.9, 4.5, 1.5},
+	{5.9, 3.0, 5.1, 1.8},
+}
+
+func main() {
+	for _, example := range examples {
+		save(filepath.Join("examples", example.file), model(example.graph()))
+	}
+}
+
+func dt(name string) int32 { return ir.TensorProto_DataType_value[name] }
+
+func floats(name string, dims []int64, v []float32) *ir.TensorProto {
+	return &ir.TensorProto{Name: name, Dims: dims, DataType: dt("FLOAT"), FloatData: v}
+}
+
+func ints(name string, dims []int64, v []int64) *ir.TensorProto {
+	return &ir.TensorProto{Name: name, Dims: dims, DataType: dt("INT64"), Int64Data: v}
+}
+
+func node(op, domain string, in, out []string, attrs ...*ir.AttributeProto) *ir.NodeProto {
+	return &ir.NodeProto{OpType: op, Domain: domain, Input: in, Output: out, Name: out[0] + "_" + op, Attribute: attrs}
+}
+
+func attrI(name string, v int64) *ir.AttributeProto {
+	return &ir.AttributeProto{Name: name, I: v, Type: ir.AttributeProto_INT}
+}
+
+func attrF(name string, v float32) *ir.AttributeProto {
+	return &ir.AttributeProto{Name: name, F: v, Type: ir.AttributeProto_FLOAT}
+}
+
+func attrInts(name string, v []int64) *ir.AttributeProto {
+	return &ir.AttributeProto{Name: name, Ints: v, Type: ir.AttributeProto_INTS}
+}
+
+func attrS(name, v string) *ir.AttributeProto {
+	return &ir.AttributeProto{Name: name, S: []byte(v), Type: ir.AttributeProto_STRING}
+}
+
+func attrFloats(name string, v []float32) *ir.AttributeProto {
+	return &ir.AttributeProto{Name: name, Floats: v, Type: ir.AttributeProto_FLOATS}
+}
+
+func attrStrings(name string, v []string) *ir.AttributeProto {
+	b := make([][]byte, len(v))
+	for i := range v {
+		b[i] = []byte(v[i])
+	}
+	return &ir.AttributeProto{Name: name, Strings: b, Type: ir.AttributeProto_STRINGS}
+}
+
+func strs(name string, dims []int64, v []string) *ir.TensorProto {
+	b := make([][]byte, len(v))
+	for i := range v {
+		b[i] = []byte(v[i])
+	}
+	return &ir.TensorProto{Name: name, Dims: dims, DataType: dt("STRING"), StringData: b}
+}
+
+// Rounds a parameter to the float it is stored as
+func r32(v float64) float64 { return float64(float32(v)) }
+
+// Describes a tensor of the given element type, a negative dimension is the batch size
+func tensorInfo(name, elem string, dims ...int64) *ir.ValueInfoProto {
+	shape := &ir.TensorShapeProto{}
+	for _, d := range dims {
+		if d < 0 {
+			shape.Dim = append(shape.Dim, &ir.TensorShapeProto_Dimension{Value: &ir.TensorShapeProto_Dimension_DimParam{DimParam: "N"}})
+		} else {
+			shape.Dim = append(shape.Dim, &ir.TensorShapeProto_Dimension{Value: &ir.TensorShapeProto_Dimension_DimValue{DimValue: d}})
+		}
+	}
+	return &ir.ValueInfoProto{Name: name, Type: &ir.TypeProto{Value: &ir.TypeProto_TensorType{
+		TensorType: &ir.TypeProto_Tensor{ElemType: dt(elem), Shape: shape}}}}
+}
+
+// Wraps a graph in a model importing opset 13 and the other domains its nodes use
+func model(g *ir.GraphProto) *ir.ModelProto {
+	m := &ir.ModelProto{IrVersion: 8, ProducerName: producer, Graph: g}
+	m.OpsetImport = []*ir.OperatorSetIdProto{{Domain: "", Version: 13}}
+	for _, domain := range []string{"ai.onnx.ml", "com.microsoft"} {
+		for _, n := range g.Node {
+			if n.Domain == domain {
+				m.OpsetImport = append(m.OpsetImport, &ir.OperatorSetIdProto{Domain: domain, Version: 1})
+				break
+			}
+		}
+	}
+	return m
+}
+
+// The outputs of a classifier of the classes 0 to nc-1: the label, the class of the highest column
+// of scores, and the probabilities as a ZipMap, as skl2onnx writes them
+func classifierOutputs(g *ir.GraphProto, scores, probabilities string, nc int) {
+	classes := make([]int64, nc)
+	for c := range classes {
+		classes[c] = int64(c)
+	}
+	g.Initializer = append(g.Initializer,
+		ints("classes", []int64{int64(nc)}, classes),
+		ints("shape_tensor", []int64{1}, []int64{-1}),
+	)
+	g.Node = append(g.Node,
+		node("ArgMax", "", []string{scores}, []string{"argmax_output"}, attrI("axis", 1)),
+		node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"classes", "argmax_output"}, []string{"array_feature_extractor_result"}),
+		node("Reshape", "", []string{"array_feature_extractor_result", "shape_tensor"}, []string{"label"}),
+		node("ZipMap", "ai.onnx.ml", []string{probabilities}, []string{"output_probability"}, attrInts("classlabels_int64s", classes)),
+	)
+	probType := &ir.TypeProto{Value: &ir.TypeProto_SequenceType{SequenceType: &ir.TypeProto_Sequence{
+		ElemType: &ir.TypeProto{Value: &ir.TypeProto_MapType{MapType: &ir.TypeProto_Map{
+			KeyType:   dt("INT64"),
+			ValueType: &ir.TypeProto{Value: &ir.TypeProto_TensorType{TensorType: &ir.TypeProto_Tensor{ElemType: dt("FLOAT")}}},
+		}}},
+	}}}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("label", "INT64", -1), {Name: "output_probability", Type: probType}}
+}
+
+func save(path string, m *ir.ModelProto) {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// The initializers are floats, the parameters are computed in float64
+func f32(v []float64) []float32 {
+	out := make([]float32, len(v))
+	for i := range v {
+		out[i] = float32(v[i])
+	}
+	return out
+}
+
+// Flattens a [rows][cols] matrix transposed to [cols, rows]
+func transposed(m [][]float64) []float32 {
+	out := make([]float32, 0, len(m)*len(m[0]))
+	for j := range m[0] {
+		for i := range m {
+			out = append(out, float32(m[i][j]))
+		}
+	}
+	return out
+}
+
+// Flattens a [rows][cols] matrix in row-major order
+func flat(m [][]float64) []float32 {
+	var out []float32
+	for _, r := range m {
+		out = append(out, f32(r)...)
+	}
+	return out
+}
+
+func dot(a, b []float64) float64 {
+	s := 0.0
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
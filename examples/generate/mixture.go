@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// The lower cholesky factor of a symmetric positive definite matrix
+func cholesky(a [][]float64) [][]float64 {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+	}
+	for i := range n {
+		for j := 0; j <= i; j++ {
+			s := a[i][j]
+			for k := range j {
+				s -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				l[i][i] = math.Sqrt(s)
+			} else {
+				l[i][j] = s / l[j][j]
+			}
+		}
+	}
+	return l
+}
+
+// Inverse of a lower triangular matrix
+func invLower(l [][]float64) [][]float64 {
+	n := len(l)
+	inv := make([][]float64, n)
+	for i := range inv {
+		inv[i] = make([]float64, n)
+	}
+	for j := range n {
+		inv[j][j] = 1 / l[j][j]
+		for i := j + 1; i < n; i++ {
+			s := 0.0
+			for k := j; k < i; k++ {
+				s -= l[i][k] * inv[k][j]
+			}
+			inv[i][j] = s / l[i][i]
+		}
+	}
+	return inv
+}
+
+// The digamma function, by the recurrence up to 6 and the asymptotic series from there
+func digamma(x float64) float64 {
+	r := 0.0
+	for x < 6 {
+		r -= 1 / x
+		x++
+	}
+	f := 1 / (x * x)
+	return r + math.Log(x) - 0.5/x - f*(1.0/12-f*(1.0/120-f*(1.0/252-f*(1.0/240-f/132))))
+}
+
+// Means and covariances of the iris samples of each class, one M step from the class labels
+func irisComponents(diag bool) (means [][]float64, covs [][][]float64, weights []float64) {
+	const reg = 1e-6
+	for c := range 3 {
+		var rows [][]float64
+		for i, r := range knnTrain {
+			if knnClasses[i] == int64(c) {
+				x := make([]float64, 4)
+				for f := range x {
+					x[f] = float64(r[f])
+				}
+				rows = append(rows, x)
+			}
+		}
+		mean := make([]float64, 4)
+		for _, r := range rows {
+			for f := range r {
+				mean[f] += r[f] / float64(len(rows))
+			}
+		}
+		cov := make([][]float64, 4)
+		for i := range cov {
+			cov[i] = make([]float64, 4)
+			for j := range cov[i] {
+				if diag && i != j {
+					continue
+				}
+				for _, r := range rows {
+					cov[i][j] += (r[i] - mean[i]) * (r[j] - mean[j]) / float64(len(rows))
+				}
+			}
+			cov[i][i] += reg
+		}
+		means = append(means, mean)
+		covs = append(covs, cov)
+		weights = append(weights, float64(len(rows))/float64(len(knnTrain)))
+	}
+	return
+}
+
+// The label, predict_proba and score_samples of a mixture from its weighted log probabilities
+func mixtureOutputs(g *ir.GraphProto, weighted string, nc int64) {
+	g.Node = append(g.Node,
+		node("ReduceLogSumExp", "", []string{weighted}, []string{"score_samples"}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+		node("Sub", "", []string{weighted, "score_samples"}, []string{"log_resp"}),
+		node("Exp", "", []string{"log_resp"}, []string{"probabilities"}),
+		node("ArgMax", "", []string{weighted}, []string{"label"}, attrI("axis", 1), attrI("keepdims", 0)),
+	)
+	g.Output = []*ir.ValueInfoProto{
+		tensorInfo("label", "INT64", -1),
+		tensorInfo("probabilities", "FLOAT", -1, nc),
+		tensorInfo("score_samples", "FLOAT", -1, 1),
+	}
+}
+
+func printMixture(name string, weighted [][]float64) {
+	labels, probs := normalise(weighted)
+	scores := make([]float64, len(weighted))
+	for i, r := range weighted {
+		m := math.Inf(-1)
+		for _, v := range r {
+			m = max(m, v)
+		}
+		s := 0.0
+		for _, v := range r {
+			s += math.Exp(v - m)
+		}
+		scores[i] = m + math.Log(s)
+	}
+	fmt.Printf("%s labels %#v\n%s predict_proba %#v\n%s score_samples %#v\n", name, labels, name, probs, name, scores)
+}
+
+// covariance_type="full": each component multiplies the centred input by its precision cholesky
+func gaussianMixture() *ir.GraphProto {
+	means, covs, weights := irisComponents(false)
+	g := &ir.GraphProto{Name: "GaussianMixture"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	logDet := make([]float64, 3)
+	logWeights := make([]float64, 3)
+	var logProbs []string
+	weighted := make([][]float64, len(knnInputs))
+	for i := range weighted {
+		weighted[i] = make([]float64, 3)
+	}
+	for c := range 3 {
+		p := fmt.Sprint(c)
+		inv := invLower(cholesky(covs[c]))
+		precChol := make([][]float64, 4) // transpose of inv
+		for i := range precChol {
+			precChol[i] = make([]float64, 4)
+			for j := range precChol[i] {
+				precChol[i][j] = float64(float32(inv[j][i]))
+			}
+			logDet[c] += math.Log(precChol[i][i])
+		}
+		logWeights[c] = math.Log(weights[c])
+		muPrec := make([]float64, 4)
+		for j := range 4 {
+			for i := range 4 {
+				muPrec[j] += float64(float32(means[c][i])) * precChol[i][j]
+			}
+		}
+		g.Initializer = append(g.Initializer,
+			floats("precisions_chol"+p, []int64{4, 4}, flat(precChol)),
+			floats("means_prec"+p, []int64{4}, f32(muPrec)),
+		)
+		g.Node = append(g.Node,
+			node("MatMul", "", []string{"X", "precisions_chol" + p}, []string{"y_prec" + p}),
+			node("Sub", "", []string{"y_prec" + p, "means_prec" + p}, []string{"y" + p}),
+			node("ReduceSumSquare", "", []string{"y" + p}, []string{"log_prob" + p}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+		)
+		logProbs = append(logProbs, "log_prob"+p)
+		for i, x := range knnInputs {
+			s := 0.0
+			for j := range 4 {
+				y := -muPrec[j]
+				for k := range 4 {
+					y += x[k] * precChol[k][j]
+				}
+				s += y * y
+			}
+			weighted[i][c] = -0.5*(4*math.Log(2*math.Pi)+s) + logDet[c] + logWeights[c]
+		}
+	}
+	g.Initializer = append(g.Initializer,
+		floats("log_2pi", []int64{1}, []float32{float32(4 * math.Log(2*math.Pi))}),
+		floats("minus_half", []int64{1}, []float32{-0.5}),
+		floats("log_det", []int64{3}, f32(logDet)),
+		floats("log_weights", []int64{3}, f32(logWeights)),
+	)
+	g.Node = append(g.Node,
+		node("Concat", "", logProbs, []string{"log_prob"}, attrI("axis", 1)),
+		node("Add", "", []string{"log_prob", "log_2pi"}, []string{"log_prob_2pi"}),
+		node("Mul", "", []string{"log_prob_2pi", "minus_half"}, []string{"log_gauss_centred"}),
+		node("Add", "", []string{"log_gauss_centred", "log_det"}, []string{"log_gauss"}),
+		node("Add", "", []string{"log_gauss", "log_weights"}, []string{"weighted_log_prob"}),
+	)
+	mixtureOutputs(g, "weighted_log_prob", 3)
+	printMixture("GaussianMixture", weighted)
+	return g
+}
+
+// covariance_type="diag" with a dirichlet_process prior: the log weights and the corrections of
+// the log probabilities are constants of the fitted model
+func bayesianGaussianMixture() *ir.GraphProto {
+	means, covs, weights := irisComponents(true)
+	const F = 4
+	g := &ir.GraphProto{Name: "BayesianGaussianMixture"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	precisions := make([][]float64, 3)
+	meansPrec := make([][]float64, 3)
+	offsets := make([]float64, 3) // everything that doesn't depend on X
+	remaining := 1.0
+	for c := range 3 {
+		precisions[c] = make([]float64, F)
+		meansPrec[c] = make([]float64, F)
+		nk := weights[c] * float64(len(knnTrain))
+		dof := float64(F) + nk
+		meanPrecision := 1 + nk
+		logDet, sqMeans := 0.0, 0.0
+		for f := range F {
+			chol := float64(float32(1 / math.Sqrt(covs[c][f][f])))
+			precisions[c][f] = float64(float32(chol * chol))
+			meansPrec[c][f] = float64(float32(means[c][f] * precisions[c][f]))
+			logDet += math.Log(chol)
+			sqMeans += means[c][f] * means[c][f] * precisions[c][f]
+		}
+		// Stick breaking weights of the dirichlet process
+		a, b := 1+nk, 1.0/3+float64(len(knnTrain))*(remaining-weights[c])
+		remaining -= weights[c]
+		logWeight := digamma(a) - digamma(a+b)
+		for k := range c {
+			nj := weights[k] * float64(len(knnTrain))
+			aj, bj := 1+nj, 1.0/3+float64(len(knnTrain))*(1-sumTo(weights, k+1))
+			logWeight += digamma(bj) - digamma(aj+bj)
+		}
+		logLambda := float64(F) * math.Log(2)
+		for f := range F {
+			logLambda += digamma(0.5 * (dof - float64(f)))
+		}
+		offsets[c] = -0.5*(float64(F)*math.Log(2*math.Pi)+sqMeans) + logDet - 0.5*float64(F)*math.Log(dof) +
+			0.5*(logLambda-float64(F)/meanPrecision) + logWeight
+	}
+	g.Initializer = []*ir.TensorProto{
+		floats("precisions", []int64{F, 3}, transposed(precisions)),
+		floats("means_prec", []int64{F, 3}, transposed(meansPrec)),
+		floats("minus_two", []int64{1}, []float32{-2}),
+		floats("minus_half", []int64{1}, []float32{-0.5}),
+		floats("offsets", []int64{3}, f32(offsets)),
+	}
+	g.Node = []*ir.NodeProto{
+		node("Mul", "", []string{"X", "X"}, []string{"X_squared"}),
+		node("MatMul", "", []string{"X_squared", "precisions"}, []string{"squared_prec"}),
+		node("MatMul", "", []string{"X", "means_prec"}, []string{"cross"}),
+		node("Mul", "", []string{"cross", "minus_two"}, []string{"cross2"}),
+		node("Add", "", []string{"squared_prec", "cross2"}, []string{"log_prob"}),
+		node("Mul", "", []string{"log_prob", "minus_half"}, []string{"log_gauss_centred"}),
+		node("Add", "", []string{"log_gauss_centred", "offsets"}, []string{"weighted_log_prob"}),
+	}
+	mixtureOutputs(g, "weighted_log_prob", 3)
+
+	weighted := make([][]float64, len(knnInputs))
+	for i, x := range knnInputs {
+		weighted[i] = make([]float64, 3)
+		for c := range 3 {
+			s := 0.0
+			for f := range F {
+				s += x[f]*x[f]*precisions[c][f] - 2*x[f]*meansPrec[c][f]
+			}
+			weighted[i][c] = -0.5*s + offsets[c]
+		}
+	}
+	printMixture("BayesianGaussianMixture", weighted)
+	return g
+}
+
+// The sum of the first n values of v
+func sumTo(v []float64, n int) float64 {
+	s := 0.0
+	for _, x := range v[:n] {
+		s += x
+	}
+	return s
+}
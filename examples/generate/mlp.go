@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// Deterministic weights in [-1, 1)
+type lcg uint64
+
+func (s *lcg) next() float64 {
+	*s = *s*6364136223846793005 + 1442695040888963407
+	return float64(*s>>11)/float64(1<<53)*2 - 1
+}
+
+type layer struct {
+	coef       [][]float64 // [in][out]
+	intercept  []float64
+	activation string
+}
+
+func newLayer(s *lcg, in, out int, activation string) layer {
+	l := layer{activation: activation, intercept: make([]float64, out)}
+	l.coef = make([][]float64, in)
+	for i := range l.coef {
+		l.coef[i] = make([]float64, out)
+		for j := range l.coef[i] {
+			l.coef[i][j] = math.Round(s.next()*1000) / 1000
+		}
+	}
+	for j := range l.intercept {
+		l.intercept[j] = math.Round(s.next()*1000) / 1000
+	}
+	return l
+}
+
+func (l layer) forward(x []float64) []float64 {
+	out := make([]float64, len(l.intercept))
+	for j := range out {
+		out[j] = l.intercept[j]
+		for i, v := range x {
+			out[j] += v * float64(float32(l.coef[i][j]))
+		}
+	}
+	switch l.activation {
+	case "Relu":
+		for j := range out {
+			out[j] = max(out[j], 0)
+		}
+	case "Tanh":
+		for j := range out {
+			out[j] = math.Tanh(out[j])
+		}
+	case "Sigmoid":
+		for j := range out {
+			out[j] = 1 / (1 + math.Exp(-out[j]))
+		}
+	case "Softmax":
+		m, s := math.Inf(-1), 0.0
+		for _, v := range out {
+			m = max(m, v)
+		}
+		for j := range out {
+			out[j] = math.Exp(out[j] - m)
+			s += out[j]
+		}
+		for j := range out {
+			out[j] /= s
+		}
+	}
+	return out
+}
+
+// The MatMul, Add and activation nodes of the layers, as skl2onnx writes them
+func mlpNodes(g *ir.GraphProto, input string, layers []layer) string {
+	for i, l := range layers {
+		p := fmt.Sprint(i)
+		g.Initializer = append(g.Initializer,
+			floats("coefficient"+p, []int64{int64(len(l.coef)), int64(len(l.intercept))}, flat(l.coef)),
+			floats("intercepts"+p, []int64{1, int64(len(l.intercept))}, f32(l.intercept)),
+		)
+		g.Node = append(g.Node,
+			node("MatMul", "", []string{input, "coefficient" + p}, []string{"mul_result" + p}),
+			node("Add", "", []string{"mul_result" + p, "intercepts" + p}, []string{"add_result" + p}),
+		)
+		input = "add_result" + p
+		if l.activation != "" {
+			g.Node = append(g.Node, node(l.activation, "", []string{input}, []string{"next_activations" + p}))
+			input = "next_activations" + p
+		}
+	}
+	return input
+}
+
+func forward(layers []layer, x []float64) []float64 {
+	for _, l := range layers {
+		x = l.forward(x)
+	}
+	return x
+}
+
+// An MLPClassifier with the given layers, the weights are drawn from seed. The output of a
+// binary classifier is the probability of class 1.
+func mlpClassifier(name string, seed lcg, layers func(*lcg) []layer, binary bool) *ir.GraphProto {
+	s := &seed
+	ls := layers(s)
+	g := &ir.GraphProto{Name: name}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("input", "FLOAT", -1, 4)}
+	out := mlpNodes(g, "input", ls)
+	nc := 3
+	if binary {
+		nc = 2
+		g.Initializer = append(g.Initializer, floats("unity", []int64{1}, []float32{1}))
+		g.Node = append(g.Node,
+			node("Sub", "", []string{"unity", out}, []string{"negative_class_proba"}),
+			node("Concat", "", []string{"negative_class_proba", out}, []string{"probabilities_concat"}, attrI("axis", 1)),
+		)
+		out = "probabilities_concat"
+	}
+	classifierOutputs(g, out, out, nc)
+
+	labels := make([]int64, len(knnInputs))
+	probs := make([][]float64, len(knnInputs))
+	for i, x := range knnInputs {
+		p := forward(ls, x)
+		if binary {
+			p = []float64{1 - p[0], p[0]}
+		}
+		probs[i] = p
+		for c := range p {
+			if p[c] > p[labels[i]] {
+				labels[i] = int64(c)
+			}
+		}
+	}
+	fmt.Printf("%s labels %#v\n%s probabilities %#v\n", name, labels, name, probs)
+	return g
+}
+
+// An MLPRegressor predicting 2 values from the first 3 iris features
+func mlpRegressor() *ir.GraphProto {
+	s := lcg(7)
+	ls := []layer{newLayer(&s, 3, 6, "Relu"), newLayer(&s, 6, 2, "")}
+	g := &ir.GraphProto{Name: "MLPRegressor"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("input", "FLOAT", -1, 3)}
+	out := mlpNodes(g, "input", ls)
+	g.Node = append(g.Node, node("Identity", "", []string{out}, []string{"variable"}))
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 2)}
+	predictions := make([][]float64, len(knnInputs))
+	for i, x := range knnInputs {
+		predictions[i] = forward(ls, x[:3])
+	}
+	fmt.Printf("MLPRegressor predictions %#v\n", predictions)
+	return g
+}
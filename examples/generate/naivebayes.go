@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// Word counts of "free", "win", "meeting" and "report"; 1 is spam
+var nbCounts = [][]float64{
+	{0, 0, 2, 3}, {1, 0, 3, 1}, {0, 1, 2, 2}, {0, 0, 1, 4},
+	{3, 2, 0, 0}, {2, 1, 0, 1}, {4, 3, 1, 0}, {1, 2, 0, 0},
+}
+var nbSpam = []int{0, 0, 0, 0, 1, 1, 1, 1}
+var nbCountInput = [][]float64{{2, 1, 0, 0}, {0, 0, 3, 2}, {1, 2, 1, 0}, {0, 1, 2, 3}}
+
+// Three categorical features with 3, 2 and 4 categories
+var nbCategories = [][]int64{
+	{0, 1, 3}, {0, 0, 2}, {1, 1, 3}, {0, 1, 1}, {2, 0, 0},
+	{2, 0, 1}, {1, 0, 0}, {2, 1, 2}, {2, 0, 0}, {1, 0, 1},
+}
+var nbCategoryClasses = []int{0, 0, 0, 0, 0, 1, 1, 1, 1, 1}
+var nbCategoryCounts = []int{3, 2, 4}
+var nbCategoryInput = [][]int64{{0, 1, 3}, {2, 0, 0}, {1, 1, 2}, {1, 0, 1}}
+
+func priors(y []int, nc int) ([]float64, []float64) {
+	count := make([]float64, nc)
+	for _, c := range y {
+		count[c]++
+	}
+	prior := make([]float64, nc)
+	for c := range count {
+		prior[c] = math.Log(count[c] / float64(len(y)))
+	}
+	return count, prior
+}
+
+func featureCounts(x [][]float64, y []int, nc int) [][]float64 {
+	fc := make([][]float64, nc)
+	for c := range fc {
+		fc[c] = make([]float64, len(x[0]))
+	}
+	for i, r := range x {
+		for f, v := range r {
+			fc[y[i]][f] += v
+		}
+	}
+	return fc
+}
+
+// Normalises joint log likelihoods into labels and probabilities like predict_proba
+func normalise(jll [][]float64) ([]int64, [][]float64) {
+	labels := make([]int64, len(jll))
+	probs := make([][]float64, len(jll))
+	for i, r := range jll {
+		m := math.Inf(-1)
+		for c, v := range r {
+			if v > r[labels[i]] {
+				labels[i] = int64(c)
+			}
+			m = max(m, v)
+		}
+		s := 0.0
+		for _, v := range r {
+			s += math.Exp(v - m)
+		}
+		lse := m + math.Log(s)
+		probs[i] = make([]float64, len(r))
+		for c, v := range r {
+			probs[i][c] = math.Exp(v - lse)
+		}
+	}
+	return labels, probs
+}
+
+func printReference(name string, jll [][]float64) {
+	labels, probs := normalise(jll)
+	fmt.Printf("%s labels %#v\n%s probabilities %#v\n", name, labels, name, probs)
+}
+
+// The nodes that turn the joint log likelihood into a label and probabilities, as in skl2onnx
+func nbOutputs(g *ir.GraphProto, nc int) {
+	g.Node = append(g.Node,
+		node("ReduceLogSumExp", "", []string{"jll"}, []string{"log_prob_x"}, attrInts("axes", []int64{1}), attrI("keepdims", 1)),
+		node("Sub", "", []string{"jll", "log_prob_x"}, []string{"log_prob"}),
+		node("Exp", "", []string{"log_prob"}, []string{"probabilities"}),
+	)
+	classifierOutputs(g, "jll", "probabilities", nc)
+}
+
+func multinomialNB(complement bool) *ir.GraphProto {
+	fc := featureCounts(nbCounts, nbSpam, 2)
+	_, prior := priors(nbSpam, 2)
+	flp := make([][]float64, 2)
+	for c := range fc {
+		flp[c] = make([]float64, 4)
+		if complement {
+			// norm=False: the log of the normalised complement counts, negated
+			comp := make([]float64, 4)
+			total := 0.0
+			for f := range comp {
+				comp[f] = fc[1-c][f] + 1
+				total += comp[f]
+			}
+			for f := range comp {
+				flp[c][f] = -math.Log(comp[f] / total)
+			}
+		} else {
+			total := 0.0
+			for _, v := range fc[c] {
+				total += v + 1
+			}
+			for f, v := range fc[c] {
+				flp[c][f] = math.Log((v + 1) / total)
+			}
+		}
+	}
+	name := "MultinomialNB"
+	if complement {
+		name = "ComplementNB"
+	}
+	jll := make([][]float64, len(nbCountInput))
+	for i, x := range nbCountInput {
+		jll[i] = make([]float64, 2)
+		for c := range 2 {
+			jll[i][c] = dot(x, flp[c])
+			if !complement {
+				jll[i][c] += prior[c]
+			}
+		}
+	}
+	printReference(name, jll)
+
+	g := &ir.GraphProto{Name: name}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("input", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{floats("feature_log_prob", []int64{4, 2}, transposed(flp))}
+	if complement {
+		g.Node = []*ir.NodeProto{node("MatMul", "", []string{"input", "feature_log_prob"}, []string{"jll"})}
+	} else {
+		g.Initializer = append(g.Initializer, floats("class_log_prior", []int64{2}, f32(prior)))
+		g.Node = []*ir.NodeProto{
+			node("MatMul", "", []string{"input", "feature_log_prob"}, []string{"matmul_result"}),
+			node("Add", "", []string{"matmul_result", "class_log_prior"}, []string{"jll"}),
+		}
+	}
+	nbOutputs(g, 2)
+	return g
+}
+
+func bernoulliNB() *ir.GraphProto {
+	binary := make([][]float64, len(nbCounts))
+	for i, r := range nbCounts {
+		binary[i] = make([]float64, 4)
+		for f, v := range r {
+			if v > 0 {
+				binary[i][f] = 1
+			}
+		}
+	}
+	fc := featureCounts(binary, nbSpam, 2)
+	count, prior := priors(nbSpam, 2)
+	flp := make([][]float64, 2)
+	neg := make([][]float64, 2)
+	for c := range fc {
+		flp[c] = make([]float64, 4)
+		neg[c] = make([]float64, 4)
+		for f, v := range fc[c] {
+			flp[c][f] = math.Log(v+1) - math.Log(count[c]+2)
+			neg[c][f] = math.Log(1 - math.Exp(flp[c][f]))
+		}
+	}
+	jll := make([][]float64, len(nbCountInput))
+	for i, x := range nbCountInput {
+		jll[i] = make([]float64, 2)
+		for c := range 2 {
+			jll[i][c] = prior[c]
+			for f, v := range x {
+				if v > 0 {
+					jll[i][c] += flp[c][f]
+				} else {
+					jll[i][c] += neg[c][f]
+				}
+			}
+		}
+	}
+	printReference("BernoulliNB", jll)
+
+	g := &ir.GraphProto{Name: "BernoulliNB"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("input", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("feature_log_prob", []int64{4, 2}, transposed(flp)),
+		floats("class_log_prior", []int64{2}, f32(prior)),
+		floats("constant", []int64{1}, []float32{1}),
+		floats("threshold", []int64{1}, []float32{0}),
+		ints("axis", []int64{1}, []int64{0}),
+	}
+	g.Node = []*ir.NodeProto{
+		node("Greater", "", []string{"input", "threshold"}, []string{"condition"}),
+		node("Cast", "", []string{"condition"}, []string{"binarised_input"}, attrI("to", int64(dt("FLOAT")))),
+		node("Exp", "", []string{"feature_log_prob"}, []string{"exp_result"}),
+		node("Sub", "", []string{"constant", "exp_result"}, []string{"sub_result"}),
+		node("Log", "", []string{"sub_result"}, []string{"neg_prob"}),
+		node("Sub", "", []string{"feature_log_prob", "neg_prob"}, []string{"difference_matrix"}),
+		node("MatMul", "", []string{"binarised_input", "difference_matrix"}, []string{"dot_product"}),
+		node("ReduceSum", "", []string{"neg_prob", "axis"}, []string{"sum_neg_prob"}, attrI("keepdims", 0)),
+		node("Add", "", []string{"dot_product", "sum_neg_prob"}, []string{"partial_sum_result"}),
+		node("Add", "", []string{"partial_sum_result", "class_log_prior"}, []string{"jll"}),
+	}
+	nbOutputs(g, 2)
+	return g
+}
+
+func gaussianNB() *ir.GraphProto {
+	x := make([][]float64, len(knnTrain))
+	for i, r := range knnTrain {
+		x[i] = make([]float64, 4)
+		for f, v := range r {
+			x[i][f] = float64(v)
+		}
+	}
+	y := make([]int, len(knnClasses))
+	for i, c := range knnClasses {
+		y[i] = int(c)
+	}
+	count, prior := priors(y, 3)
+	theta := featureCounts(x, y, 3)
+	for c := range theta {
+		for f := range theta[c] {
+			theta[c][f] /= count[c]
+		}
+	}
+	// var_smoothing is 1e-9 of the largest variance of the features
+	largest := 0.0
+	for f := range 4 {
+		mean, v := 0.0, 0.0
+		for _, r := range x {
+			mean += r[f] / float64(len(x))
+		}
+		for _, r := range x {
+			v += (r[f] - mean) * (r[f] - mean) / float64(len(x))
+		}
+		largest = max(largest, v)
+	}
+	variance := make([][]float64, 3)
+	sigmaSumLog := make([]float64, 3)
+	for c := range variance {
+		variance[c] = make([]float64, 4)
+		for i, r := range x {
+			if y[i] != c {
+				continue
+			}
+			for f, v := range r {
+				variance[c][f] += (v - theta[c][f]) * (v - theta[c][f]) / count[c]
+			}
+		}
+		for f := range variance[c] {
+			variance[c][f] += 1e-9 * largest
+			sigmaSumLog[c] -= 0.5 * math.Log(2*math.Pi*variance[c][f])
+		}
+	}
+	jll := make([][]float64, len(knnInputs))
+	for i, in := range knnInputs {
+		jll[i] = make([]float64, 3)
+		for c := range 3 {
+			s := 0.0
+			for f := range in {
+				d := in[f] - theta[c][f]
+				s += d * d / variance[c][f]
+			}
+			jll[i][c] = prior[c] + sigmaSumLog[c] - 0.5*s
+		}
+	}
+	printReference("GaussianNB", jll)
+
+	g := &ir.GraphProto{Name: "GaussianNB"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("input", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("theta", []int64{3, 4}, flat(theta)),
+		floats("sigma", []int64{3, 4}, flat(variance)),
+		floats("jointi", []int64{3}, f32(prior)),
+		floats("sigma_sum_log", []int64{3}, f32(sigmaSumLog)),
+		floats("exponent", []int64{1}, []float32{2}),
+		floats("prod_operand", []int64{1}, []float32{0.5}),
+		ints("reshaped_shape", []int64{3}, []int64{-1, 1, 4}),
+	}
+	g.Node = []*ir.NodeProto{
+		node("Reshape", "", []string{"input", "reshaped_shape"}, []string{"reshaped_input"}),
+		node("Sub", "", []string{"reshaped_input", "theta"}, []string{"subtracted_input"}),
+		node("Pow", "", []string{"subtracted_input", "exponent"}, []string{"pow_result"}),
+		node("Div", "", []string{"pow_result", "sigma"}, []string{"div_result"}),
+		node("ReduceSum", "", []string{"div_result"}, []string{"reduced_sum"}, attrInts("axes", []int64{2}), attrI("keepdims", 0)),
+		node("Mul", "", []string{"reduced_sum", "prod_operand"}, []string{"mul_result"}),
+		node("Sub", "", []string{"sigma_sum_log", "mul_result"}, []string{"subtracted_result"}),
+		node("Add", "", []string{"subtracted_result", "jointi"}, []string{"jll"}),
+	}
+	nbOutputs(g, 3)
+	return g
+}
+
+func categoricalNB() *ir.GraphProto {
+	count, prior := priors(nbCategoryClasses, 2)
+	flp := make([][][]float64, len(nbCategoryCounts)) // [feature][class][category]
+	for f, n := range nbCategoryCounts {
+		flp[f] = make([][]float64, 2)
+		for c := range 2 {
+			flp[f][c] = make([]float64, n)
+			for i, r := range nbCategories {
+				if nbCategoryClasses[i] == c {
+					flp[f][c][r[f]]++
+				}
+			}
+			for k := range flp[f][c] {
+				flp[f][c][k] = math.Log((flp[f][c][k] + 1) / (count[c] + float64(n)))
+			}
+		}
+	}
+	jll := make([][]float64, len(nbCategoryInput))
+	for i, x := range nbCategoryInput {
+		jll[i] = make([]float64, 2)
+		for c := range 2 {
+			jll[i][c] = prior[c]
+			for f, v := range x {
+				jll[i][c] += flp[f][c][v]
+			}
+		}
+	}
+	printReference("CategoricalNB", jll)
+
+	g := &ir.GraphProto{Name: "CategoricalNB"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("input", "INT64", -1, 3)}
+	g.Initializer = []*ir.TensorProto{floats("class_log_prior", []int64{2}, f32(prior))}
+	// The log probability of the category of each feature is picked by a one-hot encoding of it
+	sum := ""
+	for f, n := range nbCategoryCounts {
+		categories := make([]int64, n)
+		for k := range categories {
+			categories[k] = int64(k)
+		}
+		p := fmt.Sprint(f)
+		g.Initializer = append(g.Initializer,
+			ints("feature"+p, []int64{1}, []int64{int64(f)}),
+			ints("categories"+p, []int64{int64(n)}, categories),
+			floats("feature_log_prob"+p, []int64{int64(n), 2}, transposed(flp[f])),
+		)
+		g.Node = append(g.Node,
+			node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"input", "feature" + p}, []string{"column" + p}),
+			node("Equal", "", []string{"column" + p, "categories" + p}, []string{"one_hot" + p}),
+			node("Cast", "", []string{"one_hot" + p}, []string{"one_hot_float" + p}, attrI("to", int64(dt("FLOAT")))),
+			node("MatMul", "", []string{"one_hot_float" + p, "feature_log_prob" + p}, []string{"log_prob" + p}),
+		)
+		if f == 0 {
+			sum = "log_prob" + p
+			continue
+		}
+		g.Node = append(g.Node, node("Add", "", []string{sum, "log_prob" + p}, []string{"sum" + p}))
+		sum = "sum" + p
+	}
+	g.Node = append(g.Node, node("Add", "", []string{sum, "class_log_prior"}, []string{"jll"}))
+	nbOutputs(g, 2)
+	return g
+}
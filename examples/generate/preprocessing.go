@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// The values of feature f of the samples
+func column(x [][]float64, f int) []float64 {
+	c := make([]float64, len(x))
+	for i := range x {
+		c[i] = x[i][f]
+	}
+	return c
+}
+
+// The q quantile of v with numpy's default linear interpolation
+func quantile(v []float64, q float64) float64 {
+	s := append([]float64(nil), v...)
+	sort.Float64s(s)
+	pos := q * float64(len(s)-1)
+	lo := int(math.Floor(pos))
+	if lo+1 >= len(s) {
+		return s[lo]
+	}
+	return s[lo] + (pos-float64(lo))*(s[lo+1]-s[lo])
+}
+
+// MinMaxScaler to [0, 1], which skl2onnx writes as a Mul and an Add
+func minMaxScaler() *ir.GraphProto {
+	x := trainRows()
+	scale, offset := make([]float64, 4), make([]float64, 4)
+	for f := range 4 {
+		c := column(x, f)
+		lo, hi := quantile(c, 0), quantile(c, 1)
+		scale[f] = r32(1 / (hi - lo))
+		offset[f] = r32(-lo / (hi - lo))
+	}
+	g := &ir.GraphProto{Name: "MinMaxScaler"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{floats("scale", []int64{4}, f32(scale)), floats("min", []int64{4}, f32(offset))}
+	g.Node = []*ir.NodeProto{
+		node("Mul", "", []string{"X", "scale"}, []string{"scaled"}),
+		node("Add", "", []string{"scaled", "min"}, []string{"variable"}),
+	}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 4)}
+	out := inputs32()
+	for i := range out {
+		for f := range 4 {
+			out[i][f] = out[i][f]*scale[f] + offset[f]
+		}
+	}
+	printMatrix("min max", out)
+	return g
+}
+
+// MaxAbsScaler and RobustScaler are Scaler ops
+func scalerGraph(name string, offset, scale []float64) *ir.GraphProto {
+	g := &ir.GraphProto{Name: name}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Node = []*ir.NodeProto{node("Scaler", "ai.onnx.ml", []string{"X"}, []string{"variable"},
+		attrFloats("offset", f32(offset)), attrFloats("scale", f32(scale)))}
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 4)}
+	out := inputs32()
+	for i := range out {
+		for f := range 4 {
+			out[i][f] = float64((float32(out[i][f]) - float32(offset[f])) * float32(scale[f]))
+		}
+	}
+	printMatrix(name, out)
+	return g
+}
+
+// MaxAbsScaler, a Scaler with a zero offset
+func maxAbsScaler() *ir.GraphProto {
+	x := trainRows()
+	scale := make([]float64, 4)
+	for f := range 4 {
+		m := 0.0
+		for _, v := range column(x, f) {
+			m = max(m, math.Abs(v))
+		}
+		scale[f] = 1 / m
+	}
+	return scalerGraph("MaxAbsScaler", make([]float64, 4), scale)
+}
+
+// RobustScaler centred on the median and scaled by the interquartile range
+func robustScaler() *ir.GraphProto {
+	x := trainRows()
+	offset, scale := make([]float64, 4), make([]float64, 4)
+	for f := range 4 {
+		c := column(x, f)
+		offset[f] = quantile(c, 0.5)
+		scale[f] = 1 / (quantile(c, 0.75) - quantile(c, 0.25))
+	}
+	return scalerGraph("RobustScaler", offset, scale)
+}
+
+// The Yeo-Johnson transform of x
+func yeoJohnson(x, lambda float64) float64 {
+	switch {
+	case x >= 0 && lambda == 0:
+		return math.Log1p(x)
+	case x >= 0:
+		return (math.Pow(x+1, lambda) - 1) / lambda
+	case lambda == 2:
+		return -math.Log1p(-x)
+	}
+	return -(math.Pow(1-x, 2-lambda) - 1) / (2 - lambda)
+}
+
+// The Box-Cox transform of x, which must be positive
+func boxCox(x, lambda float64) float64 {
+	if lambda == 0 {
+		return math.Log(x)
+	}
+	return (math.Pow(x, lambda) - 1) / lambda
+}
+
+// The features of the Yeo-Johnson example are centred on the iris means, so they have both signs
+var powerShift = []float64{5.8, 3.0, 3.8, 1.2}
+
+/*
+ * The standardised PowerTransformer. Both methods compute the general formula and the one of the
+ * lambdas it doesn't work for, then pick one with Where.
+ */
+func powerTransformer(method string) *ir.GraphProto {
+	lambdas := []float64{0.5, 0, -0.3, 1.2}
+	transform := boxCox
+	if method == "yeo-johnson" {
+		lambdas = []float64{0.8, 0, 1.3, 2}
+		transform = yeoJohnson
+	}
+	x := trainRows()
+	if method == "yeo-johnson" {
+		for i := range x {
+			for f := range 4 {
+				x[i][f] = float64(float32(x[i][f]) - float32(powerShift[f]))
+			}
+		}
+	}
+	mean, scale := make([]float64, 4), make([]float64, 4)
+	for f := range 4 {
+		c := column(x, f)
+		for i := range c {
+			c[i] = transform(c[i], lambdas[f])
+			mean[f] += c[i] / float64(len(c))
+		}
+		v := 0.0
+		for _, t := range c {
+			v += (t - mean[f]) * (t - mean[f]) / float64(len(c))
+		}
+		scale[f] = 1 / math.Sqrt(v)
+	}
+	g := &ir.GraphProto{Name: "PowerTransformer"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{
+		floats("lambdas", []int64{4}, f32(lambdas)), floats("zero", []int64{1}, []float32{0}),
+		floats("one", []int64{1}, []float32{1}),
+	}
+	general := func(base, lambda, prefix string) []*ir.NodeProto {
+		return []*ir.NodeProto{
+			node("Pow", "", []string{base, lambda}, []string{prefix + "pow"}),
+			node("Sub", "", []string{prefix + "pow", "one"}, []string{prefix + "pow_1"}),
+			node("Div", "", []string{prefix + "pow_1", lambda}, []string{prefix + "general"}),
+			node("Log", "", []string{base}, []string{prefix + "log"}),
+			node("Equal", "", []string{lambda, "zero"}, []string{prefix + "is_log"}),
+			node("Where", "", []string{prefix + "is_log", prefix + "log", prefix + "general"}, []string{prefix + "transformed"}),
+		}
+	}
+	if method == "box-cox" {
+		g.Node = general("X", "lambdas", "")
+	} else {
+		g.Initializer = append(g.Initializer, floats("two", []int64{1}, []float32{2}))
+		g.Node = append(g.Node,
+			node("Add", "", []string{"X", "one"}, []string{"x_1"}),
+			node("Sub", "", []string{"one", "X"}, []string{"1_x"}),
+			node("Sub", "", []string{"two", "lambdas"}, []string{"2_lambdas"}),
+		)
+		g.Node = append(g.Node, general("x_1", "lambdas", "positive_")...)
+		g.Node = append(g.Node, general("1_x", "2_lambdas", "negative_")...)
+		g.Node = append(g.Node,
+			node("Neg", "", []string{"negative_transformed"}, []string{"negative"}),
+			node("Less", "", []string{"X", "zero"}, []string{"is_negative"}),
+			node("Where", "", []string{"is_negative", "negative", "positive_transformed"}, []string{"transformed"}),
+		)
+	}
+	g.Node = append(g.Node, node("Scaler", "ai.onnx.ml", []string{"transformed"}, []string{"variable"},
+		attrFloats("offset", f32(mean)), attrFloats("scale", f32(scale))))
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 4)}
+
+	in := inputs32()
+	if method == "yeo-johnson" {
+		in = powerInputs()
+	}
+	out := make([][]float64, len(in))
+	for i := range in {
+		out[i] = make([]float64, 4)
+		for f := range 4 {
+			out[i][f] = (transform(in[i][f], lambdas[f]) - r32(mean[f])) * r32(scale[f])
+		}
+	}
+	printMatrix("power "+method, out)
+	return g
+}
+
+// The test inputs centred like the samples the Yeo-Johnson example is fitted on
+func powerInputs() [][]float64 {
+	in := inputs32()
+	for i := range in {
+		for f := range 4 {
+			in[i][f] = float64(float32(in[i][f]) - float32(powerShift[f]))
+		}
+	}
+	return in
+}
+
+// The upper edges of the bins of each feature, the last is +inf so that larger values go to the last bin
+func kbinsEdges(strategy string) [][]float64 {
+	nBins := []int{3, 4, 3, 2}
+	x := trainRows()
+	edges := make([][]float64, 4)
+	for f := range 4 {
+		c := column(x, f)
+		for b := 1; b < nBins[f]; b++ {
+			q := float64(b) / float64(nBins[f])
+			if strategy == "uniform" {
+				lo, hi := quantile(c, 0), quantile(c, 1)
+				edges[f] = append(edges[f], r32(lo+q*(hi-lo)))
+			} else {
+				edges[f] = append(edges[f], r32(quantile(c, q)))
+			}
+		}
+		edges[f] = append(edges[f], math.Inf(1))
+	}
+	return edges
+}
+
+// KBinsDiscretizer, each bin is encoded as a one-hot column or as its ordinal
+func kbinsDiscretizer(strategy, encode string) *ir.GraphProto {
+	edges := kbinsEdges(strategy)
+	g := &ir.GraphProto{Name: "KBinsDiscretizer"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{floats("onehot_values", []int64{2}, []float32{0, 1})}
+	var columns []string
+	width := 0
+	for f := range 4 {
+		p := fmt.Sprintf("feature%d_", f)
+		n := int64(len(edges[f]))
+		g.Initializer = append(g.Initializer,
+			ints(p+"column", []int64{1}, []int64{int64(f)}), floats(p+"edges", []int64{n}, f32(edges[f])),
+			ints(p+"depth", []int64{1}, []int64{n}))
+		g.Node = append(g.Node,
+			node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"X", p + "column"}, []string{p + "values"}),
+			node("Less", "", []string{p + "values", p + "edges"}, []string{p + "below"}),
+			node("Cast", "", []string{p + "below"}, []string{p + "below_float"}, attrI("to", int64(dt("FLOAT")))),
+		)
+		// The bin is the first edge the value is below
+		if encode == "onehot" {
+			g.Node = append(g.Node,
+				node("ArgMax", "", []string{p + "below_float"}, []string{p + "bin"}, attrI("axis", 1), attrI("keepdims", 0)),
+				node("OneHot", "", []string{p + "bin", p + "depth", "onehot_values"}, []string{p + "encoded"}))
+			width += int(n)
+		} else {
+			g.Node = append(g.Node,
+				node("ArgMax", "", []string{p + "below_float"}, []string{p + "bin"}, attrI("axis", 1), attrI("keepdims", 1)),
+				node("Cast", "", []string{p + "bin"}, []string{p + "encoded"}, attrI("to", int64(dt("FLOAT")))))
+			width++
+		}
+		columns = append(columns, p+"encoded")
+	}
+	g.Node = append(g.Node, node("Concat", "", columns, []string{"variable"}, attrI("axis", 1)))
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, int64(width))}
+
+	var out [][]float64
+	for _, x := range kbinsInputs() {
+		var row []float64
+		for f := range 4 {
+			bin := 0
+			for bin < len(edges[f])-1 && !(x[f] < edges[f][bin]) {
+				bin++
+			}
+			if encode == "onehot" {
+				for b := range edges[f] {
+					if b == bin {
+						row = append(row, 1)
+					} else {
+						row = append(row, 0)
+					}
+				}
+			} else {
+				row = append(row, float64(bin))
+			}
+		}
+		out = append(out, row)
+	}
+	printMatrix("kbins "+strategy+" "+encode, out)
+	return g
+}
+
+// Values past both ends of the training range and one on an edge
+func kbinsInputs() [][]float64 {
+	return append(inputs32(), []float64{3.0, 5.0, 0.5, 3.0}, []float64{7.1, 2.3, 6.0, 0.2})
+}
+
+// Degree 2 with the bias, the features of sklearn's powers_ in order
+func polynomialFeatures() *ir.GraphProto {
+	g := &ir.GraphProto{Name: "PolynomialFeatures"}
+	g.Input = []*ir.ValueInfoProto{tensorInfo("X", "FLOAT", -1, 4)}
+	g.Initializer = []*ir.TensorProto{floats("zero", []int64{1}, []float32{0})}
+	for f := range 4 {
+		g.Initializer = append(g.Initializer, ints(fmt.Sprintf("column%d", f), []int64{1}, []int64{int64(f)}))
+		g.Node = append(g.Node, node("ArrayFeatureExtractor", "ai.onnx.ml", []string{"X", fmt.Sprintf("column%d", f)}, []string{fmt.Sprintf("x%d", f)}))
+	}
+	// x0 ** 0 is the bias column
+	g.Node = append(g.Node, node("Pow", "", []string{"x0", "zero"}, []string{"bias"}))
+	features := []string{"bias", "x0", "x1", "x2", "x3"}
+	for i := range 4 {
+		for j := i; j < 4; j++ {
+			name := fmt.Sprintf("x%dx%d", i, j)
+			g.Node = append(g.Node, node("Mul", "", []string{fmt.Sprintf("x%d", i), fmt.Sprintf("x%d", j)}, []string{name}))
+			features = append(features, name)
+		}
+	}
+	g.Node = append(g.Node, node("Concat", "", features, []string{"variable"}, attrI("axis", 1)))
+	g.Output = []*ir.ValueInfoProto{tensorInfo("variable", "FLOAT", -1, 15)}
+	var out [][]float64
+	for _, x := range inputs32() {
+		row := append([]float64{1}, x...)
+		for i := range 4 {
+			for j := i; j < 4; j++ {
+				row = append(row, x[i]*x[j])
+			}
+		}
+		out = append(out, row)
+	}
+	printMatrix("polynomial", out)
+	return g
+}
@@ -2,4 +2,4 @@ module github.com/systemEng-Learning/go-ml-deployment
 
 go 1.23.3
 
-require google.golang.org/protobuf v1.36.5 // indirect
+require google.golang.org/protobuf v1.36.5
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ops"
+)
+
+// Returns the first tree ensemble op of the graph
+func (g *Graph) treeModel() (ops.TreeModel, error) {
+	for _, node := range g.nodes {
+		if model, ok := node.(ops.TreeModel); ok {
+			return model, nil
+		}
+	}
+	return nil, fmt.Errorf("graph has no tree ensemble")
+}
+
+// DecisionPaths runs the graph on input and returns the leaf each sample reached in every tree of
+// the first tree ensemble of the graph. The branches taken are listed when steps is set.
+func (g *Graph) DecisionPaths(input []any, steps bool) ([][]ops.DecisionPath, error) {
+	model, err := g.treeModel()
+	if err != nil {
+		return nil, err
+	}
+	_, err = g.Execute(input)
+	if err != nil {
+		return nil, err
+	}
+	tree, X, err := model.Ensemble(g.kernel)
+	if err != nil {
+		return nil, err
+	}
+	return tree.DecisionPaths(X, steps)
+}
+
+// Explain runs the graph on input and attributes the scores of its first explainable op, such as
+// the SHAP values of a tree ensemble, to the features of the op's input
+func (g *Graph) Explain(input []any) (*ops.Explanation, error) {
+	var explainer ops.Explainer
+	for _, node := range g.nodes {
+		if e, ok := node.(ops.Explainer); ok {
+			explainer = e
+			break
+		}
+	}
+	if explainer == nil {
+		return nil, fmt.Errorf("graph has no op that can be explained")
+	}
+	_, err := g.Execute(input)
+	if err != nil {
+		return nil, err
+	}
+	return explainer.Explain(g.kernel)
+}
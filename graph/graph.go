@@ -26,13 +26,23 @@ type Graph struct {
 }
 
 func (g *Graph) Init(graphProto *ir.GraphProto) error {
+	return g.InitWithOptions(graphProto, kernel.DefaultOptions())
+}
+
+// InitWithOptions initializes the graph with its own options, which its ops read when they are
+// initialized
+func (g *Graph) InitWithOptions(graphProto *ir.GraphProto, options kernel.Options) error {
 	g.graph = graphProto
-	g.kernel = &kernel.Kernel{}
+	g.kernel = &kernel.Kernel{Options: options}
 	g.kernel.Init()
 	err := g.setInputsTensor()
 	if err != nil {
 		return err
 	}
+	err = g.setInitializers()
+	if err != nil {
+		return err
+	}
 	err = g.initializeNodes()
 	if err != nil {
 		return err
@@ -45,10 +55,21 @@ func (g *Graph) Init(graphProto *ir.GraphProto) error {
 }
 
 func (g *Graph) setInputsTensor() error {
-	g.inputs = make([]int, len(g.graph.Input))
-	g.shapes = make([][]int, len(g.graph.Input))
-	g.dtypes = make([]tensors.DataType, len(g.graph.Input))
-	for i, input := range g.graph.Input {
+	// Older exports also list their initializers as inputs, they are not fed by the caller
+	initializers := make(map[string]bool, len(g.graph.Initializer))
+	for _, initializer := range g.graph.Initializer {
+		initializers[initializer.Name] = true
+	}
+	graphInputs := make([]*ir.ValueInfoProto, 0, len(g.graph.Input))
+	for _, input := range g.graph.Input {
+		if !initializers[input.Name] {
+			graphInputs = append(graphInputs, input)
+		}
+	}
+	g.inputs = make([]int, len(graphInputs))
+	g.shapes = make([][]int, len(graphInputs))
+	g.dtypes = make([]tensors.DataType, len(graphInputs))
+	for i, input := range graphInputs {
 		switch v := input.GetType().GetValue().(type) {
 		case *ir.TypeProto_TensorType:
 			t := v
@@ -65,41 +86,82 @@ func (g *Graph) setInputsTensor() error {
 			g.shapes[i] = shape
 			g.dtypes[i] = dtype
 		case *ir.TypeProto_MapType:
-			m := v.MapType
-			elemTypeStr := ir.TensorProto_DataType_name[m.KeyType]
-			value := m.GetValueType().GetValue()
-			t := value.(*ir.TypeProto_TensorType)
-
-			tensorType := ir.TensorProto_DataType_name[t.TensorType.ElemType]
-			tempdytpe := elemTypeStr + tensorType
-			var dtype tensors.DataType
-			switch tempdytpe {
-			case "STRINGFLOAT":
-				dtype = tensors.StringMap
-			case "STRINGDOUBLE":
-				dtype = tensors.StringDoubleMap
-			case "STRINGINT64":
-				dtype = tensors.StringIntMap
-			case "INT64FLOAT":
-				dtype = tensors.IntMap
-			case "INT64DOUBLE":
-				dtype = tensors.IntDoubleMap
-			case "INT64STRING":
-				dtype = tensors.IntStringMap
-			default:
-				return fmt.Errorf("graph setinputtensor: map type %s not supported", tempdytpe)
+			dtype, err := mapDtype(v.MapType)
+			if err != nil {
+				return err
+			}
+			index := g.kernel.RegisterWriter(input.Name)
+			g.inputs[i] = index
+			g.shapes[i] = []int{-1}
+			g.dtypes[i] = dtype
+		case *ir.TypeProto_SequenceType:
+			// A sequence of maps is fed like a map input, with a map per row
+			dtype := tensors.Sequence
+			if m, ok := v.SequenceType.GetElemType().GetValue().(*ir.TypeProto_MapType); ok {
+				var err error
+				dtype, err = mapDtype(m.MapType)
+				if err != nil {
+					return err
+				}
 			}
 			index := g.kernel.RegisterWriter(input.Name)
 			g.inputs[i] = index
 			g.shapes[i] = []int{-1}
 			g.dtypes[i] = dtype
-
 		}
 
 	}
 	return nil
 }
 
+// Places the constant tensors of the graph in the kernel. They hold an extra reader so that no op
+// takes them over as its output and changes them.
+func (g *Graph) setInitializers() error {
+	for _, initializer := range g.graph.Initializer {
+		t, err := tensors.FromTensorProto(initializer)
+		if err != nil {
+			return fmt.Errorf("graph initializer %s: %w", initializer.Name, err)
+		}
+		index := g.kernel.RegisterWriter(initializer.Name)
+		_, err = g.kernel.RegisterReader(initializer.Name)
+		if err != nil {
+			return err
+		}
+		err = g.kernel.Put(index, t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapDtype(m *ir.TypeProto_Map) (tensors.DataType, error) {
+	elemTypeStr := ir.TensorProto_DataType_name[m.KeyType]
+	value := m.GetValueType().GetValue()
+	t, ok := value.(*ir.TypeProto_TensorType)
+	if !ok {
+		return tensors.Undefined, fmt.Errorf("graph setinputtensor: map values should be tensors")
+	}
+
+	tensorType := ir.TensorProto_DataType_name[t.TensorType.ElemType]
+	tempdytpe := elemTypeStr + tensorType
+	switch tempdytpe {
+	case "STRINGFLOAT":
+		return tensors.StringMap, nil
+	case "STRINGDOUBLE":
+		return tensors.StringDoubleMap, nil
+	case "STRINGINT64":
+		return tensors.StringIntMap, nil
+	case "INT64FLOAT":
+		return tensors.IntMap, nil
+	case "INT64DOUBLE":
+		return tensors.IntDoubleMap, nil
+	case "INT64STRING":
+		return tensors.IntStringMap, nil
+	}
+	return tensors.Undefined, fmt.Errorf("graph setinputtensor: map type %s not supported", tempdytpe)
+}
+
 func getShape(shape *ir.TensorShapeProto) ([]int, error) {
 	if shape == nil {
 		fmt.Println("No shape")
@@ -110,9 +172,11 @@ func getShape(shape *ir.TensorShapeProto) ([]int, error) {
 		v := d.Value
 		switch t := v.(type) {
 		case *ir.TensorShapeProto_Dimension_DimParam:
+			// Named dimensions such as "N" or "batch_size" are only known when the input is fed
 			e, err := strconv.ParseInt(t.DimParam, 10, 32)
 			if err != nil {
-				return result, err
+				result[i] = -1
+				continue
 			}
 			result[i] = int(e)
 		case *ir.TensorShapeProto_Dimension_DimValue:
@@ -158,6 +222,10 @@ func (g *Graph) initializeNodes() error {
 			tr := &ops.TreeEnsembleRegressor{}
 			err = tr.Init(g.kernel, node)
 			g.nodes = append(g.nodes, tr)
+		case "TreeEnsemble":
+			tu := &ops.UnifiedTreeEnsemble{}
+			err = tu.Init(g.kernel, node)
+			g.nodes = append(g.nodes, tu)
 		case "SVMRegressor":
 			s := &ops.SVMRegressor{}
 			err = s.Init(g.kernel, node)
@@ -178,6 +246,110 @@ func (g *Graph) initializeNodes() error {
 			f := &ops.FeatureVectorizer{}
 			err = f.Init(g.kernel, node)
 			g.nodes = append(g.nodes, f)
+		case "StringNormalizer":
+			s := &ops.StringNormalizer{}
+			err = s.Init(g.kernel, node)
+			g.nodes = append(g.nodes, s)
+		case "Tokenizer":
+			t := &ops.Tokenizer{}
+			err = t.Init(g.kernel, node)
+			g.nodes = append(g.nodes, t)
+		case "TfIdfVectorizer":
+			t := &ops.TfIdfVectorizer{}
+			err = t.Init(g.kernel, node)
+			g.nodes = append(g.nodes, t)
+		case "SequenceConstruct":
+			s := &ops.SequenceConstruct{}
+			err = s.Init(g.kernel, node)
+			g.nodes = append(g.nodes, s)
+		case "SequenceAt":
+			s := &ops.SequenceAt{}
+			err = s.Init(g.kernel, node)
+			g.nodes = append(g.nodes, s)
+		case "SequenceLength":
+			s := &ops.SequenceLength{}
+			err = s.Init(g.kernel, node)
+			g.nodes = append(g.nodes, s)
+		case "SequenceEmpty":
+			s := &ops.SequenceEmpty{}
+			err = s.Init(g.kernel, node)
+			g.nodes = append(g.nodes, s)
+		case "SplitToSequence":
+			s := &ops.SplitToSequence{}
+			err = s.Init(g.kernel, node)
+			g.nodes = append(g.nodes, s)
+		case "Add", "Sub", "Mul", "Div", "Pow", "Equal", "Greater", "Less":
+			b := &ops.Binary{}
+			err = b.Init(g.kernel, node)
+			g.nodes = append(g.nodes, b)
+		case "Sqrt", "Exp", "Log", "Neg", "Reciprocal", "Relu", "Tanh", "Sigmoid":
+			u := &ops.Unary{}
+			err = u.Init(g.kernel, node)
+			g.nodes = append(g.nodes, u)
+		case "Softmax":
+			s := &ops.Softmax{}
+			err = s.Init(g.kernel, node)
+			g.nodes = append(g.nodes, s)
+		case "ReduceSum", "ReduceMean", "ReduceSumSquare", "ReduceLogSumExp":
+			r := &ops.Reduce{}
+			err = r.Init(g.kernel, node)
+			g.nodes = append(g.nodes, r)
+		case "Clip":
+			c := &ops.Clip{}
+			err = c.Init(g.kernel, node)
+			g.nodes = append(g.nodes, c)
+		case "Gemm":
+			m := &ops.Gemm{}
+			err = m.Init(g.kernel, node)
+			g.nodes = append(g.nodes, m)
+		case "MatMul":
+			m := &ops.MatMul{}
+			err = m.Init(g.kernel, node)
+			g.nodes = append(g.nodes, m)
+		case "CDist":
+			c := &ops.CDist{}
+			err = c.Init(g.kernel, node)
+			g.nodes = append(g.nodes, c)
+		case "TopK":
+			t := &ops.TopK{}
+			err = t.Init(g.kernel, node)
+			g.nodes = append(g.nodes, t)
+		case "ArgMax", "ArgMin":
+			a := &ops.ArgReduce{}
+			err = a.Init(g.kernel, node)
+			g.nodes = append(g.nodes, a)
+		case "ArrayFeatureExtractor":
+			a := &ops.ArrayFeatureExtractor{}
+			err = a.Init(g.kernel, node)
+			g.nodes = append(g.nodes, a)
+		case "Reshape":
+			r := &ops.Reshape{}
+			err = r.Init(g.kernel, node)
+			g.nodes = append(g.nodes, r)
+		case "Flatten":
+			f := &ops.Flatten{}
+			err = f.Init(g.kernel, node)
+			g.nodes = append(g.nodes, f)
+		case "Identity":
+			i := &ops.Identity{}
+			err = i.Init(g.kernel, node)
+			g.nodes = append(g.nodes, i)
+		case "Gather":
+			ga := &ops.Gather{}
+			err = ga.Init(g.kernel, node)
+			g.nodes = append(g.nodes, ga)
+		case "Where":
+			w := &ops.Where{}
+			err = w.Init(g.kernel, node)
+			g.nodes = append(g.nodes, w)
+		case "OneHot":
+			o := &ops.OneHot{}
+			err = o.Init(g.kernel, node)
+			g.nodes = append(g.nodes, o)
+		case "Concat":
+			c := &ops.Concat{}
+			err = c.Init(g.kernel, node)
+			g.nodes = append(g.nodes, c)
 		default:
 			return fmt.Errorf("%s operation not supported", node.OpType)
 		}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"math"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// A 3-D initializer broadcast against the input reshaped to 3 dimensions, the way the GaussianNB
+// graph subtracts the means of every class
+func TestInit_ThreeDimensionalInitializer(t *testing.T) {
+	float := ir.TensorProto_DataType_value["FLOAT"]
+	shape := &ir.TensorShapeProto{Dim: []*ir.TensorShapeProto_Dimension{
+		{Value: &ir.TensorShapeProto_Dimension_DimParam{DimParam: "N"}},
+		{Value: &ir.TensorShapeProto_Dimension_DimValue{DimValue: 2}},
+	}}
+	proto := &ir.GraphProto{
+		Input: []*ir.ValueInfoProto{{Name: "X", Type: &ir.TypeProto{Value: &ir.TypeProto_TensorType{
+			TensorType: &ir.TypeProto_Tensor{ElemType: float, Shape: shape}}}}},
+		Output: []*ir.ValueInfoProto{{Name: "Y"}},
+		Initializer: []*ir.TensorProto{
+			{Name: "shape", Dims: []int64{3}, DataType: ir.TensorProto_DataType_value["INT64"], Int64Data: []int64{-1, 1, 2}},
+			{Name: "means", Dims: []int64{1, 3, 2}, DataType: float, FloatData: []float32{0, 0, 1, 1, 2, 4}},
+		},
+		Node: []*ir.NodeProto{
+			{OpType: "Reshape", Input: []string{"X", "shape"}, Output: []string{"reshaped"}},
+			{OpType: "Sub", Input: []string{"reshaped", "means"}, Output: []string{"difference"}},
+			{OpType: "ReduceSum", Input: []string{"difference"}, Output: []string{"Y"}, Attribute: []*ir.AttributeProto{
+				{Name: "axes", Ints: []int64{2}, Type: ir.AttributeProto_INTS},
+				{Name: "keepdims", I: 0, Type: ir.AttributeProto_INT},
+			}},
+		},
+	}
+	g := &Graph{}
+	if err := g.Init(proto); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	result, err := g.Execute([]any{[][]float32{{1, 2}, {3, 5}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := [][]float32{{3, 1, -3}, {8, 6, 2}}
+	got := result[0].([][]float32)
+	for i := range want {
+		for j := range want[i] {
+			if math.Abs(float64(got[i][j]-want[i][j])) > 1e-6 {
+				t.Errorf("Wanted %v got: %v", want, got)
+			}
+		}
+	}
+}
@@ -13,11 +13,16 @@ type Number interface {
 	int32 | int64 | int | float32 | float64
 }
 
+// Element is the type of the values of a tensor input, a bool input must be fed to a bool tensor
+type Element interface {
+	Number | bool
+}
+
 type MapType interface {
 	map[int64]float32 | map[string]float32 | map[string]int64 | map[int64][]byte | map[int64]float64 | map[string]float64
 }
 
-type InputProcessor[T Number] struct {
+type InputProcessor[T Element] struct {
 	index int
 	shape []int
 	dtype tensor.DataType
@@ -29,10 +34,26 @@ type InputProcessorMap[T MapType] struct {
 	dtype tensor.DataType
 }
 
-func (ip *InputProcessor[T]) processStatic(v T, kernel *kernel.Kernel) error {
+type InputProcessorString struct {
+	index int
+	shape []int
+	dtype tensor.DataType
+}
+
+func (ip *InputProcessor[T]) checkDtype() error {
+	if _, ok := any(*new(T)).(bool); ok {
+		return assertDtypeEqual(ip.dtype, tensor.Bool, "")
+	}
 	if ip.dtype == tensor.StringMap || ip.dtype == tensor.IntMap || ip.dtype == tensor.Undefined {
 		return fmt.Errorf("unsupported datatype: %s", ip.dtype)
 	}
+	return nil
+}
+
+func (ip *InputProcessor[T]) processStatic(v T, kernel *kernel.Kernel) error {
+	if err := ip.checkDtype(); err != nil {
+		return err
+	}
 	shape := slices.Clone(ip.shape)
 	if shape[0] == -1 {
 		shape[0] = 1
@@ -49,16 +70,7 @@ func (ip *InputProcessor[T]) processStatic(v T, kernel *kernel.Kernel) error {
 	if err != nil {
 		return err
 	}
-	switch ip.dtype {
-	case tensor.Float:
-		t.FloatData[0] = float32(v)
-	case tensor.Double:
-		t.DoubleData[0] = float64(v)
-	case tensor.Int32:
-		t.Int32Data[0] = int32(v)
-	case tensor.Int64:
-		t.Int64Data[0] = int64(v)
-	}
+	store(t, 0, []T{v})
 	return nil
 }
 func assertDtypeEqual(got, want tensor.DataType, msg string) error {
@@ -93,8 +105,8 @@ func (ip *InputProcessorMap[T]) defineShape1D(v []T) ([]int, error) {
 }
 
 func (ip *InputProcessor[T]) process1D(v []T, kernel *kernel.Kernel) error {
-	if ip.dtype == tensor.StringMap || ip.dtype == tensor.IntMap || ip.dtype == tensor.Undefined {
-		return fmt.Errorf("unsupported datatype: %s", ip.dtype)
+	if err := ip.checkDtype(); err != nil {
+		return err
 	}
 	shape := slices.Clone(ip.shape)
 
@@ -120,29 +132,14 @@ func (ip *InputProcessor[T]) process1D(v []T, kernel *kernel.Kernel) error {
 	if err != nil {
 		return err
 	}
-
-	switch ip.dtype {
-	case tensor.Float:
-		for i, val := range v {
-			t.FloatData[i] = float32(val)
-		}
-	case tensor.Double:
-		for i, val := range v {
-			t.DoubleData[i] = float64(val)
-		}
-	case tensor.Int32:
-		for i, val := range v {
-			t.Int32Data[i] = int32(val)
-		}
-	case tensor.Int64:
-		for i, val := range v {
-			t.Int64Data[i] = int64(val)
-		}
-	}
+	store(t, 0, v)
 	return nil
 }
 
 func (ip *InputProcessor[T]) process2D(v [][]T, kernel *kernel.Kernel) error {
+	if err := ip.checkDtype(); err != nil {
+		return err
+	}
 	shape := slices.Clone(ip.shape)
 	m := len(v)
 	if m == 0 {
@@ -169,31 +166,118 @@ func (ip *InputProcessor[T]) process2D(v [][]T, kernel *kernel.Kernel) error {
 	if err != nil {
 		return err
 	}
+	for x := range m {
+		store(t, x*n, v[x])
+	}
+	return nil
+}
 
-	switch ip.dtype {
+// Writes values to the data of t from offset. Numbers are converted to the type of t, booleans
+// are copied to a bool tensor.
+func store[T Element](t *tensor.Tensor, offset int, values []T) {
+	switch values := any(values).(type) {
+	case []bool:
+		copy(t.BoolData[offset:], values)
+	case []int32:
+		storeNumbers(t, offset, values)
+	case []int:
+		storeNumbers(t, offset, values)
+	case []int64:
+		storeNumbers(t, offset, values)
+	case []float32:
+		storeNumbers(t, offset, values)
+	case []float64:
+		storeNumbers(t, offset, values)
+	}
+}
+
+func storeNumbers[T Number](t *tensor.Tensor, offset int, values []T) {
+	switch t.DType {
 	case tensor.Float:
-		for x := range m {
-			for y := range n {
-				t.FloatData[x*n+y] = float32(v[x][y])
-			}
+		for i, val := range values {
+			t.FloatData[offset+i] = float32(val)
 		}
 	case tensor.Double:
-		for x := range m {
-			for y := range n {
-				t.DoubleData[x*n+y] = float64(v[x][y])
-			}
+		for i, val := range values {
+			t.DoubleData[offset+i] = float64(val)
 		}
 	case tensor.Int32:
-		for x := range m {
-			for y := range n {
-				t.Int32Data[x*n+y] = int32(v[x][y])
-			}
+		for i, val := range values {
+			t.Int32Data[offset+i] = int32(val)
 		}
 	case tensor.Int64:
-		for x := range m {
-			for y := range n {
-				t.Int64Data[x*n+y] = int64(v[x][y])
+		for i, val := range values {
+			t.Int64Data[offset+i] = int64(val)
+		}
+	}
+}
+
+func (ip *InputProcessorString) process1D(v []string, kernel *kernel.Kernel) error {
+	if err := assertDtypeEqual(ip.dtype, tensor.String, ""); err != nil {
+		return err
+	}
+	shape := slices.Clone(ip.shape)
+
+	switch len(shape) {
+	case 1:
+		if shape[0] == -1 {
+			shape[0] = len(v) // Automatically fit
+		} else if len(v) != shape[0] {
+			return fmt.Errorf("data of length %d cannot fit expected input of length %d", len(v), shape[0])
+		}
+	case 2:
+		if shape[0] == -1 {
+			if len(v)%shape[1] != 0 {
+				return fmt.Errorf("data of length %d cannot be reshaped into %v", len(v), shape)
 			}
+			shape[0] = len(v) / shape[1] // Set row count
+		} else if len(v) != shape[0]*shape[1] {
+			return fmt.Errorf("data of length %d cannot be reshaped into %v", len(v), shape)
+		}
+	}
+
+	t, err := kernel.Output(ip.index, shape, ip.dtype)
+	if err != nil {
+		return err
+	}
+	for i, val := range v {
+		t.StringData[i] = []byte(val)
+	}
+	return nil
+}
+
+func (ip *InputProcessorString) process2D(v [][]string, kernel *kernel.Kernel) error {
+	if err := assertDtypeEqual(ip.dtype, tensor.String, ""); err != nil {
+		return err
+	}
+	shape := slices.Clone(ip.shape)
+	m := len(v)
+	if m == 0 {
+		return fmt.Errorf("input is empty")
+	}
+
+	n := len(v[0])
+	for i := 1; i < m; i++ {
+		if len(v[i]) != n {
+			return fmt.Errorf("rows don't have equal length")
+		}
+	}
+
+	if len(shape) != 2 {
+		return fmt.Errorf("input should be 2D, got %dD", len(shape))
+	}
+	if shape[0] == -1 && n == shape[1] {
+		shape[0] = m
+	} else if n != shape[1] || (shape[0] > -1 && shape[0] != m) {
+		return fmt.Errorf("expected input of shape %v, got [%d, %d]", shape, m, n)
+	}
+	t, err := kernel.Output(ip.index, shape, ip.dtype)
+	if err != nil {
+		return err
+	}
+	for x := range m {
+		for y := range n {
+			t.StringData[x*n+y] = []byte(v[x][y])
 		}
 	}
 	return nil
@@ -330,6 +414,18 @@ func (g *Graph) setInputs(input []any) error {
 				return err
 			}
 			t.StringDoubleMap = item
+		case []string:
+			ip := InputProcessorString{index: index, shape: shape, dtype: dtype}
+			err = ip.process1D(item, g.kernel)
+		case [][]string:
+			ip := InputProcessorString{index: index, shape: shape, dtype: dtype}
+			err = ip.process2D(item, g.kernel)
+		case []bool:
+			ip := InputProcessor[bool]{index: index, shape: shape, dtype: dtype}
+			err = ip.process1D(item, g.kernel)
+		case [][]bool:
+			ip := InputProcessor[bool]{index: index, shape: shape, dtype: dtype}
+			err = ip.process2D(item, g.kernel)
 		case [][]int32:
 			ip := InputProcessor[int32]{index: index, shape: shape, dtype: dtype}
 			err = ip.process2D(item, g.kernel)
@@ -345,6 +441,8 @@ func (g *Graph) setInputs(input []any) error {
 		case [][]float64:
 			ip := InputProcessor[float64]{index: index, shape: shape, dtype: dtype}
 			err = ip.process2D(item, g.kernel)
+		case []any:
+			err = g.setSequence(index, dtype, item)
 		default:
 			return fmt.Errorf("unsupported data type: %v", reflect.TypeOf(item))
 		}
@@ -354,3 +452,77 @@ func (g *Graph) setInputs(input []any) error {
 	}
 	return nil
 }
+
+// Sets a sequence input, each of its items is a slice of at most 2 dimensions
+func (g *Graph) setSequence(index int, dtype tensor.DataType, items []any) error {
+	if err := assertDtypeEqual(dtype, tensor.Sequence, ""); err != nil {
+		return err
+	}
+	t, err := g.kernel.Output(index, []int{len(items)}, tensor.Sequence)
+	if err != nil {
+		return err
+	}
+	t.SequenceData = t.SequenceData[:len(items)]
+	for i, item := range items {
+		t.SequenceData[i], err = sequenceItem(item)
+		if err != nil {
+			return fmt.Errorf("sequence item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func sequenceItem(item any) (*tensor.Tensor, error) {
+	switch item := item.(type) {
+	case []float32:
+		return &tensor.Tensor{Shape: []int{len(item)}, DType: tensor.Float, FloatData: slices.Clone(item)}, nil
+	case []float64:
+		return &tensor.Tensor{Shape: []int{len(item)}, DType: tensor.Double, DoubleData: slices.Clone(item)}, nil
+	case []int32:
+		return &tensor.Tensor{Shape: []int{len(item)}, DType: tensor.Int32, Int32Data: slices.Clone(item)}, nil
+	case []int64:
+		return &tensor.Tensor{Shape: []int{len(item)}, DType: tensor.Int64, Int64Data: slices.Clone(item)}, nil
+	case []string:
+		t := &tensor.Tensor{Shape: []int{len(item)}, DType: tensor.String, StringData: make([][]byte, len(item))}
+		for i := range item {
+			t.StringData[i] = []byte(item[i])
+		}
+		return t, nil
+	case [][]float32:
+		data, shape, err := flatten(item)
+		return &tensor.Tensor{Shape: shape, DType: tensor.Float, FloatData: data}, err
+	case [][]float64:
+		data, shape, err := flatten(item)
+		return &tensor.Tensor{Shape: shape, DType: tensor.Double, DoubleData: data}, err
+	case [][]int32:
+		data, shape, err := flatten(item)
+		return &tensor.Tensor{Shape: shape, DType: tensor.Int32, Int32Data: data}, err
+	case [][]int64:
+		data, shape, err := flatten(item)
+		return &tensor.Tensor{Shape: shape, DType: tensor.Int64, Int64Data: data}, err
+	case [][]string:
+		data, shape, err := flatten(item)
+		t := &tensor.Tensor{Shape: shape, DType: tensor.String, StringData: make([][]byte, len(data))}
+		for i := range data {
+			t.StringData[i] = []byte(data[i])
+		}
+		return t, err
+	}
+	return nil, fmt.Errorf("unsupported data type: %v", reflect.TypeOf(item))
+}
+
+func flatten[T any](v [][]T) ([]T, []int, error) {
+	m := len(v)
+	if m == 0 {
+		return nil, nil, fmt.Errorf("input is empty")
+	}
+	n := len(v[0])
+	data := make([]T, 0, m*n)
+	for i := range m {
+		if len(v[i]) != n {
+			return nil, nil, fmt.Errorf("rows don't have equal length")
+		}
+		data = append(data, v[i]...)
+	}
+	return data, []int{m, n}, nil
+}
@@ -362,6 +362,33 @@ func TestExecute_StaticValueShapeMismatch(t *testing.T) {
 	}
 }
 
+func TestExecute_BoolInput(t *testing.T) {
+	g := &Graph{
+		shapes: [][]int{{-1, 2}},
+		dtypes: []tensor.DataType{tensor.Bool},
+		kernel: &kernel.Kernel{},
+	}
+	g.kernel.Init()
+	g.inputs = []int{g.kernel.RegisterWriter("input1")}
+	index, _ := g.kernel.RegisterReader("input1")
+	g.outputs = []int{index}
+
+	arr, err := g.Execute([]any{[][]bool{{true, false}, {false, true}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	got := arr[0].([][]bool)
+	if !got[0][0] || got[0][1] || got[1][0] || !got[1][1] {
+		t.Errorf("Wanted [[true false] [false true]] got: %v", got)
+	}
+
+	g.dtypes[0] = tensor.Float
+	_, err = g.Execute([]any{[]bool{true, false}})
+	if err == nil {
+		t.Errorf("Expected an error when feeding booleans to a float input, but got none")
+	}
+}
+
 func BenchmarkExecute_LargeInput(b *testing.B) {
 	g := &Graph{
 		shapes: [][]int{{1000000}},
@@ -4,6 +4,7 @@ import (
 	"maps"
 	"slices"
 
+	"github.com/systemEng-Learning/go-ml-deployment/ops"
 	tensors "github.com/systemEng-Learning/go-ml-deployment/tensor"
 )
 
@@ -12,6 +13,17 @@ type OutputProcessor[T Number] struct {
 	shape []int
 }
 
+// Returns a scalar as a single value and larger tensors as slices
+func (op *OutputProcessor[T]) get() any {
+	switch len(op.shape) {
+	case 0:
+		return op.arr[0]
+	case 1:
+		return op.get1D()
+	}
+	return op.get2D()
+}
+
 func (op *OutputProcessor[T]) get1D() []T {
 	// It is possible for a tensor to have a larger space than what is needed for the current data
 	return slices.Clone(op.arr[:op.shape[0]])
@@ -31,82 +43,136 @@ func (op *OutputProcessor[T]) get2D() [][]T {
 func (g *Graph) getOutputs() []any {
 	result := make([]any, len(g.outputs))
 	for index, output := range g.outputs {
-		tensor := g.kernel.Get(output)
-		if tensor == nil {
-			result[index] = nil
-			continue
+		result[index] = outputValue(g.kernel.Get(output))
+	}
+	return result
+}
+
+// Converts a tensor to the Go value returned to the caller, nil for an unsupported dtype
+func outputValue(tensor *tensors.Tensor) any {
+	if tensor == nil {
+		return nil
+	}
+	var result any
+	switch tensor.DType {
+	case tensors.Float:
+		op := OutputProcessor[float32]{
+			arr:   tensor.FloatData,
+			shape: tensor.Shape,
 		}
-		switch tensor.DType {
-		case tensors.Float:
-			op := OutputProcessor[float32]{
-				arr:   tensor.FloatData,
-				shape: tensor.Shape,
-			}
-			if len(tensor.Shape) == 1 {
-				result[index] = op.get1D()
-			} else {
-				result[index] = op.get2D()
-			}
-		case tensors.Double:
-			op := OutputProcessor[float64]{
-				arr:   tensor.DoubleData,
-				shape: tensor.Shape,
-			}
-			if len(tensor.Shape) == 1 {
-				result[index] = op.get1D()
-			} else {
-				result[index] = op.get2D()
-			}
-		case tensors.Int32:
-			op := OutputProcessor[int32]{
-				arr:   tensor.Int32Data,
-				shape: tensor.Shape,
-			}
-			if len(tensor.Shape) == 1 {
-				result[index] = op.get1D()
-			} else {
-				result[index] = op.get2D()
-			}
-		case tensors.Int64:
-			op := OutputProcessor[int64]{
-				arr:   tensor.Int64Data,
-				shape: tensor.Shape,
-			}
-			if len(tensor.Shape) == 1 {
-				result[index] = op.get1D()
-			} else {
-				result[index] = op.get2D()
+		result = op.get()
+	case tensors.Double:
+		op := OutputProcessor[float64]{
+			arr:   tensor.DoubleData,
+			shape: tensor.Shape,
+		}
+		result = op.get()
+	case tensors.Int32:
+		op := OutputProcessor[int32]{
+			arr:   tensor.Int32Data,
+			shape: tensor.Shape,
+		}
+		result = op.get()
+	case tensors.Int64:
+		op := OutputProcessor[int64]{
+			arr:   tensor.Int64Data,
+			shape: tensor.Shape,
+		}
+		result = op.get()
+	case tensors.String:
+		if len(tensor.Shape) == 0 {
+			result = string(tensor.StringData[0])
+		} else if len(tensor.Shape) == 1 {
+			stringArr := make([]string, tensor.Shape[0])
+			for i := range stringArr {
+				stringArr[i] = string(tensor.StringData[i])
 			}
-		case tensors.String:
-			if len(tensor.Shape) == 1 {
-				stringArr := make([]string, tensor.Shape[0])
-				for i := range stringArr {
-					stringArr[i] = string(tensor.StringData[i])
+			result = stringArr
+		} else if len(tensor.Shape) == 2 {
+			stringArr2D := make([][]string, tensor.Shape[0])
+			for i := range stringArr2D {
+				stringArr2D[i] = make([]string, tensor.Shape[1])
+				for j := range stringArr2D[i] {
+					stringArr2D[i][j] = string(tensor.StringData[i*tensor.Shape[1]+j])
 				}
-				result[index] = stringArr
-			} else if len(tensor.Shape) == 2 {
-				stringArr2D := make([][]string, tensor.Shape[0])
-				for i := range stringArr2D {
-					stringArr2D[i] = make([]string, tensor.Shape[1])
-					for j := range stringArr2D[i] {
-						stringArr2D[i][j] = string(tensor.StringData[i*tensor.Shape[1]+j])
+			}
+			result = stringArr2D
+		} else if len(tensor.Shape) == 3 {
+			stringArr3D := make([][][]string, tensor.Shape[0])
+			for i := range stringArr3D {
+				stringArr3D[i] = make([][]string, tensor.Shape[1])
+				for j := range stringArr3D[i] {
+					stringArr3D[i][j] = make([]string, tensor.Shape[2])
+					for l := range stringArr3D[i][j] {
+						stringArr3D[i][j][l] = string(tensor.StringData[(i*tensor.Shape[1]+j)*tensor.Shape[2]+l])
 					}
 				}
-				result[index] = stringArr2D
 			}
-		case tensors.IntMap:
-			mapSlice := make([]map[int64]float32, tensor.Shape[0])
-			for i := range mapSlice {
-				mapSlice[i] = maps.Clone(tensor.IntMap[i])
+			result = stringArr3D
+		}
+	case tensors.Bool:
+		if len(tensor.Shape) == 0 {
+			result = tensor.BoolData[0]
+		} else if len(tensor.Shape) == 1 {
+			result = slices.Clone(tensor.BoolData[:tensor.Shape[0]])
+		} else {
+			boolArr2D := make([][]bool, tensor.Shape[0])
+			for i := range boolArr2D {
+				boolArr2D[i] = slices.Clone(tensor.BoolData[i*tensor.Shape[1] : (i+1)*tensor.Shape[1]])
 			}
-			result[index] = mapSlice
-		case tensors.StringMap:
-			mapSlice := make([]map[string]float32, tensor.Shape[0])
-			for i := range mapSlice {
-				mapSlice[i] = maps.Clone(tensor.StringMap[i])
+			result = boolArr2D
+		}
+	case tensors.IntMap:
+		mapSlice := make([]map[int64]float32, tensor.Shape[0])
+		for i := range mapSlice {
+			mapSlice[i] = maps.Clone(tensor.IntMap[i])
+		}
+		result = mapSlice
+	case tensors.StringMap:
+		mapSlice := make([]map[string]float32, tensor.Shape[0])
+		for i := range mapSlice {
+			mapSlice[i] = maps.Clone(tensor.StringMap[i])
+		}
+		result = mapSlice
+	case tensors.IntDoubleMap:
+		result = cloneMaps(tensor.IntDoubleMap, tensor.Shape[0])
+	case tensors.StringDoubleMap:
+		result = cloneMaps(tensor.StringDoubleMap, tensor.Shape[0])
+	case tensors.StringIntMap:
+		result = cloneMaps(tensor.StringIntMap, tensor.Shape[0])
+	case tensors.IntStringMap:
+		mapSlice := make([]map[int64]string, tensor.Shape[0])
+		for i := range mapSlice {
+			mapSlice[i] = make(map[int64]string, len(tensor.IntStringMap[i]))
+			for k, v := range tensor.IntStringMap[i] {
+				mapSlice[i][k] = string(v)
 			}
-			result[index] = mapSlice
 		}
+		result = mapSlice
+	case tensors.Sequence:
+		items := make([]any, len(tensor.SequenceData))
+		for i, item := range tensor.SequenceData {
+			items[i] = outputValue(item)
+		}
+		result = items
 	}
 	return result
 }
+
+func cloneMaps[K comparable, V any](arr []map[K]V, rows int) []map[K]V {
+	mapSlice := make([]map[K]V, rows)
+	for i := range mapSlice {
+		mapSlice[i] = maps.Clone(arr[i])
+	}
+	return mapSlice
+}
+
+// RawProbabilities makes the ZipMap ops of the graph output the probability matrices of the
+// classifiers instead of a map of label to probability per sample, which is faster to produce
+func (g *Graph) RawProbabilities(raw bool) {
+	for _, node := range g.nodes {
+		if z, ok := node.(*ops.ZipMap); ok {
+			z.SetRaw(raw)
+		}
+	}
+}
@@ -16,6 +16,10 @@ import (
  *
  * - If `Readers == 1`, it is safe to transfer the tensor pointer.
  * - Otherwise, cloning might be necessary to prevent unintended modifications.
+ *
+ * An op that doesn't take over its input this way must leave it unchanged, even a cast in
+ * place. Outputs like the probabilities of a classifier are often read by several ops, such as
+ * the estimators of a voting or stacking ensemble, and the graph input is read by all of them.
  */
 type Data struct {
 	Readers int
@@ -29,6 +33,7 @@ type Data struct {
  * - Once setup is complete, operations can retrieve tensors directly using the index.
  */
 type Kernel struct {
+	Options   Options
 	tensors   []Data
 	tensorMap map[string]int // map of tensor name to index in tensors slice. Only used temporarily during setup
 }
@@ -99,10 +104,7 @@ func (k *Kernel) Output(index int, shape []int, dtype tensors.DataType) (*tensor
 		t.Alloc()
 		k.tensors[index].Tensor = t
 	} else {
-		count := shape[0]
-		if len(shape) > 1 {
-			count *= shape[1]
-		}
+		count := tensors.Size(shape)
 		capacity := 0
 		if dtype == t.DType || (t.DType == tensors.Double && dtype == tensors.Float) ||
 			(t.DType == tensors.Float && dtype == tensors.Double) || (t.DType == tensors.Int64 && dtype == tensors.Int32) ||
@@ -0,0 +1,36 @@
+package kernel
+
+import "runtime"
+
+// TreeStrategy selects how tree ensembles are evaluated
+type TreeStrategy int
+
+const (
+	// QuickScorer is used for ensembles of many shallow trees, traversal otherwise
+	TreeStrategyAuto TreeStrategy = iota
+	TreeStrategyTraversal
+	TreeStrategyQuickScorer
+)
+
+/*
+ * Options are the settings of a graph. They are set on its kernel before the ops are initialized,
+ * and the ops that depend on them read them in Init, so graphs loaded in the same process can use
+ * different settings.
+ */
+type Options struct {
+	TreeStrategy TreeStrategy
+	// Number of goroutines a tree ensemble scores with, 1 or less scores on the calling goroutine
+	TreeWorkers int
+	// Number of goroutines a large float or double matrix product is split over, 1 or less
+	// multiplies on the calling goroutine
+	DotWorkers int
+}
+
+// DefaultOptions returns the options of graphs initialized without any
+func DefaultOptions() Options {
+	return Options{
+		TreeStrategy: TreeStrategyAuto,
+		TreeWorkers:  runtime.GOMAXPROCS(0),
+		DotWorkers:   runtime.GOMAXPROCS(0),
+	}
+}
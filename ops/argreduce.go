@@ -0,0 +1,123 @@
+package ops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// ArgReduce runs the ArgMax and ArgMin ops. They return the index of the largest or the smallest
+// value along an axis, the first one when several are equal unless select_last_index is set.
+type ArgReduce struct {
+	op         string
+	input      int
+	axis       int
+	keepdims   bool
+	last_index bool
+	output     int
+}
+
+func (a *ArgReduce) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	switch node.OpType {
+	case "ArgMax", "ArgMin":
+	default:
+		return fmt.Errorf("%s is not an arg reduce op", node.OpType)
+	}
+	a.op = node.OpType
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	a.input = input
+	a.axis = 0
+	a.keepdims = true
+	a.last_index = false
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axis":
+			a.axis = int(attr.I)
+		case "keepdims":
+			a.keepdims = attr.I != 0
+		case "select_last_index":
+			a.last_index = attr.I != 0
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	a.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (a *ArgReduce) Compute(k *kernel.Kernel) error {
+	name := strings.ToLower(a.op)
+	data, err := k.Input(a.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	rank := len(input.Shape)
+	if rank == 0 || rank > 2 {
+		return fmt.Errorf("%s: invalid shape %v", name, input.Shape)
+	}
+	axis := a.axis
+	if axis < -rank || axis >= rank {
+		return fmt.Errorf("%s: axis %d is out of range for shape %v", name, a.axis, input.Shape)
+	}
+	if axis < 0 {
+		axis += rank
+	}
+	shape := make([]int, 0, rank)
+	for i, d := range input.Shape {
+		if i != axis {
+			shape = append(shape, d)
+		} else if a.keepdims {
+			shape = append(shape, 1)
+		}
+	}
+	if len(shape) == 0 {
+		shape = []int{1}
+	}
+	output, err := k.Output(a.output, shape, tensor.Int64)
+	if err != nil {
+		return err
+	}
+
+	rows, cols := matrixShape(input.Shape)
+	lines, length, stride, line_start := rows, cols, 1, cols
+	if rank == 2 && axis == 0 {
+		lines, length, stride, line_start = cols, rows, cols, 1
+	}
+	switch input.DType {
+	case tensor.Float:
+		argReduce(input.FloatData, output.Int64Data, lines, length, stride, line_start, a.last_index, a.op == "ArgMin")
+	case tensor.Double:
+		argReduce(input.DoubleData, output.Int64Data, lines, length, stride, line_start, a.last_index, a.op == "ArgMin")
+	case tensor.Int32:
+		argReduce(input.Int32Data, output.Int64Data, lines, length, stride, line_start, a.last_index, a.op == "ArgMin")
+	case tensor.Int64:
+		argReduce(input.Int64Data, output.Int64Data, lines, length, stride, line_start, a.last_index, a.op == "ArgMin")
+	default:
+		return fmt.Errorf("%s: input datatype (%v) is invalid", name, input.DType)
+	}
+	return nil
+}
+
+func argReduce[T tensor.Numeric](in []T, out []int64, lines, length, stride, line_start int, last_index, smallest bool) {
+	for line := range lines {
+		start := line * line_start
+		best := 0
+		for i := 1; i < length; i++ {
+			v, b := in[start+i*stride], in[start+best*stride]
+			if smallest {
+				v, b = b, v
+			}
+			if v > b || (last_index && v == b) {
+				best = i
+			}
+		}
+		out[line] = int64(best)
+	}
+}
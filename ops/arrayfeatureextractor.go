@@ -0,0 +1,90 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// ArrayFeatureExtractor selects the columns of its input at the given indices. Like onnxruntime,
+// the indices are flattened, and a vector input gives a single row.
+type ArrayFeatureExtractor struct {
+	input   int
+	indices int
+	output  int
+}
+
+func (a *ArrayFeatureExtractor) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 2 {
+		return fmt.Errorf("%s: the input and the indices are required", node.OpType)
+	}
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	a.input = input
+	indices, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	a.indices = indices
+	if len(node.Attribute) > 0 {
+		return fmt.Errorf("%s not supported for %s", node.Attribute[0].Name, node.OpType)
+	}
+	a.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (a *ArrayFeatureExtractor) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(a.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	if len(input.Shape) == 0 || len(input.Shape) > 2 {
+		return fmt.Errorf("arrayfeatureextractor: invalid shape %v", input.Shape)
+	}
+	i, err := k.Input(a.indices)
+	if err != nil {
+		return err
+	}
+	indices, err := intValues(i.Tensor, "arrayfeatureextractor")
+	if err != nil {
+		return err
+	}
+	rows, cols := matrixShape(input.Shape)
+	for _, index := range indices {
+		if index < 0 || index >= int64(cols) {
+			return fmt.Errorf("arrayfeatureextractor: index %d is out of range for %d columns", index, cols)
+		}
+	}
+	output, err := k.Output(a.output, []int{rows, len(indices)}, input.DType)
+	if err != nil {
+		return err
+	}
+	switch input.DType {
+	case tensor.Float:
+		extract(input.FloatData, output.FloatData, rows, cols, indices)
+	case tensor.Double:
+		extract(input.DoubleData, output.DoubleData, rows, cols, indices)
+	case tensor.Int32:
+		extract(input.Int32Data, output.Int32Data, rows, cols, indices)
+	case tensor.Int64:
+		extract(input.Int64Data, output.Int64Data, rows, cols, indices)
+	case tensor.String:
+		extract(input.StringData, output.StringData, rows, cols, indices)
+	default:
+		return fmt.Errorf("arrayfeatureextractor: input datatype (%v) is invalid", input.DType)
+	}
+	return nil
+}
+
+func extract[T any](in, out []T, rows, cols int, indices []int64) {
+	for row := range rows {
+		for i, index := range indices {
+			out[row*len(indices)+i] = in[row*cols+int(index)]
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package ops
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Binary runs the element-wise Add, Sub, Mul, Div, Pow, Equal, Greater and Less ops. The inputs
+// are broadcast against each other the numpy way, the comparisons output a Bool tensor.
+type Binary struct {
+	op     string
+	a      int
+	b      int
+	output int
+}
+
+func (o *Binary) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	switch node.OpType {
+	case "Add", "Sub", "Mul", "Div", "Pow", "Equal", "Greater", "Less":
+	default:
+		return fmt.Errorf("%s is not an element-wise binary op", node.OpType)
+	}
+	o.op = node.OpType
+	if len(node.Input) != 2 {
+		return fmt.Errorf("%s: 2 inputs are required, got %d", node.OpType, len(node.Input))
+	}
+	a, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	o.a = a
+	b, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	o.b = b
+	if len(node.Attribute) > 0 {
+		return fmt.Errorf("%s not supported for %s", node.Attribute[0].Name, node.OpType)
+	}
+	o.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (o *Binary) Compute(k *kernel.Kernel) error {
+	name := strings.ToLower(o.op)
+	a, err := k.Input(o.a)
+	if err != nil {
+		return err
+	}
+	b, err := k.Input(o.b)
+	if err != nil {
+		return err
+	}
+	x, y := a.Tensor, b.Tensor
+	if x.DType != y.DType {
+		return fmt.Errorf("%s: inputs have different datatypes %v and %v", name, x.DType, y.DType)
+	}
+	shape, err := broadcastShape(x.Shape, y.Shape)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	if o.op == "Equal" {
+		output, err := k.Output(o.output, shape, tensor.Bool)
+		if err != nil {
+			return err
+		}
+		switch x.DType {
+		case tensor.Float:
+			broadcast(x.FloatData, x.Shape, y.FloatData, y.Shape, output.BoolData, shape, equal)
+		case tensor.Double:
+			broadcast(x.DoubleData, x.Shape, y.DoubleData, y.Shape, output.BoolData, shape, equal)
+		case tensor.Int32:
+			broadcast(x.Int32Data, x.Shape, y.Int32Data, y.Shape, output.BoolData, shape, equal)
+		case tensor.Int64:
+			broadcast(x.Int64Data, x.Shape, y.Int64Data, y.Shape, output.BoolData, shape, equal)
+		case tensor.Bool:
+			broadcast(x.BoolData, x.Shape, y.BoolData, y.Shape, output.BoolData, shape, equal)
+		case tensor.String:
+			broadcast(x.StringData, x.Shape, y.StringData, y.Shape, output.BoolData, shape, bytes.Equal)
+		default:
+			return fmt.Errorf("%s: input datatype (%v) is invalid", name, x.DType)
+		}
+		return nil
+	}
+	if o.op == "Greater" || o.op == "Less" {
+		output, err := k.Output(o.output, shape, tensor.Bool)
+		if err != nil {
+			return err
+		}
+		switch x.DType {
+		case tensor.Float:
+			broadcast(x.FloatData, x.Shape, y.FloatData, y.Shape, output.BoolData, shape, compare[float32](o.op))
+		case tensor.Double:
+			broadcast(x.DoubleData, x.Shape, y.DoubleData, y.Shape, output.BoolData, shape, compare[float64](o.op))
+		case tensor.Int32:
+			broadcast(x.Int32Data, x.Shape, y.Int32Data, y.Shape, output.BoolData, shape, compare[int32](o.op))
+		case tensor.Int64:
+			broadcast(x.Int64Data, x.Shape, y.Int64Data, y.Shape, output.BoolData, shape, compare[int64](o.op))
+		default:
+			return fmt.Errorf("%s: input datatype (%v) is invalid", name, x.DType)
+		}
+		return nil
+	}
+
+	output, err := k.Output(o.output, shape, x.DType)
+	if err != nil {
+		return err
+	}
+	switch x.DType {
+	case tensor.Float:
+		broadcast(x.FloatData, x.Shape, y.FloatData, y.Shape, output.FloatData, shape, arithmetic[float32](o.op))
+	case tensor.Double:
+		broadcast(x.DoubleData, x.Shape, y.DoubleData, y.Shape, output.DoubleData, shape, arithmetic[float64](o.op))
+	case tensor.Int32:
+		if o.op == "Div" && hasZero(y.Int32Data[:tensorSize(y)]) {
+			return fmt.Errorf("%s: integer division by zero", name)
+		}
+		broadcast(x.Int32Data, x.Shape, y.Int32Data, y.Shape, output.Int32Data, shape, arithmetic[int32](o.op))
+	case tensor.Int64:
+		if o.op == "Div" && hasZero(y.Int64Data[:tensorSize(y)]) {
+			return fmt.Errorf("%s: integer division by zero", name)
+		}
+		broadcast(x.Int64Data, x.Shape, y.Int64Data, y.Shape, output.Int64Data, shape, arithmetic[int64](o.op))
+	default:
+		return fmt.Errorf("%s: input datatype (%v) is invalid", name, x.DType)
+	}
+	return nil
+}
+
+func arithmetic[T tensor.Numeric](op string) func(T, T) T {
+	switch op {
+	case "Add":
+		return func(a, b T) T { return a + b }
+	case "Sub":
+		return func(a, b T) T { return a - b }
+	case "Mul":
+		return func(a, b T) T { return a * b }
+	case "Pow":
+		return func(a, b T) T { return T(math.Pow(float64(a), float64(b))) }
+	}
+	return func(a, b T) T { return a / b }
+}
+
+func compare[T tensor.Numeric](op string) func(T, T) bool {
+	if op == "Greater" {
+		return func(a, b T) bool { return a > b }
+	}
+	return func(a, b T) bool { return a < b }
+}
+
+func equal[T comparable](a, b T) bool {
+	return a == b
+}
+
+func hasZero[T tensor.Numeric](values []T) bool {
+	for _, v := range values {
+		if v == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the shape two tensors are broadcast to. Their dimensions are aligned from the right, and
+// each pair has to be equal or contain a 1.
+func broadcastShape(a, b []int) ([]int, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return nil, fmt.Errorf("shapes %v and %v should have at least 1 dimension", a, b)
+	}
+	shape := make([]int, max(len(a), len(b)))
+	for i := range shape {
+		da, db := 1, 1
+		if i < len(a) {
+			da = a[len(a)-1-i]
+		}
+		if i < len(b) {
+			db = b[len(b)-1-i]
+		}
+		if da != db && da != 1 && db != 1 {
+			return nil, fmt.Errorf("shapes %v and %v cannot be broadcast together", a, b)
+		}
+		d := max(da, db)
+		if da == 0 || db == 0 {
+			d = 0
+		}
+		shape[len(shape)-1-i] = d
+	}
+	return shape, nil
+}
+
+// Returns a shape of at most 2 dimensions as rows and columns, a vector is a single row
+func matrixShape(shape []int) (int, int) {
+	if len(shape) == 1 {
+		return 1, shape[0]
+	}
+	return shape[0], shape[1]
+}
+
+// Applies f to every pair of elements of a and b broadcast to shape
+func broadcast[T, U any](a []T, ashape []int, b []T, bshape []int, out []U, shape []int, f func(T, T) U) {
+	strides := [][]int{broadcastStrides(ashape, shape), broadcastStrides(bshape, shape)}
+	walk(shape, strides, func(i int, offsets []int) {
+		out[i] = f(a[offsets[0]], b[offsets[1]])
+	})
+}
+
+// Returns the strides of a tensor of the given shape when it is walked as one of shape target,
+// their dimensions being aligned from the right. A dimension of length 1 is repeated: its stride
+// is 0.
+func broadcastStrides(shape, target []int) []int {
+	strides := make([]int, len(target))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		if shape[i] != 1 {
+			strides[len(target)-len(shape)+i] = stride
+		}
+		stride *= shape[i]
+	}
+	return strides
+}
+
+// Calls f with the position of every element of a tensor of the given shape, in row-major order,
+// along with the offsets of that element in the tensors walked with the given strides
+func walk(shape []int, strides [][]int, f func(i int, offsets []int)) {
+	index := make([]int, len(shape))
+	offsets := make([]int, len(strides))
+	for i := range tensor.Size(shape) {
+		f(i, offsets)
+		for d := len(shape) - 1; d >= 0; d-- {
+			index[d]++
+			for t := range strides {
+				offsets[t] += strides[t][d]
+			}
+			if index[d] < shape[d] {
+				break
+			}
+			for t := range strides {
+				offsets[t] -= strides[t][d] * shape[d]
+			}
+			index[d] = 0
+		}
+	}
+}
+
+// Unary runs the element-wise Sqrt, Exp, Log, Neg, Reciprocal, Relu, Tanh and Sigmoid ops
+type Unary struct {
+	op     string
+	input  int
+	output int
+}
+
+func (o *Unary) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	switch node.OpType {
+	case "Sqrt", "Exp", "Log", "Neg", "Reciprocal", "Relu", "Tanh", "Sigmoid":
+	default:
+		return fmt.Errorf("%s is not an element-wise unary op", node.OpType)
+	}
+	o.op = node.OpType
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	o.input = input
+	if len(node.Attribute) > 0 {
+		return fmt.Errorf("%s not supported for %s", node.Attribute[0].Name, node.OpType)
+	}
+	o.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (o *Unary) Compute(k *kernel.Kernel) error {
+	name := strings.ToLower(o.op)
+	data, err := k.Input(o.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	if o.op != "Neg" && o.op != "Relu" && input.DType != tensor.Float && input.DType != tensor.Double {
+		return fmt.Errorf("%s: input datatype (%v) is invalid", name, input.DType)
+	}
+	output, err := k.Output(o.output, slices.Clone(input.Shape), input.DType)
+	if err != nil {
+		return err
+	}
+	size := tensorSize(input)
+	switch input.DType {
+	case tensor.Float:
+		unary(input.FloatData[:size], output.FloatData, o.op)
+	case tensor.Double:
+		unary(input.DoubleData[:size], output.DoubleData, o.op)
+	case tensor.Int32:
+		unary(input.Int32Data[:size], output.Int32Data, o.op)
+	case tensor.Int64:
+		unary(input.Int64Data[:size], output.Int64Data, o.op)
+	default:
+		return fmt.Errorf("%s: input datatype (%v) is invalid", name, input.DType)
+	}
+	return nil
+}
+
+func unary[T tensor.Numeric](in, out []T, op string) {
+	for i, v := range in {
+		switch op {
+		case "Sqrt":
+			out[i] = T(math.Sqrt(float64(v)))
+		case "Exp":
+			out[i] = T(math.Exp(float64(v)))
+		case "Log":
+			out[i] = T(math.Log(float64(v)))
+		case "Reciprocal":
+			out[i] = 1 / v
+		case "Relu":
+			out[i] = max(v, 0)
+		case "Tanh":
+			out[i] = T(math.Tanh(float64(v)))
+		case "Sigmoid":
+			out[i] = T(tensor.ComputeLogistic(float64(v)))
+		default:
+			out[i] = -v
+		}
+	}
+}
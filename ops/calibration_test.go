@@ -0,0 +1,90 @@
+package ops_test
+
+import (
+	"testing"
+)
+
+/*
+ * The calibrated classifiers wrap LinearClassifiers fitted on iris, one per fold for
+ * the sigmoid calibration. The models are built by examples/generate with the graphs skl2onnx gives
+ * the calibrators, the references are computed there in double precision from the attributes of
+ * the graphs.
+ */
+
+// The calibrated values of the last sample are all 0, so it gets the same probability for every class
+var isotonicInput = append(knnInput[:len(knnInput):len(knnInput)], []float32{7, 5, 5, 1.5})
+
+func TestCalibratedClassifierSigmoid(t *testing.T) {
+	g := loadExample(t, "../examples/calibrated_sigmoid.onnx")
+	result, err := g.Execute([]any{knnInput})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	labels, ok := result[0].([]int64)
+	if !ok {
+		t.Fatalf("Unexpected label type: %T", result[0])
+	}
+	expected := []int64{0, 1, 2, 2, 2}
+	for i := range expected {
+		if labels[i] != expected[i] {
+			t.Errorf("sample %d: expected label %d, got %d", i, expected[i], labels[i])
+		}
+	}
+	// The mean of the normalised probabilities of the two folds
+	checkMatrix(t, result[1], [][]float64{
+		{0.9930049, 0.006976177, 1.896040e-05},
+		{0.001703124, 0.5218901, 0.4764068},
+		{1.775298e-05, 0.01563500, 0.9843472},
+		{0.003014353, 0.2026332, 0.7943524},
+		{0.0001223715, 0.04509895, 0.9547787},
+	}, "probability")
+}
+
+func TestCalibratedClassifierIsotonic(t *testing.T) {
+	g := loadExample(t, "../examples/calibrated_isotonic.onnx")
+	result, err := g.Execute([]any{isotonicInput})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	checkStringLabels(t, result[0], []string{"setosa", "versicolor", "virginica", "virginica", "virginica", "setosa"})
+	checkMatrix(t, result[1], [][]float64{
+		{0.9394441, 0.06055591, 0},
+		{0, 0.5243445, 0.4756555},
+		{0, 0.09764197, 0.9023580},
+		{0, 0.3611738, 0.6388262},
+		{0, 0.1361816, 0.8638184},
+		{1.0 / 3, 1.0 / 3, 1.0 / 3},
+	}, "probability")
+}
+
+// The probability of virginica, calibrated with a sigmoid of the decision function
+var calibratedPositive = []float64{5.771133e-05, 0.1797562, 0.9778171, 0.2983851, 0.7419254}
+
+func TestCalibratedClassifierBinary(t *testing.T) {
+	g := loadExample(t, "../examples/calibrated_binary.onnx")
+	result, err := g.Execute([]any{knnInput})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	checkStringLabels(t, result[0], []string{"versicolor", "versicolor", "virginica", "versicolor", "virginica"})
+	probabilities := make([][]float64, len(calibratedPositive))
+	for i, p := range calibratedPositive {
+		probabilities[i] = []float64{1 - p, p}
+	}
+	checkMatrix(t, result[1], probabilities, "probability")
+}
+
+func TestTunedThresholdClassifier(t *testing.T) {
+	g := loadExample(t, "../examples/tuned_threshold.onnx")
+	result, err := g.Execute([]any{knnInput})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	// The threshold is 0.25, the fourth sample is virginica although its probability is below 0.5
+	checkStringLabels(t, result[0], []string{"versicolor", "versicolor", "virginica", "virginica", "virginica"})
+	probabilities := make([][]float64, len(calibratedPositive))
+	for i, p := range calibratedPositive {
+		probabilities[i] = []float64{1 - p, p}
+	}
+	checkMatrix(t, result[1], probabilities, "probability")
+}
@@ -0,0 +1,101 @@
+package ops
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// CDist is the com.microsoft op computing the distances between every row of A and every row
+// of B. sklearn-onnx uses it for nearest neighbors models exported with the cdist optimisation,
+// and for the RBF kernel of KernelPCA. The distances are computed like the RBF kernel of the SVMs.
+type CDist struct {
+	a         int
+	b         int
+	euclidean bool      // the square root of the squared euclidean distance is taken
+	norms     []float64 // squared norms of the rows of B when it is a constant, like the training samples
+	dots      *tensor.Tensor
+	workers   int // goroutines the dot products are split over, the DotWorkers option
+	output    int
+}
+
+func (c *CDist) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 2 {
+		return fmt.Errorf("%s: 2 inputs are required, got %d", node.OpType, len(node.Input))
+	}
+	a, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	c.a = a
+	b, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	c.b = b
+	c.euclidean = false
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "metric":
+			switch string(attr.S) {
+			case "euclidean":
+				c.euclidean = true
+			case "sqeuclidean":
+				c.euclidean = false
+			default:
+				return fmt.Errorf("%s: metric %s is not supported", node.OpType, attr.S)
+			}
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	if t := k.Get(b); t != nil && len(t.Shape) == 2 && (t.DType == tensor.Float || t.DType == tensor.Double) {
+		c.norms = squaredNorms(t)
+	}
+	c.workers = k.Options.DotWorkers
+	c.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (c *CDist) Compute(k *kernel.Kernel) error {
+	a, err := k.Input(c.a)
+	if err != nil {
+		return err
+	}
+	b, err := k.Input(c.b)
+	if err != nil {
+		return err
+	}
+	x, y := a.Tensor, b.Tensor
+	if x.DType != y.DType {
+		return fmt.Errorf("cdist: inputs have different datatypes %v and %v", x.DType, y.DType)
+	}
+	if len(x.Shape) != 2 || len(y.Shape) != 2 || x.Shape[1] != y.Shape[1] {
+		return fmt.Errorf("cdist: inputs should be matrices with the same number of columns, got %v and %v", x.Shape, y.Shape)
+	}
+	output, err := k.Output(c.output, []int{x.Shape[0], y.Shape[0]}, x.DType)
+	if err != nil {
+		return err
+	}
+	if x.DType != tensor.Float && x.DType != tensor.Double {
+		return fmt.Errorf("cdist: input datatype (%v) is invalid", x.DType)
+	}
+	norms := c.norms
+	if norms == nil {
+		norms = squaredNorms(y)
+	}
+	c.dots = squaredDistances(x, y, norms, c.dots, c.workers, func(index int, distance float64) {
+		if c.euclidean {
+			distance = math.Sqrt(distance)
+		}
+		if output.DType == tensor.Double {
+			output.DoubleData[index] = distance
+		} else {
+			output.FloatData[index] = float32(distance)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,128 @@
+package ops
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Clip limits the input to the [min, max] interval. The bounds are optional scalar inputs, or
+// attributes in the exports of opset 6.
+type Clip struct {
+	input  int
+	min    int // -1 when the bound isn't an input
+	max    int
+	low    float64
+	high   float64
+	output int
+}
+
+func (c *Clip) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) < 1 || len(node.Input) > 3 {
+		return fmt.Errorf("%s: 1 to 3 inputs are required, got %d", node.OpType, len(node.Input))
+	}
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	c.input = input
+	c.min, c.max = -1, -1
+	for i, bound := range []*int{&c.min, &c.max} {
+		if len(node.Input) > i+1 && node.Input[i+1] != "" {
+			*bound, err = k.RegisterReader(node.Input[i+1])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	c.low, c.high = math.Inf(-1), math.Inf(1)
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "min":
+			c.low = float64(attr.F)
+		case "max":
+			c.high = float64(attr.F)
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	c.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (c *Clip) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(c.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	low, err := c.bound(k, c.min, c.low)
+	if err != nil {
+		return err
+	}
+	high, err := c.bound(k, c.max, c.high)
+	if err != nil {
+		return err
+	}
+	output, err := k.Output(c.output, slices.Clone(input.Shape), input.DType)
+	if err != nil {
+		return err
+	}
+	size := tensorSize(input)
+	switch input.DType {
+	case tensor.Float:
+		clip(input.FloatData[:size], output.FloatData, low, high)
+	case tensor.Double:
+		clip(input.DoubleData[:size], output.DoubleData, low, high)
+	case tensor.Int32:
+		clip(input.Int32Data[:size], output.Int32Data, low, high)
+	case tensor.Int64:
+		clip(input.Int64Data[:size], output.Int64Data, low, high)
+	default:
+		return fmt.Errorf("clip: input datatype (%v) is invalid", input.DType)
+	}
+	return nil
+}
+
+// Returns the value of a bound input, or the attribute when there is no input
+func (c *Clip) bound(k *kernel.Kernel, index int, attribute float64) (float64, error) {
+	if index < 0 {
+		return attribute, nil
+	}
+	data, err := k.Input(index)
+	if err != nil {
+		return 0, err
+	}
+	t := data.Tensor
+	if tensorSize(t) != 1 {
+		return 0, fmt.Errorf("clip: bounds should be scalars, got shape %v", t.Shape)
+	}
+	switch t.DType {
+	case tensor.Float:
+		return float64(t.FloatData[0]), nil
+	case tensor.Double:
+		return t.DoubleData[0], nil
+	case tensor.Int32:
+		return float64(t.Int32Data[0]), nil
+	case tensor.Int64:
+		return float64(t.Int64Data[0]), nil
+	}
+	return 0, fmt.Errorf("clip: bound datatype (%v) is invalid", t.DType)
+}
+
+func clip[T tensor.Numeric](in, out []T, low, high float64) {
+	for i, v := range in {
+		switch {
+		case float64(v) < low:
+			out[i] = T(low)
+		case float64(v) > high:
+			out[i] = T(high)
+		default:
+			out[i] = v
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package ops
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/systemEng-Learning/go-ml-deployment/tensor"
@@ -24,6 +25,24 @@ var postTransformMap = map[string]postTransform{
 	"SOFTMAX_ZERO": SOFTMAX_ZERO,
 }
 
+// Returns a [rows, features] view of the input of a linear model. The tensor read from the kernel
+// is shared with the other readers of the value, so neither its shape nor its data is changed.
+func linearInput(input *tensor.Tensor, op string) (*tensor.Tensor, error) {
+	if len(input.Shape) == 0 || len(input.Shape) > 2 {
+		return nil, fmt.Errorf("%s: invalid shape %v", op, input.Shape)
+	}
+	switch input.DType {
+	case tensor.Float, tensor.Double, tensor.Int32, tensor.Int64:
+	default:
+		return nil, fmt.Errorf("%s: input datatype (%v) is invalid", op, input.DType)
+	}
+	view := *input
+	if len(view.Shape) == 1 {
+		view.Shape = []int{1, view.Shape[0]}
+	}
+	return &view, nil
+}
+
 func update_scores[T tensor.Float32_64](scores []T, shape []int, post_transform postTransform, add_second_class int, have_space bool) {
 	rows := shape[0]
 	cols := shape[1]
@@ -0,0 +1,104 @@
+package ops_test
+
+import (
+	"math"
+	"testing"
+)
+
+// The reference projections of the samples, computed in double precision by examples/generate,
+// which builds the models with the graphs skl2onnx gives the transformers. They are fitted on the
+// iris samples of the examples, the kernels of KernelPCA have gamma = 0.25, and the polynomial one
+// coef0 = 1 and degree = 3.
+var decompositionCases = []struct {
+	name     string
+	path     string
+	expected [][]float64
+}{
+	{
+		"PCA", "../examples/pca.onnx",
+		[][]float64{
+			{-1.25024, 0.3883239},
+			{0.350175, -0.2642333},
+			{0.9900511, 0.02931545},
+			{0.3041703, -0.444764},
+			{0.5682612, -1.016543},
+		},
+	},
+	{
+		"IncrementalPCA", "../examples/incremental_pca.onnx",
+		[][]float64{
+			{-2.760268, 0.1661022, 0.1334146},
+			{0.7731131, -0.1130231, -0.2714437},
+			{2.185826, 0.01253966, 0.2448644},
+			{0.6715444, -0.1902434, -0.05537182},
+			{1.254602, -0.4348164, 0.1966951},
+		},
+	},
+	{
+		"TruncatedSVD", "../examples/truncated_svd.onnx",
+		[][]float64{
+			{5.822994, -2.22012},
+			{8.263776, 0.3584834},
+			{9.501416, 1.089891},
+			{8.173941, 0.3094573},
+			{8.501852, 0.8255434},
+		},
+	},
+	{
+		"GaussianRandomProjection", "../examples/gaussian_random_projection.onnx",
+		[][]float64{
+			{-3.190827, -0.8771259, -1.490034},
+			{-0.8137591, -2.137064, -2.728952},
+			{-0.1612519, -2.589919, -2.524765},
+			{-0.8310656, -2.087143, -2.409921},
+			{-0.032663, -2.284075, -2.242724},
+		},
+	},
+	{
+		"KernelPCA/rbf", "../examples/kernel_pca_rbf.onnx",
+		[][]float64{
+			{0.8506284, 0.01207424},
+			{-0.4170099, -0.4256337},
+			{-0.4626377, 0.4395704},
+			{-0.4078378, -0.4479919},
+			{-0.4767012, -0.06571749},
+		},
+	},
+	{
+		"KernelPCA/poly", "../examples/kernel_pca_poly.onnx",
+		[][]float64{
+			{-48.85873, 1.420328},
+			{4.99642, 1.388272},
+			{46.29004, -3.714914},
+			{2.522693, -0.4247316},
+			{14.11336, -7.068538},
+		},
+	},
+}
+
+func TestDecomposition(t *testing.T) {
+	for _, c := range decompositionCases {
+		t.Run(c.name, func(t *testing.T) {
+			g := loadExample(t, c.path)
+			result, err := g.Execute([]any{knnInput})
+			if err != nil {
+				t.Fatalf("Error executing graph: %v", err)
+			}
+			output, ok := result[0].([][]float32)
+			if !ok {
+				t.Fatalf("Unexpected output type: %T", result[0])
+			}
+			if len(output) != len(c.expected) {
+				t.Fatalf("expected %d rows, got %d", len(c.expected), len(output))
+			}
+			for i := range c.expected {
+				for j, expected := range c.expected[i] {
+					// The polynomial kernel reaches thousands before it is centred, in single precision
+					if math.Abs(float64(output[i][j])-expected) > 1e-4*math.Max(1, math.Abs(expected)) {
+						t.Errorf("sample %d: expected %v in column %d, got %v", i, expected, j, output[i][j])
+					}
+				}
+			}
+		})
+	}
+}
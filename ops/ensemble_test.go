@@ -0,0 +1,127 @@
+package ops_test
+
+import (
+	"math"
+	"testing"
+)
+
+/*
+ * The ensembles combine a Scaler + multinomial LinearClassifier pipeline, a depth 2
+ * TreeEnsembleClassifier and, for hard voting, a linear SVMClassifier, all labelled with the iris
+ * species. Every sub-estimator reads the same input. The models are built by examples/generate with
+ * the graphs skl2onnx gives the ensembles, the references are computed there in double precision
+ * from the attributes of the graphs.
+ */
+
+// The last two samples split the hard vote, each estimator picks another species for the last one
+var votingInput = [][]float64{
+	{5.0, 3.4, 1.5, 0.2},
+	{6.1, 2.8, 4.7, 1.2},
+	{6.7, 3.1, 5.6, 2.4},
+	{6.0, 2.9, 4.5, 1.5},
+	{5.9, 3.0, 5.1, 1.8},
+	{6.5, 3.0, 2.4, 1.9},
+	{4.5, 2.0, 2.5, 1.8},
+}
+
+func checkStringLabels(t *testing.T, result any, expected []string) {
+	t.Helper()
+	labels, ok := result.([]string)
+	if !ok {
+		t.Fatalf("Unexpected label type: %T", result)
+	}
+	if len(labels) != len(expected) {
+		t.Fatalf("expected %d labels, got %d", len(expected), len(labels))
+	}
+	for i := range expected {
+		if labels[i] != expected[i] {
+			t.Errorf("sample %d: expected label %s, got %s", i, expected[i], labels[i])
+		}
+	}
+}
+
+func checkMatrix(t *testing.T, result any, expected [][]float64, name string) {
+	t.Helper()
+	values, ok := result.([][]float32)
+	if !ok {
+		t.Fatalf("Unexpected %s type: %T", name, result)
+	}
+	if len(values) != len(expected) {
+		t.Fatalf("expected %d rows of %s, got %d", len(expected), name, len(values))
+	}
+	for i := range expected {
+		for j, e := range expected[i] {
+			if math.Abs(float64(values[i][j])-e) > 1e-5 {
+				t.Errorf("sample %d: expected %s %v in column %d, got %v", i, name, e, j, values[i][j])
+			}
+		}
+	}
+}
+
+func TestVotingClassifierSoft(t *testing.T) {
+	g := loadExample(t, "../examples/voting_classifier_soft.onnx")
+	result, err := g.Execute([]any{knnInput})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	checkStringLabels(t, result[0], []string{"setosa", "versicolor", "virginica", "versicolor", "virginica"})
+	// The weights are 2 for the pipeline and 1 for the tree
+	checkMatrix(t, result[1], [][]float64{
+		{0.9415967, 0.04508235, 0.01332099},
+		{0.03967710, 0.6748438, 0.2854791},
+		{0.005089113, 0.2752829, 0.7196280},
+		{0.04126465, 0.6501307, 0.3086046},
+		{0.02802411, 0.3277027, 0.6442732},
+	}, "probability")
+}
+
+func TestVotingClassifierHard(t *testing.T) {
+	g := loadExample(t, "../examples/voting_classifier_hard.onnx")
+	// Run twice, the estimators must not leave the shared input changed for the next run
+	for range 2 {
+		result, err := g.Execute([]any{votingInput})
+		if err != nil {
+			t.Fatalf("Error executing graph: %v", err)
+		}
+		// A tie goes to the first species, like sklearn's argmax over the vote counts
+		checkStringLabels(t, result[0], []string{"setosa", "versicolor", "virginica", "versicolor", "virginica", "versicolor", "setosa"})
+		checkMatrix(t, result[1], [][]float64{{3, 0, 0}, {0, 3, 0}, {0, 0, 3}, {0, 3, 0}, {0, 0, 3}, {1, 2, 0}, {1, 1, 1}}, "vote count")
+	}
+}
+
+func TestStackingClassifier(t *testing.T) {
+	g := loadExample(t, "../examples/stacking_classifier.onnx")
+	result, err := g.Execute([]any{knnInput})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	checkStringLabels(t, result[0], []string{"setosa", "versicolor", "virginica", "versicolor", "virginica"})
+	checkMatrix(t, result[1], [][]float64{
+		{0.9865728, 0.01087103, 0.002556180},
+		{0.02337962, 0.9515493, 0.02507107},
+		{0.01787981, 0.06754560, 0.9145746},
+		{0.02520982, 0.9448121, 0.02997804},
+		{0.02715031, 0.1064175, 0.8664322},
+	}, "probability")
+}
+
+func TestEnsembleRegressors(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		expected [][]float64
+	}{
+		{"VotingRegressor", "../examples/voting_regressor.onnx", [][]float64{{0.191}, {1.4265}, {1.955}, {1.3885}, {1.8685}}},
+		{"StackingRegressor", "../examples/stacking_regressor.onnx", [][]float64{{0.1474}, {1.4571}, {1.952}, {1.4039}, {1.8309}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := loadExample(t, c.path)
+			result, err := g.Execute([]any{knnInput})
+			if err != nil {
+				t.Fatalf("Error executing graph: %v", err)
+			}
+			checkMatrix(t, result[0], c.expected, "prediction")
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+/*
+ * Explanation attributes the scores of a model to its input features. For every sample and
+ * output, Bias plus the contributions of the features adds up to the raw score of the model.
+ * The post transform isn't linear, so the transformed scores are given as they are.
+ */
+type Explanation struct {
+	Bias          []float64     // [outputs], the expected value of the model
+	Contributions [][][]float64 // [samples][outputs][features]
+	Scores        [][]float64   // [samples][outputs], the raw scores
+	Transformed   [][]float64   // [samples][classes], the scores after the post transform, nil if not computed
+}
+
+// Explainer is implemented by the ops that can explain the scores of their last Compute
+type Explainer interface {
+	Explain(k *kernel.Kernel) (*Explanation, error)
+}
+
+func newExplanation(nSamples, nOutputs, nFeatures int) *Explanation {
+	e := &Explanation{
+		Bias:          make([]float64, nOutputs),
+		Contributions: make([][][]float64, nSamples),
+		Scores:        make([][]float64, nSamples),
+	}
+	for i := range e.Contributions {
+		e.Scores[i] = make([]float64, nOutputs)
+		e.Contributions[i] = make([][]float64, nOutputs)
+		for j := range e.Contributions[i] {
+			e.Contributions[i][j] = make([]float64, nFeatures)
+		}
+	}
+	return e
+}
+
+// Sets the scores to the bias plus the contributions
+func (e *Explanation) sumScores() {
+	for i := range e.Scores {
+		for j := range e.Scores[i] {
+			e.Scores[i][j] = e.Bias[j]
+			for _, phi := range e.Contributions[i][j] {
+				e.Scores[i][j] += phi
+			}
+		}
+	}
+}
+
+// Returns the tensor an op read in its last Compute
+func lastInput(k *kernel.Kernel, input int) (*tensor.Tensor, error) {
+	data, err := k.Input(input)
+	if err != nil {
+		return nil, err
+	}
+	if data.Tensor == nil {
+		return nil, fmt.Errorf("the graph has not been executed")
+	}
+	return data.Tensor, nil
+}
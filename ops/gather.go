@@ -0,0 +1,118 @@
+package ops
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Gather takes the entries of an axis of its input at the given indices. The output has the
+// dimensions of the input before the axis, then those of the indices, then those after the axis.
+type Gather struct {
+	input   int
+	indices int
+	axis    int
+	output  int
+}
+
+func (g *Gather) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 2 {
+		return fmt.Errorf("%s: the input and the indices are required", node.OpType)
+	}
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	g.input = input
+	indices, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	g.indices = indices
+	g.axis = 0
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axis":
+			g.axis = int(attr.I)
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	g.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (g *Gather) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(g.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	idx, err := k.Input(g.indices)
+	if err != nil {
+		return err
+	}
+	indices, err := intValues(idx.Tensor, "gather")
+	if err != nil {
+		return err
+	}
+	rank := len(input.Shape)
+	axis := g.axis
+	if axis < -rank || axis >= rank {
+		return fmt.Errorf("gather: axis %d is out of range for shape %v", g.axis, input.Shape)
+	}
+	if axis < 0 {
+		axis += rank
+	}
+	dim := input.Shape[axis]
+	positions := make([]int, len(indices))
+	for i, index := range indices {
+		if index < -int64(dim) || index >= int64(dim) {
+			return fmt.Errorf("gather: index %d is out of range for an axis of length %d", index, dim)
+		}
+		if index < 0 {
+			index += int64(dim)
+		}
+		positions[i] = int(index)
+	}
+
+	shape := slices.Concat(input.Shape[:axis], idx.Tensor.Shape, input.Shape[axis+1:])
+	output, err := k.Output(g.output, shape, input.DType)
+	if err != nil {
+		return err
+	}
+	outer, inner := tensor.Size(input.Shape[:axis]), tensor.Size(input.Shape[axis+1:])
+	switch input.DType {
+	case tensor.Float:
+		gather(input.FloatData, output.FloatData, positions, outer, dim, inner)
+	case tensor.Double:
+		gather(input.DoubleData, output.DoubleData, positions, outer, dim, inner)
+	case tensor.Int32:
+		gather(input.Int32Data, output.Int32Data, positions, outer, dim, inner)
+	case tensor.Int64:
+		gather(input.Int64Data, output.Int64Data, positions, outer, dim, inner)
+	case tensor.String:
+		gather(input.StringData, output.StringData, positions, outer, dim, inner)
+	case tensor.Bool:
+		gather(input.BoolData, output.BoolData, positions, outer, dim, inner)
+	default:
+		return fmt.Errorf("gather: input datatype (%v) is invalid", input.DType)
+	}
+	return nil
+}
+
+// Copies the blocks of inner values at the given positions of the axis, for each of the outer
+// blocks of the input
+func gather[T any](in, out []T, positions []int, outer, dim, inner int) {
+	o := 0
+	for block := range outer {
+		for _, p := range positions {
+			start := (block*dim + p) * inner
+			copy(out[o:o+inner], in[start:start+inner])
+			o += inner
+		}
+	}
+}
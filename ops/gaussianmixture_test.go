@@ -0,0 +1,84 @@
+package ops_test
+
+import (
+	"math"
+	"testing"
+)
+
+// The labels, predict_proba and score_samples of the mixtures, computed in double precision by
+// examples/generate, which builds the models with the graphs skl2onnx gives them. The components
+// of both are the iris classes of the training samples.
+var mixtureCases = []struct {
+	name          string
+	path          string
+	labels        []int64
+	probabilities [][]float64
+	scores        []float64
+}{
+	{
+		"GaussianMixture", "../examples/gaussian_mixture.onnx",
+		[]int64{0, 1, 2, 1, 1},
+		[][]float64{
+			{1, 9.760549e-168, 0},
+			{0, 1, 1.786821e-50},
+			{0, 8.308987e-49, 1},
+			{0, 1, 2.005167e-137},
+			{0, 0.9905385, 0.009461502},
+		},
+		[]float64{6.575792, -79.78150, -23.00318, -3.249824, -68.60217},
+	},
+	{
+		"BayesianGaussianMixture", "../examples/bayesian_gaussian_mixture.onnx",
+		[]int64{0, 1, 2, 1, 2},
+		[][]float64{
+			{1, 1.123207e-79, 2.443182e-57},
+			{0, 0.9995861, 0.0004138955},
+			{0, 5.113482e-34, 1},
+			{0, 0.9999658, 3.418096e-05},
+			{0, 0.0001567015, 0.9998433},
+		},
+		[]float64{5.408868, -5.336022, -2.191833, -1.071231, -4.084053},
+	},
+}
+
+func TestGaussianMixture(t *testing.T) {
+	for _, c := range mixtureCases {
+		t.Run(c.name, func(t *testing.T) {
+			g := loadExample(t, c.path)
+			result, err := g.Execute([]any{knnInput})
+			if err != nil {
+				t.Fatalf("Error executing graph: %v", err)
+			}
+			labels, ok := result[0].([]int64)
+			if !ok {
+				t.Fatalf("Unexpected label type: %T", result[0])
+			}
+			probabilities, ok := result[1].([][]float32)
+			if !ok {
+				t.Fatalf("Unexpected probability type: %T", result[1])
+			}
+			scores, ok := result[2].([][]float32)
+			if !ok {
+				t.Fatalf("Unexpected score type: %T", result[2])
+			}
+			if len(labels) != len(c.labels) || len(probabilities) != len(c.labels) || len(scores) != len(c.labels) {
+				t.Fatalf("expected %d rows, got %d labels, %d probabilities and %d scores", len(c.labels), len(labels), len(probabilities), len(scores))
+			}
+			for i := range c.labels {
+				if labels[i] != c.labels[i] {
+					t.Errorf("sample %d: expected label %d, got %d", i, c.labels[i], labels[i])
+				}
+				for class, expected := range c.probabilities[i] {
+					p := probabilities[i][class]
+					if math.Abs(float64(p)-expected) > 1e-5 {
+						t.Errorf("sample %d: expected probability %v for component %d, got %v", i, expected, class, p)
+					}
+				}
+				// The diagonal graph expands (x - mean)^2 in single precision, which loses a few digits
+				if math.Abs(float64(scores[i][0])-c.scores[i]) > 1e-3*math.Max(1, math.Abs(c.scores[i])) {
+					t.Errorf("sample %d: expected score %v, got %v", i, c.scores[i], scores[i][0])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Gemm computes alpha * A' * B' + beta * C, where A' and B' are A and B transposed when transA
+// and transB are set. C is optional and is broadcast to the shape of the product.
+type Gemm struct {
+	a       int
+	b       int
+	c       int // -1 when there is no C
+	alpha   float64
+	beta    float64
+	trans_a bool
+	trans_b bool
+	output  int
+}
+
+func (g *Gemm) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) < 2 || len(node.Input) > 3 {
+		return fmt.Errorf("%s: 2 or 3 inputs are required, got %d", node.OpType, len(node.Input))
+	}
+	a, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	g.a = a
+	b, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	g.b = b
+	g.c = -1
+	if len(node.Input) > 2 && node.Input[2] != "" {
+		c, err := k.RegisterReader(node.Input[2])
+		if err != nil {
+			return err
+		}
+		g.c = c
+	}
+	g.alpha = 1
+	g.beta = 1
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "alpha":
+			g.alpha = float64(attr.F)
+		case "beta":
+			g.beta = float64(attr.F)
+		case "transA":
+			g.trans_a = attr.I != 0
+		case "transB":
+			g.trans_b = attr.I != 0
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	g.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (g *Gemm) Compute(k *kernel.Kernel) error {
+	a, err := k.Input(g.a)
+	if err != nil {
+		return err
+	}
+	b, err := k.Input(g.b)
+	if err != nil {
+		return err
+	}
+	x, y := a.Tensor, b.Tensor
+	if x.DType != y.DType {
+		return fmt.Errorf("gemm: inputs have different datatypes %v and %v", x.DType, y.DType)
+	}
+	if len(x.Shape) != 2 || len(y.Shape) != 2 {
+		return fmt.Errorf("gemm: shapes %v and %v should have 2 dimensions", x.Shape, y.Shape)
+	}
+	rows, inner := x.Shape[0], x.Shape[1]
+	if g.trans_a {
+		rows, inner = inner, rows
+	}
+	inner_b, cols := y.Shape[0], y.Shape[1]
+	if g.trans_b {
+		inner_b, cols = cols, inner_b
+	}
+	if inner != inner_b {
+		return fmt.Errorf("gemm: shapes %v and %v are not aligned", x.Shape, y.Shape)
+	}
+
+	var z *tensor.Tensor
+	if g.c >= 0 {
+		c, err := k.Input(g.c)
+		if err != nil {
+			return err
+		}
+		z = c.Tensor
+		if z.DType != x.DType {
+			return fmt.Errorf("gemm: C is %v, A and B are %v", z.DType, x.DType)
+		}
+		shape, err := broadcastShape(z.Shape, []int{rows, cols})
+		if err != nil || len(shape) != 2 || shape[0] != rows || shape[1] != cols {
+			return fmt.Errorf("gemm: C of shape %v cannot be broadcast to [%d %d]", z.Shape, rows, cols)
+		}
+	}
+
+	output, err := k.Output(g.output, []int{rows, cols}, x.DType)
+	if err != nil {
+		return err
+	}
+	switch x.DType {
+	case tensor.Float:
+		var c []float32
+		if z != nil {
+			c = z.FloatData
+		}
+		gemm[float32, float64](g, x.FloatData, y.FloatData, c, z, output.FloatData, rows, inner, cols)
+	case tensor.Double:
+		var c []float64
+		if z != nil {
+			c = z.DoubleData
+		}
+		gemm[float64, float64](g, x.DoubleData, y.DoubleData, c, z, output.DoubleData, rows, inner, cols)
+	case tensor.Int32:
+		var c []int32
+		if z != nil {
+			c = z.Int32Data
+		}
+		gemm[int32, int64](g, x.Int32Data, y.Int32Data, c, z, output.Int32Data, rows, inner, cols)
+	case tensor.Int64:
+		var c []int64
+		if z != nil {
+			c = z.Int64Data
+		}
+		gemm[int64, int64](g, x.Int64Data, y.Int64Data, c, z, output.Int64Data, rows, inner, cols)
+	default:
+		return fmt.Errorf("gemm: input datatype (%v) is invalid", x.DType)
+	}
+	return nil
+}
+
+/*
+ * The sums are accumulated in S as in matmul, double precision for floats and int64 for integers.
+ * Integer sums are scaled and added to C in double precision, unless alpha and beta are 1 and they
+ * can stay exact.
+ */
+func gemm[T tensor.Numeric, S int64 | float64](g *Gemm, a, b, c []T, z *tensor.Tensor, out []T, rows, inner, cols int) {
+	// Element (i, p) of A' and (p, j) of B' in the row-major data of A and B
+	a_row, a_col := inner, 1
+	if g.trans_a {
+		a_row, a_col = 1, rows
+	}
+	b_row, b_col := cols, 1
+	if g.trans_b {
+		b_row, b_col = 1, inner
+	}
+	c_rows, c_cols := 0, 0
+	if z != nil {
+		c_rows, c_cols = matrixShape(z.Shape)
+	}
+	unscaled := g.alpha == 1 && (c == nil || g.beta == 1)
+	sums := make([]S, cols)
+	for i := range rows {
+		clear(sums)
+		for p := range inner {
+			v := S(a[i*a_row+p*a_col])
+			for j := range cols {
+				sums[j] += v * S(b[p*b_row+j*b_col])
+			}
+		}
+		for j, sum := range sums {
+			var bias T
+			if c != nil {
+				ci, cj := 0, 0
+				if c_rows > 1 {
+					ci = i
+				}
+				if c_cols > 1 {
+					cj = j
+				}
+				bias = c[ci*c_cols+cj]
+			}
+			if unscaled {
+				out[i*cols+j] = T(sum + S(bias))
+			} else {
+				out[i*cols+j] = T(g.alpha*float64(sum) + g.beta*float64(bias))
+			}
+		}
+	}
+}
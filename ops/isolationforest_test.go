@@ -0,0 +1,47 @@
+package ops_test
+
+import (
+	"math"
+	"testing"
+)
+
+// The example forest is built by examples/generate with 6 trees grown on subsamples of 10 latency
+// and error rate pairs, with contamination="auto" so that its offset_ is -0.5. The graph outputs
+// the label, the decision_function and score_samples, the references are those of the trees
+// computed in double precision.
+func TestIsolationForest(t *testing.T) {
+	input := [][]float32{{1.02, 0.1}, {3.5, 0.8}, {1.3, 0.15}, {0.1, 0.9}, {0.95, 0.11}}
+	expectedLabels := []int64{1, -1, 1, -1, 1}
+	decisionFunction := []float64{0.1393244, -0.1908802, 0.0204976, -0.2125016, 0.1432566}
+	scoreSamples := []float64{-0.3606756, -0.6908802, -0.4795024, -0.7125016, -0.3567434}
+
+	g := loadExample(t, "../examples/isolation_forest.onnx")
+	result, err := g.Execute([]any{input})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	labels, ok := result[0].([]int64)
+	if !ok {
+		t.Fatalf("Unexpected label type: %T", result[0])
+	}
+	scores, ok := result[1].([][]float32)
+	if !ok {
+		t.Fatalf("Unexpected score type: %T", result[1])
+	}
+	samples, ok := result[2].([][]float32)
+	if !ok {
+		t.Fatalf("Unexpected score_samples type: %T", result[2])
+	}
+	for i := range input {
+		if labels[i] != expectedLabels[i] {
+			t.Errorf("sample %d: expected label %d, got %d", i, expectedLabels[i], labels[i])
+		}
+		score := float64(scores[i][0])
+		if math.Abs(score-decisionFunction[i]) > 1e-5 {
+			t.Errorf("sample %d: expected decision_function %v, got %v", i, decisionFunction[i], score)
+		}
+		if math.Abs(float64(samples[i][0])-scoreSamples[i]) > 1e-5 {
+			t.Errorf("sample %d: expected score_samples %v, got %v", i, scoreSamples[i], samples[i][0])
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ops_test
+
+import (
+	"math"
+	"testing"
+)
+
+// The centers of the example models built by examples/generate, the expected labels and distances
+// are computed from them in float64
+var kmeansCenters = [][]float64{{4.86, 3.28, 1.4, 0.2}, {6.46, 2.92, 4.54, 1.44}, {6.4, 2.98, 5.68, 2.1}}
+var miniBatchCenters = [][]float64{{4.9, 3.3, 1.4, 0.2}, {6.4, 2.95, 5.1, 1.75}}
+
+func testKMeans(t *testing.T, path string, centers [][]float64) {
+	g := loadExample(t, path)
+	result, err := g.Execute([]any{knnInput})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	labels, ok := result[0].([]int64)
+	if !ok {
+		t.Fatalf("Unexpected label type: %T", result[0])
+	}
+	scores, ok := result[1].([][]float32)
+	if !ok {
+		t.Fatalf("Unexpected score type: %T", result[1])
+	}
+	if len(labels) != len(knnInput) || len(scores) != len(knnInput) {
+		t.Fatalf("expected %d rows, got %d labels and %d scores", len(knnInput), len(labels), len(scores))
+	}
+	for i, x := range knnInput {
+		closest := int64(0)
+		distances := make([]float64, len(centers))
+		for c, center := range centers {
+			for f := range x {
+				d := float64(x[f]) - center[f]
+				distances[c] += d * d
+			}
+			distances[c] = math.Sqrt(distances[c])
+			if distances[c] < distances[closest] {
+				closest = int64(c)
+			}
+			if math.Abs(float64(scores[i][c])-distances[c]) > 1e-4 {
+				t.Errorf("sample %d: expected distance %v to cluster %d, got %v", i, distances[c], c, scores[i][c])
+			}
+		}
+		if labels[i] != closest {
+			t.Errorf("sample %d: expected cluster %d, got %d", i, closest, labels[i])
+		}
+	}
+}
+
+func TestKMeans(t *testing.T) {
+	testKMeans(t, "../examples/kmeans.onnx", kmeansCenters)
+}
+
+func TestMiniBatchKMeans(t *testing.T) {
+	testKMeans(t, "../examples/minibatch_kmeans.onnx", miniBatchCenters)
+}
@@ -0,0 +1,140 @@
+package ops_test
+
+import (
+	"math"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/graph"
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"google.golang.org/protobuf/proto"
+)
+
+// The 15 iris samples the example KNN models keep as their training set, with k = 3. The models
+// are built by examples/generate with the graphs skl2onnx gives KNeighborsClassifier and
+// KNeighborsRegressor. The classifier uses all four features and goes through CDist, the
+// regressor predicts the petal width from the other three with ReduceSumSquare and MatMul.
+var knnTrain = [][]float64{
+	{5.1, 3.5, 1.4, 0.2}, {4.9, 3.0, 1.4, 0.2}, {4.7, 3.2, 1.3, 0.2}, {4.6, 3.1, 1.5, 0.2}, {5.0, 3.6, 1.4, 0.2},
+	{7.0, 3.2, 4.7, 1.4}, {6.4, 3.2, 4.5, 1.5}, {6.9, 3.1, 4.9, 1.5}, {5.5, 2.3, 4.0, 1.3}, {6.5, 2.8, 4.6, 1.5},
+	{6.3, 3.3, 6.0, 2.5}, {5.8, 2.7, 5.1, 1.9}, {7.1, 3.0, 5.9, 2.1}, {6.3, 2.9, 5.6, 1.8}, {6.5, 3.0, 5.8, 2.2},
+}
+var knnClasses = []int64{0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2}
+
+var knnInput = [][]float32{
+	{5.0, 3.4, 1.5, 0.2},
+	{6.1, 2.8, 4.7, 1.2},
+	{6.7, 3.1, 5.6, 2.4},
+	{6.0, 2.9, 4.5, 1.5},
+	{5.9, 3.0, 5.1, 1.8},
+}
+
+func loadExample(t *testing.T, path string) *graph.Graph {
+	in, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading file: %v", err)
+	}
+	model := &ir.ModelProto{}
+	if err := proto.Unmarshal(in, model); err != nil {
+		t.Fatalf("Failed to parse model file: %v", err)
+	}
+	g := &graph.Graph{}
+	if err := g.Init(model.GetGraph()); err != nil {
+		t.Fatalf("Failed to initialize graph: %v", err)
+	}
+	return g
+}
+
+// Returns the indices of the 3 training samples closest to x over the first len(x) features. The
+// expected predictions are computed from them in float64, as KNeighbors* predicts.
+func nearest(x []float32) []int {
+	distances := make([]float64, len(knnTrain))
+	for i, row := range knnTrain {
+		for f := range x {
+			d := float64(x[f]) - row[f]
+			distances[i] += d * d
+		}
+	}
+	order := make([]int, len(knnTrain))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if distances[a] < distances[b] {
+			return -1
+		} else if distances[a] > distances[b] {
+			return 1
+		}
+		return 0
+	})
+	return order[:3]
+}
+
+func TestKNeighborsClassifier(t *testing.T) {
+	g := loadExample(t, "../examples/knn_classifier.onnx")
+	result, err := g.Execute([]any{knnInput})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	labels, ok := result[0].([]int64)
+	if !ok {
+		t.Fatalf("Unexpected label type: %T", result[0])
+	}
+	probabilities, ok := result[1].([]map[int64]float32)
+	if !ok {
+		t.Fatalf("Unexpected probability type: %T", result[1])
+	}
+	for i, x := range knnInput {
+		votes := make([]float32, 3)
+		for _, neighbor := range nearest(x) {
+			votes[knnClasses[neighbor]]++
+		}
+		best := int64(0)
+		for c := range votes {
+			if votes[c] > votes[best] {
+				best = int64(c)
+			}
+			if p := probabilities[i][int64(c)]; math.Abs(float64(p-votes[c]/3)) > 1e-6 {
+				t.Errorf("sample %d: expected probability %v for class %d, got %v", i, votes[c]/3, c, p)
+			}
+		}
+		if labels[i] != best {
+			t.Errorf("sample %d: expected label %d, got %d", i, best, labels[i])
+		}
+	}
+}
+
+func TestKNeighborsRegressor(t *testing.T) {
+	g := loadExample(t, "../examples/knn_regressor.onnx")
+	input := make([][]float32, len(knnInput))
+	for i, x := range knnInput {
+		input[i] = x[:3]
+	}
+	result, err := g.Execute([]any{input})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	predictions, ok := result[0].([][]float32)
+	if !ok {
+		t.Fatalf("Unexpected output type: %T", result[0])
+	}
+	for i, x := range input {
+		expected := float64(0)
+		for _, neighbor := range nearest(x) {
+			expected += knnTrain[neighbor][3] / 3
+		}
+		if math.Abs(float64(predictions[i][0])-expected) > 1e-5 {
+			t.Errorf("sample %d: expected %v, got %v", i, expected, predictions[i][0])
+		}
+	}
+
+	// The graph can be run again with another batch size
+	result, err = g.Execute([]any{input[:2]})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	if len(result[0].([][]float32)) != 2 {
+		t.Fatalf("expected 2 predictions, got %v", result[0])
+	}
+}
@@ -8,6 +8,13 @@ import (
 	"github.com/systemEng-Learning/go-ml-deployment/tensor"
 )
 
+type multiClass int
+
+const (
+	multiClassOVR multiClass = iota
+	multiClassMultinomial
+)
+
 type LinearClassifier struct {
 	input             int
 	num_targets       int
@@ -16,7 +23,7 @@ type LinearClassifier struct {
 	classlabel_string [][]byte
 	coefficients      *tensor.Tensor
 	intercepts        *tensor.Tensor
-	multiclass        bool
+	multi_class       multiClass
 	post_transform    postTransform
 	outputs           []int
 }
@@ -27,7 +34,7 @@ func (l *LinearClassifier) Init(k *kernel.Kernel, node *ir.NodeProto) error {
 		return err
 	}
 	l.input = input
-	l.multiclass = false
+	l.multi_class = multiClassOVR
 	l.post_transform = NONE
 	using_strings := false
 
@@ -43,8 +50,13 @@ func (l *LinearClassifier) Init(k *kernel.Kernel, node *ir.NodeProto) error {
 		case "intercepts":
 			l.intercepts = tensor.Create1DDoubleTensorFromFloat(attr.Floats)
 		case "multi_class":
-			if attr.I > 0 {
-				l.multiclass = true
+			switch attr.I {
+			case 0:
+				l.multi_class = multiClassOVR
+			case 1:
+				l.multi_class = multiClassMultinomial
+			default:
+				return fmt.Errorf("linearclassifier: multi_class %d not supported", attr.I)
 			}
 		case "post_transform":
 			l.post_transform = postTransformMap[string(attr.S)]
@@ -77,13 +89,9 @@ func (l *LinearClassifier) Compute(k *kernel.Kernel) error {
 	if err != nil {
 		return err
 	}
-	input := data.Tensor
-	if len(input.Shape) > 2 {
-		return fmt.Errorf("linearclassifier: invalid shape %v", input.Shape)
-	}
-
-	if len(input.Shape) == 1 {
-		input.Shape = []int{1, input.Shape[0]}
+	input, err := linearInput(data.Tensor, "linearclassifier")
+	if err != nil {
+		return err
 	}
 	num_targets := l.num_targets
 	if l.intercepts == nil {
@@ -110,17 +118,11 @@ func (l *LinearClassifier) Compute(k *kernel.Kernel) error {
 		return err
 	}
 
-	output_classes := num_targets
-	add_second_class := false
-	if num_targets == 1 && ((l.using_strings && len(l.classlabel_string) == 2) || (!l.using_strings && len(l.classlabel) == 2)) {
-		output_classes = 2
-		add_second_class = true
-	}
+	output_classes, add_second_class := l.outputClasses(num_targets)
 	scores, err := k.Output(l.outputs[1], []int{num_batches, output_classes}, tensor.Double)
 	if err != nil {
 		return err
 	}
-	input.Cast(tensor.Double)
 	scores.Shape = []int{num_batches, num_targets}
 	scores, err = input.Dot(l.coefficients, scores)
 	if err != nil {
@@ -184,14 +186,46 @@ func (l *LinearClassifier) Compute(k *kernel.Kernel) error {
 			}
 		}
 	}
-	if l.post_transform != NONE || add_second_class {
-		scores.Shape = []int{num_batches, output_classes}
-		to_add := -1
-		if add_second_class {
-			to_add = 1
-		}
-		update_scores(scores.DoubleData, []int{num_batches, num_targets}, l.post_transform, to_add, false)
-	}
+	scores.Shape = []int{num_batches, output_classes}
+	l.transformScores(scores.DoubleData, num_batches, num_targets, add_second_class)
 	scores.Cast(tensor.Float)
 	return nil
 }
+
+// Returns the number of score columns, a binary classifier with two labels gets a column for
+// the negative class
+func (l *LinearClassifier) outputClasses(num_targets int) (int, bool) {
+	if num_targets == 1 && ((l.using_strings && len(l.classlabel_string) == 2) || (!l.using_strings && len(l.classlabel) == 2)) {
+		return 2, true
+	}
+	return num_targets, false
+}
+
+/*
+ * Applies the post transform to the [num_batches, num_targets] scores at the start of the buffer.
+ * With several targets each class has its own score whatever multi_class is, the post transform
+ * decides how they are turned into probabilities. A binary classifier with a single score is
+ * where they differ: one-vs-rest gives the negative class 1 - score, while multinomial gives the
+ * two classes opposite scores, normalized together by SOFTMAX like sklearn does.
+ */
+func (l *LinearClassifier) transformScores(scores []float64, num_batches, num_targets int, add_second_class bool) {
+	if l.post_transform == NONE && !add_second_class {
+		return
+	}
+	to_add := -1
+	multinomial := add_second_class && l.multi_class == multiClassMultinomial
+	if multinomial {
+		to_add = 2
+	} else if add_second_class {
+		to_add = 1
+	}
+	update_scores(scores, []int{num_batches, num_targets}, l.post_transform, to_add, false)
+	if multinomial {
+		switch l.post_transform {
+		case SOFTMAX:
+			tensor.SoftMax(scores, []int{num_batches, 2})
+		case SOFTMAX_ZERO:
+			tensor.SoftMaxZero(scores, []int{num_batches, 2})
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+/*
+ * linearExplanation splits the scores of a linear model, the contribution of a feature to a
+ * target being its coefficient times its value and the bias the intercept of the target. The
+ * coefficients are shaped [targets, features] by the last Compute.
+ */
+func linearExplanation(k *kernel.Kernel, input int, coefficients, intercepts *tensor.Tensor) (*Explanation, error) {
+	X, err := lastInput(k, input)
+	if err != nil {
+		return nil, err
+	}
+	X, err = X.Clone()
+	if err != nil {
+		return nil, err
+	}
+	X.Cast(tensor.Double)
+	if len(X.Shape) == 1 {
+		X.Shape = []int{1, X.Shape[0]}
+	}
+	nSamples, nFeatures := X.Shape[0], X.Shape[1]
+	if len(coefficients.Shape) != 2 || coefficients.Shape[1] != nFeatures {
+		return nil, fmt.Errorf("input with shape %v cannot be explained with coefficients of shape %v", X.Shape, coefficients.Shape)
+	}
+	nTargets := coefficients.Shape[0]
+
+	e := newExplanation(nSamples, nTargets, nFeatures)
+	if intercepts != nil {
+		copy(e.Bias, intercepts.DoubleData)
+	}
+	for i := range nSamples {
+		x := X.DoubleData[i*nFeatures : (i+1)*nFeatures]
+		for j := range nTargets {
+			w := coefficients.DoubleData[j*nFeatures : (j+1)*nFeatures]
+			for f := range x {
+				e.Contributions[i][j][f] = w[f] * x[f]
+			}
+		}
+	}
+	e.sumScores()
+	return e, nil
+}
+
+// Applies fn to a [samples, columns] buffer holding the raw scores at its start
+func transformedScores(scores [][]float64, columns int, fn func([]float64)) [][]float64 {
+	nSamples := len(scores)
+	buffer := make([]float64, nSamples*columns)
+	for i := range scores {
+		copy(buffer[i*len(scores[i]):], scores[i])
+	}
+	fn(buffer)
+	transformed := make([][]float64, nSamples)
+	for i := range transformed {
+		transformed[i] = buffer[i*columns : (i+1)*columns]
+	}
+	return transformed
+}
+
+func (l *LinearRegressor) Explain(k *kernel.Kernel) (*Explanation, error) {
+	e, err := linearExplanation(k, l.input, l.coefficients, l.intercepts)
+	if err != nil {
+		return nil, err
+	}
+	nTargets := len(e.Bias)
+	e.Transformed = transformedScores(e.Scores, nTargets, func(scores []float64) {
+		if l.post_transform != NONE {
+			update_scores(scores, []int{len(e.Scores), nTargets}, l.post_transform, -1, false)
+		}
+	})
+	return e, nil
+}
+
+func (l *LinearClassifier) Explain(k *kernel.Kernel) (*Explanation, error) {
+	e, err := linearExplanation(k, l.input, l.coefficients, l.intercepts)
+	if err != nil {
+		return nil, err
+	}
+	nTargets := len(e.Bias)
+	output_classes, add_second_class := l.outputClasses(nTargets)
+	e.Transformed = transformedScores(e.Scores, output_classes, func(scores []float64) {
+		l.transformScores(scores, len(e.Scores), nTargets, add_second_class)
+	})
+	return e, nil
+}
@@ -9,10 +9,11 @@ import (
 )
 
 type LinearRegressor struct {
-	input        int
-	outputs      []int
-	coefficients *tensor.Tensor
-	intercepts   *tensor.Tensor
+	input          int
+	outputs        []int
+	coefficients   *tensor.Tensor
+	intercepts     *tensor.Tensor
+	post_transform postTransform
 }
 
 func (l *LinearRegressor) Init(k *kernel.Kernel, node *ir.NodeProto) error {
@@ -28,6 +29,12 @@ func (l *LinearRegressor) Init(k *kernel.Kernel, node *ir.NodeProto) error {
 			l.coefficients = tensor.Create1DDoubleTensorFromFloat(attr.Floats)
 		case "intercepts":
 			l.intercepts = tensor.Create1DDoubleTensorFromFloat(attr.Floats)
+		case "post_transform":
+			post_transform, ok := postTransformMap[string(attr.S)]
+			if !ok {
+				return fmt.Errorf("linearregressor: post_transform %s not supported", attr.S)
+			}
+			l.post_transform = post_transform
 		default:
 			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
 		}
@@ -56,13 +63,9 @@ func (l *LinearRegressor) Compute(k *kernel.Kernel) error {
 		return err
 	}
 
-	input := data.Tensor
-	if len(input.Shape) > 2 {
-		return fmt.Errorf("linearregressor: invalid shape %v", input.Shape)
-	}
-
-	if len(input.Shape) == 1 {
-		input.Shape = []int{1, input.Shape[0]}
+	input, err := linearInput(data.Tensor, "linearregressor")
+	if err != nil {
+		return err
 	}
 
 	if l.intercepts == nil {
@@ -83,8 +86,6 @@ func (l *LinearRegressor) Compute(k *kernel.Kernel) error {
 		return err
 	}
 
-	input.Cast(tensor.Double)
-
 	scores, err = input.Dot(l.coefficients, scores)
 	if err != nil {
 		return err
@@ -97,6 +98,9 @@ func (l *LinearRegressor) Compute(k *kernel.Kernel) error {
 		}
 	}
 
+	if l.post_transform != NONE {
+		update_scores(scores.DoubleData, scores.Shape, l.post_transform, -1, false)
+	}
 	scores.Cast(tensor.Float)
 	return nil
 
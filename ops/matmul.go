@@ -0,0 +1,145 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// MatMul multiplies matrices the numpy way: a vector on the left is a single row and a vector
+// on the right a single column, and their dimension is dropped from the output. When B is a float
+// or double matrix initializer, such as the weights of a dense layer, it is transposed once and
+// the product goes through tensor.Dot.
+type MatMul struct {
+	a          int
+	b          int
+	transposed *tensor.Tensor // B transposed, nil when B is not a constant float or double matrix
+	workers    int            // goroutines the product with transposed is split over, the DotWorkers option
+	output     int
+}
+
+func (m *MatMul) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 2 {
+		return fmt.Errorf("%s: 2 inputs are required, got %d", node.OpType, len(node.Input))
+	}
+	a, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	m.a = a
+	b, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	m.b = b
+	if len(node.Attribute) > 0 {
+		return fmt.Errorf("%s not supported for %s", node.Attribute[0].Name, node.OpType)
+	}
+	// Only initializers are in the kernel before the graph runs
+	m.transposed = nil
+	if weights := k.Get(b); weights != nil && len(weights.Shape) == 2 {
+		switch weights.DType {
+		case tensor.Float:
+			m.transposed = &tensor.Tensor{DType: tensor.Float, FloatData: transpose(weights.FloatData, weights.Shape[0], weights.Shape[1])}
+		case tensor.Double:
+			m.transposed = &tensor.Tensor{DType: tensor.Double, DoubleData: transpose(weights.DoubleData, weights.Shape[0], weights.Shape[1])}
+		}
+		if m.transposed != nil {
+			m.transposed.Shape = []int{weights.Shape[1], weights.Shape[0]}
+		}
+	}
+	m.workers = k.Options.DotWorkers
+	m.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (m *MatMul) Compute(k *kernel.Kernel) error {
+	a, err := k.Input(m.a)
+	if err != nil {
+		return err
+	}
+	b, err := k.Input(m.b)
+	if err != nil {
+		return err
+	}
+	x, y := a.Tensor, b.Tensor
+	if x.DType != y.DType {
+		return fmt.Errorf("matmul: inputs have different datatypes %v and %v", x.DType, y.DType)
+	}
+	if len(x.Shape) == 0 || len(x.Shape) > 2 || len(y.Shape) == 0 || len(y.Shape) > 2 {
+		return fmt.Errorf("matmul: shapes %v and %v should have 1 or 2 dimensions", x.Shape, y.Shape)
+	}
+	rows, inner := matrixShape(x.Shape)
+	inner_b, cols := y.Shape[0], 1
+	if len(y.Shape) == 2 {
+		cols = y.Shape[1]
+	}
+	if inner != inner_b {
+		return fmt.Errorf("matmul: shapes %v and %v are not aligned", x.Shape, y.Shape)
+	}
+	shape := make([]int, 0, 2)
+	if len(x.Shape) == 2 {
+		shape = append(shape, rows)
+	}
+	if len(y.Shape) == 2 {
+		shape = append(shape, cols)
+	}
+	if len(shape) == 0 {
+		shape = append(shape, 1)
+	}
+	output, err := k.Output(m.output, shape, x.DType)
+	if err != nil {
+		return err
+	}
+	if m.transposed != nil && m.transposed.DType == x.DType {
+		// A vector on the left is multiplied as a matrix of a single row
+		rows_view := &tensor.Tensor{Shape: []int{rows, inner}, DType: x.DType, FloatData: x.FloatData, DoubleData: x.DoubleData}
+		_, err = rows_view.ParallelDot(m.transposed, output, m.workers)
+		return err
+	}
+	switch x.DType {
+	case tensor.Float:
+		matmul[float32, float64](x.FloatData, y.FloatData, output.FloatData, rows, inner, cols)
+	case tensor.Double:
+		matmul[float64, float64](x.DoubleData, y.DoubleData, output.DoubleData, rows, inner, cols)
+	case tensor.Int32:
+		matmul[int32, int64](x.Int32Data, y.Int32Data, output.Int32Data, rows, inner, cols)
+	case tensor.Int64:
+		matmul[int64, int64](x.Int64Data, y.Int64Data, output.Int64Data, rows, inner, cols)
+	default:
+		return fmt.Errorf("matmul: input datatype (%v) is invalid", x.DType)
+	}
+	return nil
+}
+
+// Multiplies a [rows, inner] matrix by an [inner, cols] one, going along the rows of b so that
+// its memory is read in order. The sums are accumulated in S, double precision for floats and
+// int64 for integers so that they stay exact past 2^53.
+func matmul[T tensor.Numeric, S int64 | float64](a, b, out []T, rows, inner, cols int) {
+	sums := make([]S, cols)
+	for i := range rows {
+		clear(sums)
+		for p := range inner {
+			v := S(a[i*inner+p])
+			for j, w := range b[p*cols : (p+1)*cols] {
+				sums[j] += v * S(w)
+			}
+		}
+		for j, sum := range sums {
+			out[i*cols+j] = T(sum)
+		}
+	}
+}
+
+// Returns the [cols, rows] transpose of a [rows, cols] matrix
+func transpose[T any](data []T, rows, cols int) []T {
+	out := make([]T, rows*cols)
+	for i := range rows {
+		for j, v := range data[i*cols : (i+1)*cols] {
+			out[j*rows+i] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+package ops_test
+
+import (
+	"math"
+	"testing"
+)
+
+// The example networks are built by examples/generate with the graph skl2onnx gives MLPClassifier
+// and MLPRegressor and fixed weights, the references are their forward pass in double precision on
+// knnInput
+var mlpClassifierCases = []struct {
+	name          string
+	path          string
+	labels        []int64
+	probabilities [][]float64
+}{
+	{
+		"MLPClassifier", "../examples/mlp_classifier.onnx",
+		[]int64{0, 0, 2, 1, 2},
+		[][]float64{
+			{0.7010409, 0.1896498, 0.1093092},
+			{0.3674061, 0.3352298, 0.2973641},
+			{0.1700335, 0.3753471, 0.4546194},
+			{0.3266241, 0.3477597, 0.3256162},
+			{0.1729752, 0.3752338, 0.4517910},
+		},
+	},
+	{
+		// A single logistic output, widened to the probabilities of both classes
+		"MLPBinaryClassifier", "../examples/mlp_binary_classifier.onnx",
+		[]int64{0, 1, 1, 1, 1},
+		[][]float64{
+			{0.6075289, 0.3924711},
+			{0.4231140, 0.5768860},
+			{0.3749052, 0.6250948},
+			{0.4271708, 0.5728292},
+			{0.3938430, 0.6061570},
+		},
+	},
+}
+
+// Repeats the rows of knnInput up to n rows
+func repeatedInput(n int) [][]float32 {
+	input := make([][]float32, n)
+	for i := range input {
+		input[i] = knnInput[i%len(knnInput)]
+	}
+	return input
+}
+
+func TestMLPClassifier(t *testing.T) {
+	for _, c := range mlpClassifierCases {
+		t.Run(c.name, func(t *testing.T) {
+			g := loadExample(t, c.path)
+			for _, n := range []int{1, len(knnInput), 10000} {
+				result, err := g.Execute([]any{repeatedInput(n)})
+				if err != nil {
+					t.Fatalf("Error executing graph: %v", err)
+				}
+				labels, ok := result[0].([]int64)
+				if !ok {
+					t.Fatalf("Unexpected label type: %T", result[0])
+				}
+				probabilities, ok := result[1].([]map[int64]float32)
+				if !ok {
+					t.Fatalf("Unexpected probability type: %T", result[1])
+				}
+				if len(labels) != n || len(probabilities) != n {
+					t.Fatalf("expected %d rows, got %d labels and %d probabilities", n, len(labels), len(probabilities))
+				}
+				for i := range n {
+					expected := c.probabilities[i%len(knnInput)]
+					if labels[i] != c.labels[i%len(knnInput)] {
+						t.Fatalf("batch of %d, sample %d: expected label %d, got %d", n, i, c.labels[i%len(knnInput)], labels[i])
+					}
+					for class, p := range expected {
+						if math.Abs(float64(probabilities[i][int64(class)])-p) > 1e-5 {
+							t.Fatalf("batch of %d, sample %d: expected probability %v for class %d, got %v", n, i, p, class, probabilities[i][int64(class)])
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMLPRegressor(t *testing.T) {
+	expected := [][]float64{
+		{-7.135449, 3.166120},
+		{-8.634446, 4.352192},
+		{-9.500905, 4.984128},
+		{-8.474298, 4.213372},
+		{-8.594851, 4.425314},
+	}
+	g := loadExample(t, "../examples/mlp_regressor.onnx")
+	input := make([][]float32, len(knnInput))
+	for i, x := range knnInput {
+		input[i] = x[:3]
+	}
+	result, err := g.Execute([]any{input})
+	if err != nil {
+		t.Fatalf("Error executing graph: %v", err)
+	}
+	predictions, ok := result[0].([][]float32)
+	if !ok {
+		t.Fatalf("Unexpected output type: %T", result[0])
+	}
+	for i := range expected {
+		for j, v := range expected[i] {
+			if math.Abs(float64(predictions[i][j])-v) > 1e-4 {
+				t.Errorf("sample %d: expected output %d to be %v, got %v", i, j, v, predictions[i][j])
+			}
+		}
+	}
+}
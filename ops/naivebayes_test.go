@@ -0,0 +1,89 @@
+package ops_test
+
+import (
+	"math"
+	"testing"
+)
+
+// Word counts of "free", "win", "meeting" and "report" in the messages scored by the multinomial,
+// complement and bernoulli examples, fitted on a few spam (1) and ham (0) messages with alpha = 1
+var nbCountInput = [][]float32{{2, 1, 0, 0}, {0, 0, 3, 2}, {1, 2, 1, 0}, {0, 1, 2, 3}}
+
+// The categories of the 3 features of the categorical example
+var nbCategoryInput = [][]int64{{0, 1, 3}, {2, 0, 0}, {1, 1, 2}, {1, 0, 1}}
+
+// The labels and predict_proba of the estimators the examples are built from, computed in double
+// precision by examples/generate, which builds the models with the graphs skl2onnx gives them
+var naiveBayesCases = []struct {
+	name          string
+	path          string
+	input         any
+	labels        []int64
+	probabilities [][]float64
+}{
+	{
+		"GaussianNB", "../examples/gaussian_nb.onnx", knnInput,
+		[]int64{0, 1, 2, 1, 2},
+		[][]float64{
+			{1, 7.012607e-81, 1.873005e-58},
+			{0, 0.9995005, 0.0004994553},
+			{0, 4.191881e-34, 1},
+			{0, 0.9999588, 4.122501e-05},
+			{0, 0.0001297153, 0.9998703},
+		},
+	},
+	{
+		"MultinomialNB", "../examples/multinomial_nb.onnx", nbCountInput,
+		[]int64{1, 0, 1, 0},
+		[][]float64{{0.007292616, 0.9927074}, {0.9996374, 0.0003626432}, {0.03883495, 0.9611650}, {0.9986661, 0.001333889}},
+	},
+	{
+		"ComplementNB", "../examples/complement_nb.onnx", nbCountInput,
+		[]int64{1, 0, 1, 0},
+		[][]float64{{0.007292616, 0.9927074}, {0.9996374, 0.0003626432}, {0.03883495, 0.9611650}, {0.9986661, 0.001333889}},
+	},
+	{
+		"BernoulliNB", "../examples/bernoulli_nb.onnx", nbCountInput,
+		[]int64{1, 0, 1, 0},
+		[][]float64{{0.00990099, 0.990099}, {0.990099, 0.00990099}, {0.09090909, 0.9090909}, {0.9090909, 0.09090909}},
+	},
+	{
+		"CategoricalNB", "../examples/categorical_nb.onnx", nbCategoryInput,
+		[]int64{0, 1, 0, 1},
+		[][]float64{{0.96, 0.04}, {0.1666667, 0.8333333}, {0.5714286, 0.4285714}, {0.2105263, 0.7894737}},
+	},
+}
+
+func TestNaiveBayes(t *testing.T) {
+	for _, c := range naiveBayesCases {
+		t.Run(c.name, func(t *testing.T) {
+			g := loadExample(t, c.path)
+			result, err := g.Execute([]any{c.input})
+			if err != nil {
+				t.Fatalf("Error executing graph: %v", err)
+			}
+			labels, ok := result[0].([]int64)
+			if !ok {
+				t.Fatalf("Unexpected label type: %T", result[0])
+			}
+			probabilities, ok := result[1].([]map[int64]float32)
+			if !ok {
+				t.Fatalf("Unexpected probability type: %T", result[1])
+			}
+			if len(labels) != len(c.labels) || len(probabilities) != len(c.labels) {
+				t.Fatalf("expected %d rows, got %d labels and %d probabilities", len(c.labels), len(labels), len(probabilities))
+			}
+			for i := range c.labels {
+				if labels[i] != c.labels[i] {
+					t.Errorf("sample %d: expected label %d, got %d", i, c.labels[i], labels[i])
+				}
+				for class, expected := range c.probabilities[i] {
+					p := probabilities[i][int64(class)]
+					if math.Abs(float64(p)-expected) > 1e-5 {
+						t.Errorf("sample %d: expected probability %v for class %d, got %v", i, expected, class, p)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package ops
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// OneHot gives every index a new axis of length depth, holding the on value at the index and the
+// off value elsewhere. values is [off, on]. Negative indices count from the end of the axis, and
+// those out of range only get off values.
+type OneHot struct {
+	indices int
+	depth   int
+	values  int
+	axis    int
+	output  int
+}
+
+func (o *OneHot) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 3 {
+		return fmt.Errorf("%s: indices, depth and values are required", node.OpType)
+	}
+	inputs := make([]int, 3)
+	for i, name := range node.Input {
+		input, err := k.RegisterReader(name)
+		if err != nil {
+			return err
+		}
+		inputs[i] = input
+	}
+	o.indices, o.depth, o.values = inputs[0], inputs[1], inputs[2]
+	o.axis = -1
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axis":
+			o.axis = int(attr.I)
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	o.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (o *OneHot) Compute(k *kernel.Kernel) error {
+	i, err := k.Input(o.indices)
+	if err != nil {
+		return err
+	}
+	d, err := k.Input(o.depth)
+	if err != nil {
+		return err
+	}
+	v, err := k.Input(o.values)
+	if err != nil {
+		return err
+	}
+	indices, err := oneHotIndices(i.Tensor)
+	if err != nil {
+		return err
+	}
+	depths, err := oneHotIndices(d.Tensor)
+	if err != nil {
+		return err
+	}
+	if len(depths) != 1 || depths[0] < 1 {
+		return fmt.Errorf("onehot: depth should be a positive scalar, got %v", depths)
+	}
+	depth := int(depths[0])
+	values := v.Tensor
+	if tensorSize(values) != 2 {
+		return fmt.Errorf("onehot: values should hold the off and on values, got shape %v", values.Shape)
+	}
+
+	shape := i.Tensor.Shape
+	rank := len(shape) + 1
+	axis := o.axis
+	if axis < -rank || axis >= rank {
+		return fmt.Errorf("onehot: axis %d is out of range for %d dimensions", o.axis, rank)
+	}
+	if axis < 0 {
+		axis += rank
+	}
+	// The indices are [outer, inner] around the new axis, the output [outer, depth, inner]
+	inner := tensor.Size(shape[axis:])
+	output, err := k.Output(o.output, slices.Insert(slices.Clone(shape), axis, depth), values.DType)
+	if err != nil {
+		return err
+	}
+	switch values.DType {
+	case tensor.Float:
+		oneHot(indices, values.FloatData, output.FloatData, depth, inner)
+	case tensor.Double:
+		oneHot(indices, values.DoubleData, output.DoubleData, depth, inner)
+	case tensor.Int32:
+		oneHot(indices, values.Int32Data, output.Int32Data, depth, inner)
+	case tensor.Int64:
+		oneHot(indices, values.Int64Data, output.Int64Data, depth, inner)
+	case tensor.String:
+		oneHot(indices, values.StringData, output.StringData, depth, inner)
+	default:
+		return fmt.Errorf("onehot: values datatype (%v) is invalid", values.DType)
+	}
+	return nil
+}
+
+// Indices and depth can be of any numeric type, floats are truncated
+func oneHotIndices(t *tensor.Tensor) ([]int64, error) {
+	switch t.DType {
+	case tensor.Float:
+		values := make([]int64, tensorSize(t))
+		for i := range values {
+			values[i] = int64(t.FloatData[i])
+		}
+		return values, nil
+	case tensor.Double:
+		values := make([]int64, tensorSize(t))
+		for i := range values {
+			values[i] = int64(t.DoubleData[i])
+		}
+		return values, nil
+	}
+	return intValues(t, "onehot")
+}
+
+func oneHot[T any](indices []int64, values, out []T, depth, inner int) {
+	off, on := values[0], values[1]
+	for i, index := range indices {
+		outer, position := i/inner, i%inner
+		if index < 0 {
+			index += int64(depth)
+		}
+		for d := range depth {
+			value := off
+			if int64(d) == index {
+				value = on
+			}
+			out[(outer*depth+d)*inner+position] = value
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package ops_test
+
+import (
+	"math"
+	"testing"
+)
+
+// The Yeo-Johnson example is fitted on the iris samples centred on these values, so its features
+// have both signs
+var powerShift = []float32{5.8, 3.0, 3.8, 1.2}
+
+func powerInput() [][]float32 {
+	input := make([][]float32, len(knnInput))
+	for i, row := range knnInput {
+		input[i] = make([]float32, len(row))
+		for j, v := range row {
+			input[i][j] = v - powerShift[j]
+		}
+	}
+	return input
+}
+
+// Samples past both ends of the training range of every feature
+var kbinsInput = append(knnInput[:len(knnInput):len(knnInput)], []float32{3.0, 5.0, 0.5, 3.0}, []float32{7.1, 2.3, 6.0, 0.2})
+
+// The reference transforms of the samples, computed in double precision by examples/generate,
+// which builds the models with the graphs skl2onnx gives the transformers. They are fitted on the
+// iris samples of the examples. The Box-Cox lambdas are [0.5, 0, -0.3, 1.2], the Yeo-Johnson ones
+// [0.8, 0, 1.3, 2], so that each method goes through its logarithm. KBinsDiscretizer has
+// [3, 4, 3, 2] bins, uniform for the one-hot encoding and quantile for the ordinal one.
+var preprocessingCases = []struct {
+	name     string
+	path     string
+	input    [][]float32
+	expected [][]float64
+}{
+	{
+		"MinMaxScaler", "../examples/min_max_scaler.onnx", knnInput,
+		[][]float64{
+			{0.1600001, 0.8461539, 0.0425532, 0},
+			{0.6000001, 0.3846153, 0.7234042, 0.4347826},
+			{0.84, 0.6153845, 0.9148936, 0.9565217},
+			{0.5600001, 0.4615385, 0.6808511, 0.5652174},
+			{0.5200002, 0.5384615, 0.8085106, 0.6956521},
+		},
+	},
+	{
+		"MaxAbsScaler", "../examples/max_abs_scaler.onnx", knnInput,
+		[][]float64{
+			{0.7042254, 0.9444445, 0.25, 0.08000001},
+			{0.8591549, 0.7777778, 0.7833333, 0.48},
+			{0.943662, 0.8611111, 0.9333333, 0.96},
+			{0.8450705, 0.8055556, 0.75, 0.6},
+			{0.830986, 0.8333334, 0.85, 0.72},
+		},
+	},
+	{
+		"RobustScaler", "../examples/robust_scaler.onnx", knnInput,
+		[][]float64{
+			{-0.8965518, 1.200001, -0.7948718, -0.7878788},
+			{-0.1379312, -1.2, 0.025641, -0.1818182},
+			{0.2758618, 0, 0.2564103, 0.5454546},
+			{-0.2068967, -0.7999992, -0.025641, 0},
+			{-0.2758621, -0.3999996, 0.1282051, 0.1818182},
+		},
+	},
+	{
+		"PowerTransformer/box-cox", "../examples/power_transformer_box_cox.onnx", knnInput,
+		[][]float64{
+			{-1.07009, 1.054791, -1.2613, -1.275269},
+			{0.2608226, -0.7952812, 0.600484, -0.1381086},
+			{0.9362037, 0.1745848, 0.8337673, 1.531728},
+			{0.1450798, -0.4609028, 0.5406595, 0.2570845},
+			{0.0283684, -0.1378623, 0.7107643, 0.6685059},
+		},
+	},
+	{
+		"PowerTransformer/yeo-johnson", "../examples/power_transformer_yeo_johnson.onnx", powerInput(),
+		[][]float64{
+			{-1.070319, 0.9653837, -1.268648, -1.140402},
+			{0.2849602, -0.7180718, 0.3454508, -0.3481648},
+			{0.9319493, 0.2358132, 0.9988518, 1.846313},
+			{0.1718251, -0.3701701, 0.2112535, 0.04615538},
+			{0.05678626, -0.05252156, 0.6264901, 0.5433417},
+		},
+	},
+	{
+		"KBinsDiscretizer/onehot", "../examples/kbins_discretizer_onehot.onnx",
+		kbinsInput,
+		[][]float64{
+			{1, 0, 0, 0, 0, 0, 1, 1, 0, 0, 1, 0},
+			{0, 1, 0, 0, 1, 0, 0, 0, 0, 1, 1, 0},
+			{0, 0, 1, 0, 0, 1, 0, 0, 0, 1, 0, 1},
+			{0, 1, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1},
+			{0, 1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 1},
+			{1, 0, 0, 0, 0, 0, 1, 1, 0, 0, 0, 1},
+			{0, 0, 1, 1, 0, 0, 0, 0, 0, 1, 1, 0},
+		},
+	},
+	{
+		"KBinsDiscretizer/ordinal", "../examples/kbins_discretizer_ordinal.onnx",
+		kbinsInput,
+		[][]float64{
+			{0, 3, 0, 0},
+			{1, 0, 1, 0},
+			{2, 2, 2, 1},
+			{1, 0, 1, 1},
+			{1, 1, 2, 1},
+			{0, 3, 0, 1},
+			{2, 0, 2, 0},
+		},
+	},
+	{
+		"PolynomialFeatures", "../examples/polynomial_features.onnx", knnInput,
+		[][]float64{
+			{1, 5, 3.4, 1.5, 0.2, 25, 17, 7.5, 1, 11.56, 5.1, 0.68, 2.25, 0.3, 0.04},
+			{1, 6.1, 2.8, 4.7, 1.2, 37.21, 17.08, 28.67, 7.32, 7.84, 13.16, 3.36, 22.09, 5.64, 1.44},
+			{1, 6.7, 3.1, 5.6, 2.4, 44.89, 20.77, 37.52, 16.08, 9.61, 17.36, 7.44, 31.36, 13.44, 5.76},
+			{1, 6, 2.9, 4.5, 1.5, 36, 17.4, 27, 9, 8.41, 13.05, 4.35, 20.25, 6.75, 2.25},
+			{1, 5.9, 3, 5.1, 1.8, 34.81, 17.7, 30.09, 10.62, 9, 15.3, 5.4, 26.01, 9.18, 3.24},
+		},
+	},
+}
+
+func TestPreprocessing(t *testing.T) {
+	for _, c := range preprocessingCases {
+		t.Run(c.name, func(t *testing.T) {
+			g := loadExample(t, c.path)
+			result, err := g.Execute([]any{c.input})
+			if err != nil {
+				t.Fatalf("Error executing graph: %v", err)
+			}
+			output, ok := result[0].([][]float32)
+			if !ok {
+				t.Fatalf("Unexpected output type: %T", result[0])
+			}
+			if len(output) != len(c.expected) {
+				t.Fatalf("expected %d rows, got %d", len(c.expected), len(output))
+			}
+			for i := range c.expected {
+				for j, expected := range c.expected[i] {
+					if math.Abs(float64(output[i][j])-expected) > 1e-5*math.Max(1, math.Abs(expected)) {
+						t.Errorf("sample %d: expected %v in column %d, got %v", i, expected, j, output[i][j])
+					}
+				}
+			}
+		})
+	}
+}
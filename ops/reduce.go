@@ -0,0 +1,187 @@
+package ops
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Reduce runs the ReduceSum, ReduceMean, ReduceSumSquare and ReduceLogSumExp ops. The axes are an
+// attribute up to opset 13 (ReduceSum) or 18 (the others), and an optional input after.
+type Reduce struct {
+	op         string
+	input      int
+	axes       []int64
+	axes_input int // -1 when the axes are not an input
+	keepdims   bool
+	noop_empty bool // noop_with_empty_axes, no axes leaves the input unchanged instead of reducing all
+	output     int
+}
+
+func (r *Reduce) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	switch node.OpType {
+	case "ReduceSum", "ReduceMean", "ReduceSumSquare", "ReduceLogSumExp":
+	default:
+		return fmt.Errorf("%s is not a reduce op", node.OpType)
+	}
+	r.op = node.OpType
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	r.input = input
+	r.axes_input = -1
+	if len(node.Input) > 1 && node.Input[1] != "" {
+		axes, err := k.RegisterReader(node.Input[1])
+		if err != nil {
+			return err
+		}
+		r.axes_input = axes
+	}
+	r.keepdims = true
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axes":
+			r.axes = attr.Ints
+		case "keepdims":
+			r.keepdims = attr.I != 0
+		case "noop_with_empty_axes":
+			r.noop_empty = attr.I != 0
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	r.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (r *Reduce) Compute(k *kernel.Kernel) error {
+	name := strings.ToLower(r.op)
+	data, err := k.Input(r.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	if len(input.Shape) == 0 {
+		return fmt.Errorf("%s: invalid shape %v", name, input.Shape)
+	}
+	axes := r.axes
+	if r.axes_input >= 0 {
+		a, err := k.Input(r.axes_input)
+		if err != nil {
+			return err
+		}
+		axes, err = intValues(a.Tensor, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Which of the axes of the input are reduced
+	rank := len(input.Shape)
+	reduced := make([]bool, rank)
+	for _, axis := range axes {
+		if axis < -int64(rank) || axis >= int64(rank) {
+			return fmt.Errorf("%s: axis %d is out of range for shape %v", name, axis, input.Shape)
+		}
+		if axis < 0 {
+			axis += int64(rank)
+		}
+		reduced[axis] = true
+	}
+	if len(axes) == 0 && r.noop_empty && (r.op == "ReduceSum" || r.op == "ReduceMean") {
+		output, err := input.Clone()
+		if err != nil {
+			return err
+		}
+		return k.Put(r.output, output)
+	} else if len(axes) == 0 && !r.noop_empty {
+		for i := range reduced {
+			reduced[i] = true
+		}
+	}
+	// ReduceSumSquare and ReduceLogSumExp without axes and with noop_with_empty_axes are applied
+	// to every value on its own
+	kept := make([]int, rank) // The shape of the output with the reduced axes kept
+	shape := make([]int, 0, rank)
+	for i, d := range input.Shape {
+		kept[i] = d
+		if !reduced[i] {
+			shape = append(shape, d)
+		} else {
+			kept[i] = 1
+			if r.keepdims {
+				shape = append(shape, 1)
+			}
+		}
+	}
+	if len(shape) == 0 {
+		shape = []int{1}
+	}
+	output, err := k.Output(r.output, shape, input.DType)
+	if err != nil {
+		return err
+	}
+	// The offsets in the output of the values of the input
+	strides := broadcastStrides(kept, input.Shape)
+	size, reduced_size := tensorSize(input), tensor.Size(shape)
+	switch input.DType {
+	case tensor.Float:
+		reduce(input.FloatData[:size], output.FloatData[:reduced_size], input.Shape, strides, r.op)
+	case tensor.Double:
+		reduce(input.DoubleData[:size], output.DoubleData[:reduced_size], input.Shape, strides, r.op)
+	case tensor.Int32:
+		reduce(input.Int32Data[:size], output.Int32Data[:reduced_size], input.Shape, strides, r.op)
+	case tensor.Int64:
+		reduce(input.Int64Data[:size], output.Int64Data[:reduced_size], input.Shape, strides, r.op)
+	default:
+		return fmt.Errorf("%s: input datatype (%v) is invalid", name, input.DType)
+	}
+	return nil
+}
+
+// Reduces the values of the input into the output, strides giving the offset in the output of
+// each value. The sums are accumulated in double precision, and ReduceLogSumExp takes the largest
+// value of each reduction out of the exponentials so that they don't overflow.
+func reduce[T tensor.Numeric](in, out []T, shape, strides []int, op string) {
+	sums := make([]float64, len(out))
+	var peaks []float64
+	if op == "ReduceLogSumExp" {
+		peaks = make([]float64, len(out))
+		for i := range peaks {
+			peaks[i] = math.Inf(-1)
+		}
+		walk(shape, [][]int{strides}, func(i int, o []int) {
+			peaks[o[0]] = max(peaks[o[0]], float64(in[i]))
+		})
+		for i, peak := range peaks {
+			if math.IsInf(peak, 0) {
+				peaks[i] = 0
+			}
+		}
+	}
+	walk(shape, [][]int{strides}, func(i int, o []int) {
+		v := float64(in[i])
+		switch op {
+		case "ReduceSumSquare":
+			v *= v
+		case "ReduceLogSumExp":
+			v = math.Exp(v - peaks[o[0]])
+		}
+		sums[o[0]] += v
+	})
+	count := float64(len(in) / max(1, len(out)))
+	for i, sum := range sums {
+		switch op {
+		case "ReduceMean":
+			sum /= count
+		case "ReduceLogSumExp":
+			sum = peaks[i] + math.Log(sum)
+		}
+		out[i] = T(sum)
+	}
+}
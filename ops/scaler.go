@@ -51,11 +51,6 @@ func (s *Scaler) Compute(k *kernel.Kernel) error {
 		return fmt.Errorf("scaler: input datatype (%v) is invalid", input.DType)
 	}
 
-	output, err := k.Output(s.output, input.Shape, tensor.Float)
-	if err != nil {
-		return err
-	}
-	input.Cast(tensor.Float)
 	rows := input.Shape[0]
 	stride := rows
 	if len(input.Shape) == 1 {
@@ -63,17 +58,32 @@ func (s *Scaler) Compute(k *kernel.Kernel) error {
 	} else {
 		stride = input.Shape[1]
 	}
-	length := rows * stride
-	if len(s.offset) == stride {
-		for i := range length {
-			output.FloatData[i] = (input.FloatData[i] - s.offset[i%stride]) * s.scale[i%stride]
-		}
-	} else if len(s.offset) == 1 {
-		for i := range length {
-			output.FloatData[i] = (input.FloatData[i] - s.offset[0]) * s.scale[0]
-		}
-	} else {
+	if len(s.offset) != stride && len(s.offset) != 1 {
 		return fmt.Errorf("scaler: either offset/scale length has to be of length (%d) or 1", stride)
 	}
+
+	output, err := k.Output(s.output, input.Shape, tensor.Float)
+	if err != nil {
+		return err
+	}
+	// The input can have other readers, so it is converted while it is read instead of cast in place
+	switch input.DType {
+	case tensor.Float:
+		scale(input.FloatData, output.FloatData, s.offset, s.scale, rows*stride)
+	case tensor.Double:
+		scale(input.DoubleData, output.FloatData, s.offset, s.scale, rows*stride)
+	case tensor.Int32:
+		scale(input.Int32Data, output.FloatData, s.offset, s.scale, rows*stride)
+	case tensor.Int64:
+		scale(input.Int64Data, output.FloatData, s.offset, s.scale, rows*stride)
+	}
 	return nil
 }
+
+// A single offset and scale apply to every element, otherwise there is one per column
+func scale[T tensor.Numeric](input []T, output, offset, scale []float32, length int) {
+	stride := len(offset)
+	for i := range length {
+		output[i] = (float32(input[i]) - offset[i%stride]) * scale[i%stride]
+	}
+}
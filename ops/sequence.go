@@ -0,0 +1,379 @@
+package ops
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+/*
+ * Sequences are tensors of the Sequence dtype, their elements are full tensors held in
+ * SequenceData. A sequence of maps, such as the output of ZipMap, is not a Sequence: it is
+ * already a map tensor with one map per row.
+ */
+
+type SequenceConstruct struct {
+	inputs []int
+	output int
+}
+
+func (s *SequenceConstruct) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) == 0 {
+		return fmt.Errorf("%s: at least one input is required", node.OpType)
+	}
+	if len(node.Attribute) > 0 {
+		return fmt.Errorf("%s not supported for %s", node.Attribute[0].Name, node.OpType)
+	}
+	s.inputs = make([]int, len(node.Input))
+	for i, name := range node.Input {
+		input, err := k.RegisterReader(name)
+		if err != nil {
+			return err
+		}
+		s.inputs[i] = input
+	}
+	s.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (s *SequenceConstruct) Compute(k *kernel.Kernel) error {
+	output, err := k.Output(s.output, []int{len(s.inputs)}, tensor.Sequence)
+	if err != nil {
+		return err
+	}
+	for i, index := range s.inputs {
+		data, err := k.Input(index)
+		if err != nil {
+			return err
+		}
+		if i > 0 && data.Tensor.DType != output.SequenceData[0].DType {
+			return fmt.Errorf("sequenceconstruct: input %d is %v, the sequence holds %v", i, data.Tensor.DType, output.SequenceData[0].DType)
+		}
+		output.SequenceData[i], err = shareOrClone(data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type SequenceAt struct {
+	input    int
+	position int
+	output   int
+}
+
+func (s *SequenceAt) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 2 {
+		return fmt.Errorf("%s: a sequence and a position are required", node.OpType)
+	}
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	s.input = input
+	position, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	s.position = position
+	if len(node.Attribute) > 0 {
+		return fmt.Errorf("%s not supported for %s", node.Attribute[0].Name, node.OpType)
+	}
+	s.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (s *SequenceAt) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(s.input)
+	if err != nil {
+		return err
+	}
+	sequence := data.Tensor
+	if sequence.DType != tensor.Sequence {
+		return fmt.Errorf("sequenceat: input is %v, not a sequence", sequence.DType)
+	}
+	position, err := k.Input(s.position)
+	if err != nil {
+		return err
+	}
+	positions, err := intValues(position.Tensor, "sequenceat")
+	if err != nil {
+		return err
+	}
+	if len(positions) != 1 {
+		return fmt.Errorf("sequenceat: position should be a scalar, got shape %v", position.Tensor.Shape)
+	}
+	length := len(sequence.SequenceData)
+	at := int(positions[0])
+	if at < -length || at >= length {
+		return fmt.Errorf("sequenceat: position %d is out of the bounds of a sequence of length %d", at, length)
+	}
+	if at < 0 {
+		at += length
+	}
+	item := kernel.Data{Readers: data.Readers, Tensor: sequence.SequenceData[at]}
+	output, err := shareOrClone(item)
+	if err != nil {
+		return err
+	}
+	return k.Put(s.output, output)
+}
+
+type SequenceLength struct {
+	input  int
+	output int
+}
+
+func (s *SequenceLength) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	s.input = input
+	if len(node.Attribute) > 0 {
+		return fmt.Errorf("%s not supported for %s", node.Attribute[0].Name, node.OpType)
+	}
+	s.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (s *SequenceLength) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(s.input)
+	if err != nil {
+		return err
+	}
+	if data.Tensor.DType != tensor.Sequence {
+		return fmt.Errorf("sequencelength: input is %v, not a sequence", data.Tensor.DType)
+	}
+	output, err := k.Output(s.output, []int{1}, tensor.Int64)
+	if err != nil {
+		return err
+	}
+	output.Int64Data[0] = int64(len(data.Tensor.SequenceData))
+	return nil
+}
+
+type SequenceEmpty struct {
+	output int
+}
+
+func (s *SequenceEmpty) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "dtype":
+			// The sequence has no element to hold the type
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	s.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (s *SequenceEmpty) Compute(k *kernel.Kernel) error {
+	output, err := k.Output(s.output, []int{0}, tensor.Sequence)
+	if err != nil {
+		return err
+	}
+	output.SequenceData = output.SequenceData[:0]
+	return nil
+}
+
+type SplitToSequence struct {
+	input    int
+	split    int // -1 when the lengths of the splits are not given
+	axis     int
+	keepdims bool
+	output   int
+}
+
+func (s *SplitToSequence) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	s.input = input
+	s.split = -1
+	if len(node.Input) > 1 && node.Input[1] != "" {
+		split, err := k.RegisterReader(node.Input[1])
+		if err != nil {
+			return err
+		}
+		s.split = split
+	}
+	s.axis = 0
+	s.keepdims = true
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axis":
+			s.axis = int(attr.I)
+		case "keepdims":
+			s.keepdims = attr.I != 0
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	s.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (s *SplitToSequence) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(s.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	switch input.DType {
+	case tensor.Float, tensor.Double, tensor.Int32, tensor.Int64, tensor.String:
+	default:
+		return fmt.Errorf("splittosequence: input datatype (%v) is invalid", input.DType)
+	}
+	rank := len(input.Shape)
+	axis := s.axis
+	if axis < -rank || axis >= rank {
+		return fmt.Errorf("splittosequence: axis %d is out of range for shape %v", s.axis, input.Shape)
+	}
+	if axis < 0 {
+		axis += rank
+	}
+	dim := input.Shape[axis]
+
+	// Without split, every element has a length of 1 and keepdims decides if the axis is kept
+	lengths := slices.Repeat([]int{1}, dim)
+	squeeze := !s.keepdims
+	if s.split >= 0 {
+		squeeze = false
+		split, err := k.Input(s.split)
+		if err != nil {
+			return err
+		}
+		lengths, err = splitLengths(split.Tensor, dim)
+		if err != nil {
+			return err
+		}
+	}
+
+	output, err := k.Output(s.output, []int{len(lengths)}, tensor.Sequence)
+	if err != nil {
+		return err
+	}
+	// The tensor can be reused from a run with more parts
+	output.SequenceData = output.SequenceData[:len(lengths)]
+	start := 0
+	for i, length := range lengths {
+		item := sliceAxis(input, axis, start, start+length)
+		if squeeze {
+			item.Shape = slices.Delete(item.Shape, axis, axis+1)
+			if len(item.Shape) == 0 {
+				item.Shape = []int{1}
+			}
+		}
+		output.SequenceData[i] = item
+		start += length
+	}
+	return nil
+}
+
+// Returns the lengths of the parts an axis of length dim is split into. A single split value gives
+// parts of that length with a shorter last one, more values list the lengths.
+func splitLengths(split *tensor.Tensor, dim int) ([]int, error) {
+	values, err := intValues(split, "splittosequence")
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 1 {
+		size := int(values[0])
+		if size <= 0 {
+			return nil, fmt.Errorf("splittosequence: split %d should be positive", size)
+		}
+		lengths := make([]int, 0, (dim+size-1)/size)
+		for start := 0; start < dim; start += size {
+			lengths = append(lengths, min(size, dim-start))
+		}
+		return lengths, nil
+	}
+	lengths := make([]int, len(values))
+	total := 0
+	for i, v := range values {
+		if v < 0 {
+			return nil, fmt.Errorf("splittosequence: split %d should not be negative", v)
+		}
+		lengths[i] = int(v)
+		total += lengths[i]
+	}
+	if total != dim {
+		return nil, fmt.Errorf("splittosequence: splits %v don't add up to the axis length %d", values, dim)
+	}
+	return lengths, nil
+}
+
+// Returns the values of an Int32 or Int64 tensor
+func intValues(t *tensor.Tensor, op string) ([]int64, error) {
+	switch t.DType {
+	case tensor.Int64:
+		return t.Int64Data[:tensorSize(t)], nil
+	case tensor.Int32:
+		values := make([]int64, tensorSize(t))
+		for i := range values {
+			values[i] = int64(t.Int32Data[i])
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("%s: expected an int32 or int64 tensor, got %v", op, t.DType)
+}
+
+func tensorSize(t *tensor.Tensor) int {
+	return tensor.Size(t.Shape)
+}
+
+// Returns a copy of the [start, end) part of the axis of a tensor of at most 2 dimensions
+func sliceAxis(t *tensor.Tensor, axis, start, end int) *tensor.Tensor {
+	shape := slices.Clone(t.Shape)
+	shape[axis] = end - start
+	rows, cols := t.Shape[0], 1
+	if len(t.Shape) > 1 {
+		cols = t.Shape[1]
+	}
+	if len(t.Shape) == 1 {
+		// A vector is a single row, the parts are taken from its columns
+		rows, cols, axis = 1, t.Shape[0], 1
+	}
+	out := &tensor.Tensor{Shape: shape, DType: t.DType}
+	switch t.DType {
+	case tensor.Float:
+		out.FloatData = sliceData(t.FloatData, rows, cols, axis, start, end)
+	case tensor.Double:
+		out.DoubleData = sliceData(t.DoubleData, rows, cols, axis, start, end)
+	case tensor.Int32:
+		out.Int32Data = sliceData(t.Int32Data, rows, cols, axis, start, end)
+	case tensor.Int64:
+		out.Int64Data = sliceData(t.Int64Data, rows, cols, axis, start, end)
+	case tensor.String:
+		out.StringData = sliceData(t.StringData, rows, cols, axis, start, end)
+	}
+	return out
+}
+
+func sliceData[T any](data []T, rows, cols, axis, start, end int) []T {
+	if axis == 0 {
+		return slices.Clone(data[start*cols : end*cols])
+	}
+	out := make([]T, 0, rows*(end-start))
+	for row := range rows {
+		out = append(out, data[row*cols+start:row*cols+end]...)
+	}
+	return out
+}
+
+// Returns the tensor of data when its only reader is the caller, a copy otherwise so that the
+// other readers don't see it changed
+func shareOrClone(data kernel.Data) (*tensor.Tensor, error) {
+	if data.Readers == 1 {
+		return data.Tensor, nil
+	}
+	return data.Tensor.Clone()
+}
@@ -0,0 +1,312 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+/*
+ * Reshape, Flatten and Identity don't change the data of their input. Like Cast, they place the
+ * input tensor in their output when they are its only reader, and a copy otherwise.
+ */
+
+type Reshape struct {
+	input      int
+	shape      int
+	allow_zero bool // a 0 in the shape is a dimension of length 0 instead of a copy of the input's
+	output     int
+}
+
+func (r *Reshape) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 2 {
+		return fmt.Errorf("%s: the input and the shape are required", node.OpType)
+	}
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	r.input = input
+	shape, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	r.shape = shape
+	r.allow_zero = false
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "allowzero":
+			r.allow_zero = attr.I != 0
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	r.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (r *Reshape) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(r.input)
+	if err != nil {
+		return err
+	}
+	s, err := k.Input(r.shape)
+	if err != nil {
+		return err
+	}
+	dims, err := intValues(s.Tensor, "reshape")
+	if err != nil {
+		return err
+	}
+	if len(dims) == 0 {
+		return fmt.Errorf("reshape: want a shape of at least 1 dimension, got %v", dims)
+	}
+	size := tensorSize(data.Tensor)
+	shape := make([]int, len(dims))
+	inferred, known := -1, 1
+	for i, d := range dims {
+		switch {
+		case d == -1 && inferred >= 0:
+			return fmt.Errorf("reshape: shape %v has more than one -1", dims)
+		case d == -1:
+			inferred = i
+			continue
+		case d == 0 && !r.allow_zero:
+			if i >= len(data.Tensor.Shape) {
+				return fmt.Errorf("reshape: shape %v copies a dimension missing from %v", dims, data.Tensor.Shape)
+			}
+			shape[i] = data.Tensor.Shape[i]
+		case d < 0:
+			return fmt.Errorf("reshape: invalid dimension %d in %v", d, dims)
+		default:
+			shape[i] = int(d)
+		}
+		known *= shape[i]
+	}
+	if inferred >= 0 {
+		if known == 0 || size%known != 0 {
+			return fmt.Errorf("reshape: input of shape %v cannot be reshaped into %v", data.Tensor.Shape, dims)
+		}
+		shape[inferred] = size / known
+	} else if known != size {
+		return fmt.Errorf("reshape: input of shape %v cannot be reshaped into %v", data.Tensor.Shape, dims)
+	}
+	return reshaped(k, data, shape, r.output)
+}
+
+type Flatten struct {
+	input  int
+	axis   int
+	output int
+}
+
+func (f *Flatten) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	f.input = input
+	f.axis = 1
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axis":
+			f.axis = int(attr.I)
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	f.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+// The dimensions before axis become the rows of the output, and the ones after its columns
+func (f *Flatten) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(f.input)
+	if err != nil {
+		return err
+	}
+	rank := len(data.Tensor.Shape)
+	axis := f.axis
+	if axis < -rank || axis > rank {
+		return fmt.Errorf("flatten: axis %d is out of range for shape %v", f.axis, data.Tensor.Shape)
+	}
+	if axis < 0 {
+		axis += rank
+	}
+	rows := 1
+	for _, d := range data.Tensor.Shape[:axis] {
+		rows *= d
+	}
+	cols := 1
+	for _, d := range data.Tensor.Shape[axis:] {
+		cols *= d
+	}
+	return reshaped(k, data, []int{rows, cols}, f.output)
+}
+
+type Identity struct {
+	input  int
+	output int
+}
+
+func (i *Identity) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	i.input = input
+	i.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (i *Identity) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(i.input)
+	if err != nil {
+		return err
+	}
+	output, err := shareOrClone(data)
+	if err != nil {
+		return err
+	}
+	return k.Put(i.output, output)
+}
+
+// Places the data of the input with a new shape in the output
+func reshaped(k *kernel.Kernel, data kernel.Data, shape []int, index int) error {
+	output, err := shareOrClone(data)
+	if err != nil {
+		return err
+	}
+	output.Shape = shape
+	return k.Put(index, output)
+}
+
+type Concat struct {
+	inputs []int
+	axis   int
+	output int
+}
+
+func (c *Concat) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) == 0 {
+		return fmt.Errorf("%s: at least one input is required", node.OpType)
+	}
+	c.inputs = make([]int, len(node.Input))
+	for i, name := range node.Input {
+		input, err := k.RegisterReader(name)
+		if err != nil {
+			return err
+		}
+		c.inputs[i] = input
+	}
+	axis_set := false
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axis":
+			c.axis = int(attr.I)
+			axis_set = true
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	if !axis_set {
+		return fmt.Errorf("%s: the axis attribute is required", node.OpType)
+	}
+	c.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (c *Concat) Compute(k *kernel.Kernel) error {
+	inputs := make([]*tensor.Tensor, len(c.inputs))
+	for i, index := range c.inputs {
+		data, err := k.Input(index)
+		if err != nil {
+			return err
+		}
+		inputs[i] = data.Tensor
+	}
+	first := inputs[0]
+	rank := len(first.Shape)
+	if rank == 0 || rank > 2 {
+		return fmt.Errorf("concat: invalid shape %v", first.Shape)
+	}
+	axis := c.axis
+	if axis < -rank || axis >= rank {
+		return fmt.Errorf("concat: axis %d is out of range for shape %v", c.axis, first.Shape)
+	}
+	if axis < 0 {
+		axis += rank
+	}
+	// A vector is a single row, concatenating vectors is concatenating columns
+	if rank == 1 {
+		axis = 1
+	}
+	rows, cols := matrixShape(first.Shape)
+	widths := make([]int, len(inputs))
+	total := 0
+	for i, input := range inputs {
+		if input.DType != first.DType || len(input.Shape) != rank {
+			return fmt.Errorf("concat: input %d of type %v and shape %v doesn't match %v and %v", i, input.DType, input.Shape, first.DType, first.Shape)
+		}
+		r, w := matrixShape(input.Shape)
+		if axis == 0 {
+			r, w = w, r
+		}
+		if (axis == 1 && r != rows) || (axis == 0 && r != cols) {
+			return fmt.Errorf("concat: input %d of shape %v doesn't match %v", i, input.Shape, first.Shape)
+		}
+		widths[i] = w
+		total += w
+	}
+	shape := []int{total}
+	if rank == 2 && axis == 0 {
+		shape = []int{total, cols}
+	} else if rank == 2 {
+		shape = []int{rows, total}
+	}
+	output, err := k.Output(c.output, shape, first.DType)
+	if err != nil {
+		return err
+	}
+	// Concatenating rows is concatenating the data, the columns of a row are copied one input after the other
+	if axis == 0 {
+		rows, total, widths = 1, total*cols, scaled(widths, cols)
+	}
+	switch first.DType {
+	case tensor.Float:
+		concat(inputs, func(t *tensor.Tensor) []float32 { return t.FloatData }, output.FloatData, rows, total, widths)
+	case tensor.Double:
+		concat(inputs, func(t *tensor.Tensor) []float64 { return t.DoubleData }, output.DoubleData, rows, total, widths)
+	case tensor.Int32:
+		concat(inputs, func(t *tensor.Tensor) []int32 { return t.Int32Data }, output.Int32Data, rows, total, widths)
+	case tensor.Int64:
+		concat(inputs, func(t *tensor.Tensor) []int64 { return t.Int64Data }, output.Int64Data, rows, total, widths)
+	case tensor.String:
+		concat(inputs, func(t *tensor.Tensor) [][]byte { return t.StringData }, output.StringData, rows, total, widths)
+	case tensor.Bool:
+		concat(inputs, func(t *tensor.Tensor) []bool { return t.BoolData }, output.BoolData, rows, total, widths)
+	default:
+		return fmt.Errorf("concat: input datatype (%v) is invalid", first.DType)
+	}
+	return nil
+}
+
+func scaled(widths []int, factor int) []int {
+	for i := range widths {
+		widths[i] *= factor
+	}
+	return widths
+}
+
+func concat[T any](inputs []*tensor.Tensor, data func(*tensor.Tensor) []T, out []T, rows, total int, widths []int) {
+	for row := range rows {
+		offset := row * total
+		for i, input := range inputs {
+			w := widths[i]
+			copy(out[offset:offset+w], data(input)[row*w:(row+1)*w])
+			offset += w
+		}
+	}
+}
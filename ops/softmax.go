@@ -0,0 +1,65 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Softmax normalizes the rows of its input. The axis defaults to the last one from opset 13 and to
+// 1 before, which is the same axis for the matrices it is given here.
+type Softmax struct {
+	input  int
+	axis   int
+	output int
+}
+
+func (s *Softmax) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	s.input = input
+	s.axis = -1
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axis":
+			s.axis = int(attr.I)
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	s.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (s *Softmax) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(s.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	if input.DType != tensor.Float && input.DType != tensor.Double {
+		return fmt.Errorf("softmax: input datatype (%v) is invalid", input.DType)
+	}
+	rank := len(input.Shape)
+	if rank == 0 || rank > 2 {
+		return fmt.Errorf("softmax: invalid shape %v", input.Shape)
+	}
+	if s.axis != -1 && s.axis != rank-1 {
+		return fmt.Errorf("softmax: only the last axis is supported, got %d for shape %v", s.axis, input.Shape)
+	}
+	output, err := shareOrClone(data)
+	if err != nil {
+		return err
+	}
+	rows, cols := matrixShape(input.Shape)
+	if output.DType == tensor.Float {
+		tensor.SoftMax(output.FloatData, []int{rows, cols})
+	} else {
+		tensor.SoftMax(output.DoubleData, []int{rows, cols})
+	}
+	return k.Put(s.output, output)
+}
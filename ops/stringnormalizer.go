@@ -0,0 +1,125 @@
+package ops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+type caseChange int
+
+const (
+	caseChangeNone caseChange = iota
+	caseChangeLower
+	caseChangeUpper
+)
+
+type StringNormalizer struct {
+	input              int
+	output             int
+	case_change_action caseChange
+	is_case_sensitive  bool
+	stopwords          map[string]struct{}
+}
+
+func (s *StringNormalizer) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	s.input = input
+	s.case_change_action = caseChangeNone
+	var stopwords [][]byte
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "case_change_action":
+			action := string(attr.S)
+			if action == "LOWER" {
+				s.case_change_action = caseChangeLower
+			} else if action == "UPPER" {
+				s.case_change_action = caseChangeUpper
+			} else if action == "NONE" || action == "" {
+				s.case_change_action = caseChangeNone
+			} else {
+				return fmt.Errorf("%s not supported as a case_change_action for the StringNormalizer op", action)
+			}
+		case "is_case_sensitive":
+			s.is_case_sensitive = attr.I != 0
+		case "locale":
+			// Go's unicode case mapping is locale independent, so the locale is only accepted.
+		case "stopwords":
+			stopwords = attr.Strings
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+
+	// When the comparison is case insensitive, both the stopwords and the words are lowered before matching.
+	s.stopwords = make(map[string]struct{}, len(stopwords))
+	for _, word := range stopwords {
+		w := string(word)
+		if !s.is_case_sensitive {
+			w = strings.ToLower(w)
+		}
+		s.stopwords[w] = struct{}{}
+	}
+	s.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (s *StringNormalizer) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(s.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	if input.DType != tensor.String {
+		return fmt.Errorf("stringnormalizer: input datatype (%v) is invalid", input.DType)
+	}
+	if len(input.Shape) > 2 || (len(input.Shape) == 2 && input.Shape[0] != 1) {
+		return fmt.Errorf("stringnormalizer: want input of shape [C] or [1, C], got %v", input.Shape)
+	}
+	length := input.Shape[0]
+	if len(input.Shape) == 2 {
+		length = input.Shape[1]
+	}
+
+	words := make([][]byte, 0, length)
+	for i := range length {
+		word := string(input.StringData[i])
+		if len(s.stopwords) > 0 {
+			key := word
+			if !s.is_case_sensitive {
+				key = strings.ToLower(word)
+			}
+			if _, ok := s.stopwords[key]; ok {
+				continue
+			}
+		}
+		switch s.case_change_action {
+		case caseChangeLower:
+			word = strings.ToLower(word)
+		case caseChangeUpper:
+			word = strings.ToUpper(word)
+		}
+		words = append(words, []byte(word))
+	}
+
+	// If every word was a stopword, the output holds a single empty string.
+	if len(words) == 0 {
+		words = append(words, []byte{})
+	}
+	shape := []int{len(words)}
+	if len(input.Shape) == 2 {
+		shape = []int{1, len(words)}
+	}
+	output, err := k.Output(s.output, shape, tensor.String)
+	if err != nil {
+		return err
+	}
+	copy(output.StringData, words)
+	return nil
+}
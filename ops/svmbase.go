@@ -15,51 +15,171 @@ const (
 	Rbf
 )
 
+// Blocks of samples and support vectors the RBF kernel is computed by, small enough for a block
+// of both and their dot products to stay in cache
+const (
+	rbfBlockRows    = 64
+	rbfBlockVectors = 256
+)
+
 type SVMBase struct {
 	gamma       float32
 	coef0       float32
 	degree      float32
 	kernel_type kernelType
+	norms       []float64 // squared norms of the support vectors, for the RBF kernel
+	dots        *tensor.Tensor
+	dot_workers int // goroutines large dot products are split over, the DotWorkers option
+}
+
+// Caches the squared norms of the rows of the support vectors, they are the same for every input
+func (s *SVMBase) cacheNorms(support_vectors *tensor.Tensor) {
+	s.norms = squaredNorms(support_vectors)
+}
+
+func squaredNorms(t *tensor.Tensor) []float64 {
+	rows, cols := t.Shape[0], t.Shape[1]
+	norms := make([]float64, rows)
+	for i := range rows {
+		sum := float64(0)
+		if t.DType == tensor.Double {
+			for _, v := range t.DoubleData[i*cols : (i+1)*cols] {
+				sum += v * v
+			}
+		} else {
+			for _, v := range t.FloatData[i*cols : (i+1)*cols] {
+				sum += float64(v) * float64(v)
+			}
+		}
+		norms[i] = sum
+	}
+	return norms
 }
 
+/*
+ * batched_kernel_dot applies the kernel to every row of a and every row of b, writing the
+ * [rows of a, rows of b] result to out. The three tensors are either all Float or all Double, so
+ * double inputs are scored in double precision. The kernel parameters are floats, like the
+ * attributes they come from.
+ */
 func (s *SVMBase) batched_kernel_dot(a *tensor.Tensor, b *tensor.Tensor, out *tensor.Tensor, scalar_c float32) {
-	m := a.Shape[0]
-	k := a.Shape[1]
-	n := b.Shape[0]
 	if s.kernel_type == Rbf {
-		for batch := range m {
-			for support_vector := range n {
-				sum := float32(0)
-				for feature := range k {
-					val := a.FloatData[batch*k+feature] - b.FloatData[support_vector*k+feature]
-					sum += val * val
-				}
-				out.FloatData[batch*n+support_vector] = float32(math.Exp(-float64(s.gamma * sum)))
-			}
+		s.rbfKernel(a, b, out)
+		return
+	}
+
+	a.ParallelDot(b, out, s.dot_workers)
+	alpha := float32(1)
+	c := scalar_c
+	if s.kernel_type != Linear {
+		alpha = s.gamma
+		c = s.coef0
+	}
+	length := out.Shape[0] * out.Shape[1]
+	if out.DType == tensor.Double {
+		for i := range length {
+			out.DoubleData[i] = out.DoubleData[i]*float64(alpha) + float64(c)
 		}
 	} else {
-		a.Dot(b, out)
-		alpha := float32(1)
-		c := scalar_c
-		if s.kernel_type != Linear {
-			alpha = s.gamma
-			c = s.coef0
-		}
-
-		length := out.Shape[0] * out.Shape[1]
 		for i := range length {
 			out.FloatData[i] = out.FloatData[i]*alpha + c
 		}
-		if s.kernel_type == Poly {
-			if s.degree == 2 {
-				out.Square()
-			} else if s.degree == 3 {
-				out.Cube()
-			} else {
-				out.Power(float64(s.degree))
+	}
+	if s.kernel_type == Poly {
+		if s.degree == 2 {
+			out.Square()
+		} else if s.degree == 3 {
+			out.Cube()
+		} else {
+			out.Power(float64(s.degree))
+		}
+	} else if s.kernel_type == Sigmoid {
+		out.Tanh(out)
+	}
+}
+
+// rbfKernel computes exp(-gamma ||a - b||²) from the squared distances of the rows of a and b
+func (s *SVMBase) rbfKernel(a, b, out *tensor.Tensor) {
+	norms := s.norms
+	if len(norms) != b.Shape[0] {
+		norms = squaredNorms(b)
+	}
+	gamma := float64(s.gamma)
+	s.dots = squaredDistances(a, b, norms, s.dots, s.dot_workers, func(index int, distance float64) {
+		kernel := math.Exp(-gamma * distance)
+		if out.DType == tensor.Double {
+			out.DoubleData[index] = kernel
+		} else {
+			out.FloatData[index] = float32(kernel)
+		}
+	})
+}
+
+/*
+ * squaredDistances calls f with every position of the [rows of a, rows of b] output and the
+ * squared euclidean distance of the two rows, computed as ||a||² + ||b||² - 2 a.b. The work is in
+ * the dot products, done with Dot one block at a time. They are accumulated in double precision,
+ * the cancellation when a and b are close would be too large in float. norms are the squared
+ * norms of the rows of b, dots is the buffer of the dot products, returned to be reused. Each
+ * block is split over at most workers goroutines.
+ */
+func squaredDistances(a, b *tensor.Tensor, norms []float64, dots *tensor.Tensor, workers int, f func(index int, distance float64)) *tensor.Tensor {
+	m, n := a.Shape[0], b.Shape[0]
+	dots = tensor.CreateOrReuseTensor(dots, []int{rbfBlockRows, rbfBlockVectors}, tensor.Double)
+	for row := 0; row < m; row += rbfBlockRows {
+		row_end := min(row+rbfBlockRows, m)
+		rows := rowsView(a, row, row_end)
+		row_norms := squaredNorms(rows)
+		for vector := 0; vector < n; vector += rbfBlockVectors {
+			vector_end := min(vector+rbfBlockVectors, n)
+			width := vector_end - vector
+			dots.Shape = []int{row_end - row, width}
+			rows.ParallelDot(rowsView(b, vector, vector_end), dots, workers)
+			for i := range row_end - row {
+				offset := (row+i)*n + vector
+				for j, dot := range dots.DoubleData[i*width : (i+1)*width] {
+					f(offset+j, max(row_norms[i]+norms[vector+j]-2*dot, 0))
+				}
 			}
-		} else if s.kernel_type == Sigmoid {
-			out.Tanh(out)
 		}
 	}
+	return dots
+}
+
+// Returns the rows [start, end) of a 2-D tensor without copying them
+func rowsView(t *tensor.Tensor, start, end int) *tensor.Tensor {
+	cols := t.Shape[1]
+	view := &tensor.Tensor{Shape: []int{end - start, cols}, DType: t.DType}
+	if t.DType == tensor.Double {
+		view.DoubleData = t.DoubleData[start*cols : end*cols]
+	} else {
+		view.FloatData = t.FloatData[start*cols : end*cols]
+	}
+	return view
+}
+
+// Returns a [rows, features] view of the input of an SVM. Integer inputs are scored as floats,
+// the tensor read from the kernel is never changed.
+func svmInput(input *tensor.Tensor, op string) (*tensor.Tensor, error) {
+	view, err := linearInput(input, op)
+	if err != nil {
+		return nil, err
+	}
+	if view.DType == tensor.Int32 || view.DType == tensor.Int64 {
+		view, err = view.Clone()
+		if err != nil {
+			return nil, err
+		}
+		view.Cast(tensor.Float)
+	}
+	return view, nil
+}
+
+// Returns a Double copy of a Float attribute tensor
+func doubleTensor(t *tensor.Tensor) *tensor.Tensor {
+	values := make([]float64, len(t.FloatData))
+	for i := range t.FloatData {
+		values[i] = float64(t.FloatData[i])
+	}
+	return &tensor.Tensor{Shape: t.Shape, DType: tensor.Double, DoubleData: values}
 }
@@ -0,0 +1,55 @@
+package ops
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// The blocked kernel has to match exp(-gamma ||a - b||²) computed pair by pair, across more
+// samples and support vectors than fit in a single block
+func TestSVMBaseBlockedRbf(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	m, n, k := rbfBlockRows*2+5, rbfBlockVectors*2+17, 9
+	a := tensor.CreateEmptyTensor([]int{m, k}, tensor.Float)
+	b := tensor.CreateEmptyTensor([]int{n, k}, tensor.Float)
+	for i := range a.FloatData {
+		a.FloatData[i] = float32(r.NormFloat64() * 10)
+	}
+	for i := range b.FloatData {
+		b.FloatData[i] = float32(r.NormFloat64() * 10)
+	}
+	copy(b.FloatData[:k], a.FloatData[:k]) // a sample that is also a support vector
+
+	for _, dtype := range []tensor.DataType{tensor.Float, tensor.Double} {
+		s := SVMBase{gamma: 0.01, kernel_type: Rbf}
+		s.cacheNorms(b)
+		input, vectors := a, b
+		if dtype == tensor.Double {
+			input, vectors = doubleTensor(a), doubleTensor(b)
+		}
+		out := tensor.CreateEmptyTensor([]int{m, n}, dtype)
+		s.batched_kernel_dot(input, vectors, out, 0)
+		for i := range m {
+			for j := range n {
+				sum := float64(0)
+				for f := range k {
+					val := float64(a.FloatData[i*k+f]) - float64(b.FloatData[j*k+f])
+					sum += val * val
+				}
+				want := math.Exp(-0.01 * sum)
+				got := float64(0)
+				if dtype == tensor.Double {
+					got = out.DoubleData[i*n+j]
+				} else {
+					got = float64(out.FloatData[i*n+j])
+				}
+				if math.Abs(got-want) > 1e-6 {
+					t.Fatalf("%v kernel of sample %d and vector %d: expected %v, got %v", dtype, i, j, want, got)
+				}
+			}
+		}
+	}
+}
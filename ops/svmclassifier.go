@@ -22,6 +22,8 @@ type SVMClassifier struct {
 	probb                    *tensor.Tensor
 	support_vectors          *tensor.Tensor
 	coefficients             *tensor.Tensor
+	support_vectors64        *tensor.Tensor // copies of support_vectors and coefficients for double inputs
+	coefficients64           *tensor.Tensor
 	classlabels              []int64
 	classlabels_string       [][]byte
 	mode                     svmType
@@ -41,7 +43,7 @@ func (s *SVMClassifier) Init(k *kernel.Kernel, node *ir.NodeProto) error {
 	}
 
 	s.input = input
-	s.base = SVMBase{}
+	s.base = SVMBase{dot_workers: k.Options.DotWorkers}
 	using_strings := false
 	s.post_transform = NONE
 	for _, attr := range node.Attribute {
@@ -151,6 +153,11 @@ func (s *SVMClassifier) Init(k *kernel.Kernel, node *ir.NodeProto) error {
 		s.mode = svmLinear
 		s.base.kernel_type = Linear
 	}
+	if s.support_vectors != nil {
+		s.support_vectors64 = doubleTensor(s.support_vectors)
+		s.base.cacheNorms(s.support_vectors)
+	}
+	s.coefficients64 = doubleTensor(s.coefficients)
 	s.outputs = make([]int, len(node.Output))
 
 	for i, output := range node.Output {
@@ -191,13 +198,9 @@ func (s *SVMClassifier) Compute(k *kernel.Kernel) error {
 	if err != nil {
 		return err
 	}
-	input := data.Tensor
-	if len(input.Shape) > 2 {
-		return fmt.Errorf("svmclassifier: invalid shape %v", input.Shape)
-	}
-
-	if len(input.Shape) == 1 {
-		input.Shape = []int{1, input.Shape[0]}
+	input, err := svmInput(data.Tensor, "svmclassifier")
+	if err != nil {
+		return err
 	}
 	num_batches := input.Shape[0]
 	num_features := input.Shape[1]
@@ -207,7 +210,7 @@ func (s *SVMClassifier) Compute(k *kernel.Kernel) error {
 	if num_features <= 0 || num_batches <= 0 {
 		return fmt.Errorf("svmclassifier: illegal num_features (%d) or illegal num_batches (%d)", num_features, num_batches)
 	}
-	input.Cast(tensor.Float)
+	double := input.DType == tensor.Double
 
 	// Total number of classifiers comparing pairs between the classes
 	num_classifiers := (s.class_count * (s.class_count - 1)) / 2
@@ -261,7 +264,15 @@ func (s *SVMClassifier) Compute(k *kernel.Kernel) error {
 		// input: [num_batches, feature_count]
 		// coefficients: [class_count, feature_count]
 		// out: [num_batches, class_count]
-		s.base.batched_kernel_dot(input, s.coefficients, final_scores, s.rho[0])
+		if double {
+			s.kernels_data = tensor.CreateOrReuseTensor(s.kernels_data, []int{num_batches, s.class_count}, tensor.Double)
+			s.base.batched_kernel_dot(input, s.coefficients64, s.kernels_data, s.rho[0])
+			for i := range num_batches * s.class_count {
+				final_scores.FloatData[i] = float32(s.kernels_data.DoubleData[i])
+			}
+		} else {
+			s.base.batched_kernel_dot(input, s.coefficients, final_scores, s.rho[0])
+		}
 
 	} else {
 		// if we have one classifier, are writing directly to the final buffer,
@@ -281,66 +292,27 @@ func (s *SVMClassifier) Compute(k *kernel.Kernel) error {
 			// write directly to the final score output.
 			classifier_scores_data = final_scores.FloatData
 		}
-		s.kernels_data = tensor.CreateOrReuseTensor(s.kernels_data, []int{num_batches, s.vector_count}, tensor.Float)
+		s.kernels_data = tensor.CreateOrReuseTensor(s.kernels_data, []int{num_batches, s.vector_count}, input.DType)
 		s.votes_data = tensor.CreateOrReuseTensor(s.votes_data, []int{num_batches * s.class_count}, tensor.Int64)
+		clear(s.votes_data.Int64Data)
 
 		// combine the input data with the support vectors and apply the kernel type, write output to kernel
 		// input: [num_batches, feature_count]
 		// support_vectores: [vector_count, feature_count]
 		// kernel: [num_batches, vector_count]
-		s.base.batched_kernel_dot(input, s.support_vectors, s.kernels_data, 0)
+		support_vectors := s.support_vectors
+		if double {
+			support_vectors = s.support_vectors64
+		}
+		s.base.batched_kernel_dot(input, support_vectors, s.kernels_data, 0)
+		// reduce scores from kernels using coefficients, taking into account the varying number of support vectors
 		for n := range num_batches {
-			// reduce scores from kernels using coefficients, taking into account the varying number of support vectors
-
-			cur_kernels := s.kernels_data.FloatData[n*s.vector_count:]
 			cur_scores := classifier_scores_data[n*num_slots_per_iteration:]
 			cur_votes := s.votes_data.Int64Data[n*s.class_count:]
-			scores_iter := 0
-
-			classifier_idx := 0
-			for i := range s.class_count - 1 {
-				start_index_i := s.starting_vector[i] // start of support vectors for class i
-				class_i_support_count := s.vectors_per_class[i]
-				i_coeff_row_offset := s.vector_count * i
-
-				for j := i + 1; j < s.class_count; j++ {
-					start_index_j := s.starting_vector[j] // start of support vectors for class j
-					class_j_support_count := s.vectors_per_class[j]
-					j_coeff_row_offset := s.vector_count * (j - 1)
-
-					sum := float64(0)
-
-					val1_index := j_coeff_row_offset + int(start_index_i)
-					val2_index := start_index_i
-					for range class_i_support_count {
-						val1 := s.coefficients.FloatData[val1_index]
-						val2 := cur_kernels[val2_index]
-						sum += float64(val1 * val2)
-						val1_index++
-						val2_index++
-					}
-
-					val1_index = i_coeff_row_offset + int(start_index_j)
-					val2_index = start_index_j
-					for range class_j_support_count {
-						val1 := s.coefficients.FloatData[val1_index]
-						val2 := cur_kernels[val2_index]
-						sum += float64(val1 * val2)
-						val1_index++
-						val2_index++
-					}
-
-					sum += float64(s.rho[classifier_idx])
-					classifier_idx++
-
-					cur_scores[scores_iter] = float32(sum)
-					scores_iter++
-					if sum > 0 {
-						cur_votes[i]++
-					} else {
-						cur_votes[j]++
-					}
-				}
+			if double {
+				reduceKernels(s, s.kernels_data.DoubleData[n*s.vector_count:], cur_scores, cur_votes)
+			} else {
+				reduceKernels(s, s.kernels_data.FloatData[n*s.vector_count:], cur_scores, cur_votes)
 			}
 		}
 	}
@@ -417,3 +389,54 @@ func (s *SVMClassifier) Compute(k *kernel.Kernel) error {
 	}
 	return nil
 }
+
+// Computes the score of every pair of classes for a sample from its kernels, each classifier
+// voting for one of the two classes
+func reduceKernels[T tensor.Float32_64](s *SVMClassifier, cur_kernels []T, cur_scores []float32, cur_votes []int64) {
+	scores_iter := 0
+	classifier_idx := 0
+	for i := range s.class_count - 1 {
+		start_index_i := s.starting_vector[i] // start of support vectors for class i
+		class_i_support_count := s.vectors_per_class[i]
+		i_coeff_row_offset := s.vector_count * i
+
+		for j := i + 1; j < s.class_count; j++ {
+			start_index_j := s.starting_vector[j] // start of support vectors for class j
+			class_j_support_count := s.vectors_per_class[j]
+			j_coeff_row_offset := s.vector_count * (j - 1)
+
+			sum := float64(0)
+
+			val1_index := j_coeff_row_offset + int(start_index_i)
+			val2_index := start_index_i
+			for range class_i_support_count {
+				val1 := T(s.coefficients.FloatData[val1_index])
+				val2 := cur_kernels[val2_index]
+				sum += float64(val1 * val2)
+				val1_index++
+				val2_index++
+			}
+
+			val1_index = i_coeff_row_offset + int(start_index_j)
+			val2_index = start_index_j
+			for range class_j_support_count {
+				val1 := T(s.coefficients.FloatData[val1_index])
+				val2 := cur_kernels[val2_index]
+				sum += float64(val1 * val2)
+				val1_index++
+				val2_index++
+			}
+
+			sum += float64(s.rho[classifier_idx])
+			classifier_idx++
+
+			cur_scores[scores_iter] = float32(sum)
+			scores_iter++
+			if sum > 0 {
+				cur_votes[i]++
+			} else {
+				cur_votes[j]++
+			}
+		}
+	}
+}
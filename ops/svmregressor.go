@@ -16,17 +16,19 @@ const (
 )
 
 type SVMRegressor struct {
-	base            SVMBase
-	input           int
-	output          int
-	vector_count    int
-	feature_count   int
-	support_vectors *tensor.Tensor
-	coefficients    *tensor.Tensor
-	temp            *tensor.Tensor
-	rho             float32
-	one_class       bool
-	mode            svmType
+	base              SVMBase
+	input             int
+	output            int
+	vector_count      int
+	feature_count     int
+	support_vectors   *tensor.Tensor
+	coefficients      *tensor.Tensor
+	support_vectors64 *tensor.Tensor // copies of support_vectors and coefficients for double inputs
+	coefficients64    *tensor.Tensor
+	temp              *tensor.Tensor
+	rho               float32
+	one_class         bool
+	mode              svmType
 }
 
 func (s *SVMRegressor) Init(k *kernel.Kernel, node *ir.NodeProto) error {
@@ -36,7 +38,7 @@ func (s *SVMRegressor) Init(k *kernel.Kernel, node *ir.NodeProto) error {
 	}
 
 	s.input = input
-	s.base = SVMBase{}
+	s.base = SVMBase{dot_workers: k.Options.DotWorkers}
 	var rho []float32
 	for _, attr := range node.Attribute {
 		switch attr.Name {
@@ -99,6 +101,11 @@ func (s *SVMRegressor) Init(k *kernel.Kernel, node *ir.NodeProto) error {
 		s.mode = svmLinear
 		s.base.kernel_type = Linear
 	}
+	if s.support_vectors != nil {
+		s.support_vectors64 = doubleTensor(s.support_vectors)
+		s.base.cacheNorms(s.support_vectors)
+	}
+	s.coefficients64 = doubleTensor(s.coefficients)
 	s.output = k.RegisterWriter(node.Output[0])
 	return nil
 }
@@ -108,13 +115,9 @@ func (s *SVMRegressor) Compute(k *kernel.Kernel) error {
 	if err != nil {
 		return err
 	}
-	input := data.Tensor
-	if len(input.Shape) > 2 {
-		return fmt.Errorf("svmregressor: invalid shape %v", input.Shape)
-	}
-
-	if len(input.Shape) == 1 {
-		input.Shape = []int{1, input.Shape[0]}
+	input, err := svmInput(data.Tensor, "svmregressor")
+	if err != nil {
+		return err
 	}
 	num_batches := input.Shape[0]
 	num_features := input.Shape[1]
@@ -130,20 +133,42 @@ func (s *SVMRegressor) Compute(k *kernel.Kernel) error {
 
 	output, err := k.Output(s.output, []int{num_batches, 1}, tensor.Float)
 	if err != nil {
-		return nil
+		return err
 	}
-	input.Cast(tensor.Float)
+	double := input.DType == tensor.Double
 	if s.mode == svmSvc {
-		s.temp = tensor.CreateOrReuseTensor(s.temp, []int{num_batches, s.vector_count}, tensor.Float)
-		s.base.batched_kernel_dot(input, s.support_vectors, s.temp, 0)
-		s.temp.Dot(s.coefficients, output)
+		support_vectors := s.support_vectors
+		if double {
+			support_vectors = s.support_vectors64
+		}
+		s.temp = tensor.CreateOrReuseTensor(s.temp, []int{num_batches, s.vector_count}, input.DType)
+		s.base.batched_kernel_dot(input, support_vectors, s.temp, 0)
+		if double {
+			for i := range num_batches {
+				sum := float64(s.rho)
+				for j, kernel := range s.temp.DoubleData[i*s.vector_count : (i+1)*s.vector_count] {
+					sum += float64(s.coefficients.FloatData[j]) * kernel
+				}
+				output.FloatData[i] = float32(sum)
+			}
+		} else {
+			s.temp.Dot(s.coefficients, output)
+			for i := range num_batches {
+				output.FloatData[i] = output.FloatData[i] + s.rho
+			}
+		}
+	} else if double {
+		s.temp = tensor.CreateOrReuseTensor(s.temp, []int{num_batches, 1}, tensor.Double)
+		s.base.batched_kernel_dot(input, s.coefficients64, s.temp, s.rho)
 		for i := range num_batches {
-			output.FloatData[i] = output.FloatData[i] + s.rho
+			output.FloatData[i] = float32(s.temp.DoubleData[i])
 		}
 	} else {
 		s.base.batched_kernel_dot(input, s.coefficients, output, s.rho)
 	}
 
+	// A one-class SVM predicts an inlier (1) when the decision function is positive, an outlier (-1)
+	// otherwise, like libsvm
 	if s.one_class {
 		for i := range num_batches {
 			if output.FloatData[i] > 0 {
@@ -0,0 +1,292 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+type weightingMode int
+
+const (
+	weightingTF weightingMode = iota
+	weightingIDF
+	weightingTFIDF
+)
+
+/*
+ * The n-grams in the pool are stored in a trie so that all the n-grams starting at a position
+ * can be matched in a single walk. `id` is the 1-based position of the n-gram in the pool,
+ * 0 means the node is only a prefix of a longer n-gram.
+ */
+type ngramNode struct {
+	id      int
+	strings map[string]*ngramNode
+	ints    map[int64]*ngramNode
+}
+
+func (n *ngramNode) child(item any, create bool) *ngramNode {
+	var next *ngramNode
+	switch item := item.(type) {
+	case string:
+		next = n.strings[item]
+		if next == nil && create {
+			if n.strings == nil {
+				n.strings = make(map[string]*ngramNode)
+			}
+			next = &ngramNode{}
+			n.strings[item] = next
+		}
+	case int64:
+		next = n.ints[item]
+		if next == nil && create {
+			if n.ints == nil {
+				n.ints = make(map[int64]*ngramNode)
+			}
+			next = &ngramNode{}
+			n.ints[item] = next
+		}
+	}
+	return next
+}
+
+type TfIdfVectorizer struct {
+	input           int
+	output          int
+	min_gram_length int
+	max_gram_length int
+	max_skip_count  int
+	mode            weightingMode
+	ngram_counts    []int64
+	ngram_indexes   []int64
+	pool_int64s     []int64
+	pool_strings    [][]byte
+	weights         []float32
+	output_size     int
+	pool            *ngramNode
+}
+
+func (t *TfIdfVectorizer) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	t.input = input
+	has_mode := false
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "min_gram_length":
+			t.min_gram_length = int(attr.I)
+		case "max_gram_length":
+			t.max_gram_length = int(attr.I)
+		case "max_skip_count":
+			t.max_skip_count = int(attr.I)
+		case "mode":
+			mode := string(attr.S)
+			if mode == "TF" {
+				t.mode = weightingTF
+			} else if mode == "IDF" {
+				t.mode = weightingIDF
+			} else if mode == "TFIDF" {
+				t.mode = weightingTFIDF
+			} else {
+				return fmt.Errorf("%s not supported as a mode for the TfIdfVectorizer op", mode)
+			}
+			has_mode = true
+		case "ngram_counts":
+			t.ngram_counts = attr.Ints
+		case "ngram_indexes":
+			t.ngram_indexes = attr.Ints
+		case "pool_int64s":
+			t.pool_int64s = attr.Ints
+		case "pool_strings":
+			t.pool_strings = attr.Strings
+		case "weights":
+			t.weights = attr.Floats
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+
+	if !has_mode {
+		return fmt.Errorf("tfidfvectorizer: mode attribute is required")
+	}
+	if t.min_gram_length < 1 || t.max_gram_length < t.min_gram_length {
+		return fmt.Errorf("tfidfvectorizer: invalid gram lengths, min (%d) max (%d)", t.min_gram_length, t.max_gram_length)
+	}
+	if t.max_skip_count < 0 {
+		return fmt.Errorf("tfidfvectorizer: max_skip_count (%d) cannot be negative", t.max_skip_count)
+	}
+	if (len(t.pool_strings) > 0) == (len(t.pool_int64s) > 0) {
+		return fmt.Errorf("tfidfvectorizer: exactly one of pool_strings and pool_int64s must be set")
+	}
+	if len(t.ngram_indexes) == 0 {
+		return fmt.Errorf("tfidfvectorizer: ngram_indexes attribute cannot be empty")
+	}
+	if len(t.weights) > 0 && len(t.weights) != len(t.ngram_indexes) {
+		return fmt.Errorf("tfidfvectorizer: weights length (%d) != ngram_indexes length (%d)", len(t.weights), len(t.ngram_indexes))
+	}
+
+	for _, index := range t.ngram_indexes {
+		if index < 0 {
+			return fmt.Errorf("tfidfvectorizer: ngram index %d cannot be negative", index)
+		}
+		t.output_size = max(t.output_size, int(index)+1)
+	}
+	// Weights are applied per output column
+	if len(t.weights) > 0 && len(t.weights) < t.output_size {
+		return fmt.Errorf("tfidfvectorizer: weights length (%d) is less than the output size (%d)", len(t.weights), t.output_size)
+	}
+
+	err = t.buildPool()
+	if err != nil {
+		return err
+	}
+	t.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+// Loads the pool into a trie. ngram_counts[i] is the position in the pool where the
+// n-grams of length i+1 start.
+func (t *TfIdfVectorizer) buildPool() error {
+	pool_size := len(t.pool_int64s)
+	if len(t.pool_strings) > 0 {
+		pool_size = len(t.pool_strings)
+	}
+	t.pool = &ngramNode{}
+	ngram_id := 0
+	for i, start := range t.ngram_counts {
+		gram_length := i + 1
+		end := int64(pool_size)
+		if i+1 < len(t.ngram_counts) {
+			end = t.ngram_counts[i+1]
+		}
+		if start < 0 || start > end || end > int64(pool_size) {
+			return fmt.Errorf("tfidfvectorizer: invalid ngram_counts %v for a pool of size %d", t.ngram_counts, pool_size)
+		}
+		if (end-start)%int64(gram_length) != 0 {
+			return fmt.Errorf("tfidfvectorizer: %d items cannot be split into %d-grams", end-start, gram_length)
+		}
+		for s := int(start); s < int(end); s += gram_length {
+			node := t.pool
+			for j := s; j < s+gram_length; j++ {
+				if len(t.pool_strings) > 0 {
+					node = node.child(string(t.pool_strings[j]), true)
+				} else {
+					node = node.child(t.pool_int64s[j], true)
+				}
+			}
+			ngram_id++
+			node.id = ngram_id
+		}
+	}
+	if ngram_id != len(t.ngram_indexes) {
+		return fmt.Errorf("tfidfvectorizer: pool contains %d n-grams but ngram_indexes has %d entries", ngram_id, len(t.ngram_indexes))
+	}
+	return nil
+}
+
+// Counts the pool n-grams occurring in a row. Unigrams are unaffected by the skip distance,
+// so they are only counted on the first pass.
+func (t *TfIdfVectorizer) countRow(item func(int) any, length int, frequencies []float32) {
+	start_gram_length := t.min_gram_length
+	for skip_distance := 1; skip_distance <= t.max_skip_count+1; skip_distance++ {
+		for start := 0; start < length; start++ {
+			if start+skip_distance*(start_gram_length-1) >= length {
+				break
+			}
+			node := t.pool
+			position := start
+			for gram_length := 1; gram_length <= t.max_gram_length && position < length; gram_length++ {
+				node = node.child(item(position), false)
+				if node == nil {
+					break
+				}
+				if gram_length >= start_gram_length && node.id != 0 {
+					frequencies[t.ngram_indexes[node.id-1]]++
+				}
+				position += skip_distance
+			}
+		}
+		if start_gram_length == 1 {
+			start_gram_length++
+			if start_gram_length > t.max_gram_length {
+				break
+			}
+		}
+	}
+}
+
+func (t *TfIdfVectorizer) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(t.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	if len(input.Shape) > 2 {
+		return fmt.Errorf("tfidfvectorizer: invalid shape %v", input.Shape)
+	}
+
+	var item func(int) any
+	switch input.DType {
+	case tensor.String:
+		if len(t.pool_strings) == 0 {
+			return fmt.Errorf("tfidfvectorizer: string input needs pool_strings")
+		}
+		item = func(i int) any { return string(input.StringData[i]) }
+	case tensor.Int64:
+		if len(t.pool_int64s) == 0 {
+			return fmt.Errorf("tfidfvectorizer: integer input needs pool_int64s")
+		}
+		item = func(i int) any { return input.Int64Data[i] }
+	case tensor.Int32:
+		if len(t.pool_int64s) == 0 {
+			return fmt.Errorf("tfidfvectorizer: integer input needs pool_int64s")
+		}
+		item = func(i int) any { return int64(input.Int32Data[i]) }
+	default:
+		return fmt.Errorf("tfidfvectorizer: input datatype (%v) is invalid", input.DType)
+	}
+
+	rows := 1
+	cols := input.Shape[0]
+	shape := []int{t.output_size}
+	if len(input.Shape) == 2 {
+		rows = input.Shape[0]
+		cols = input.Shape[1]
+		shape = []int{rows, t.output_size}
+	}
+	output, err := k.Output(t.output, shape, tensor.Float)
+	if err != nil {
+		return err
+	}
+
+	for i := range rows {
+		frequencies := output.FloatData[i*t.output_size : (i+1)*t.output_size]
+		clear(frequencies)
+		offset := i * cols
+		t.countRow(func(j int) any { return item(offset + j) }, cols, frequencies)
+
+		switch t.mode {
+		case weightingIDF:
+			for j := range frequencies {
+				if frequencies[j] > 0 {
+					if len(t.weights) > 0 {
+						frequencies[j] = t.weights[j]
+					} else {
+						frequencies[j] = 1
+					}
+				}
+			}
+		case weightingTFIDF:
+			if len(t.weights) > 0 {
+				for j := range frequencies {
+					frequencies[j] *= t.weights[j]
+				}
+			}
+		}
+	}
+	return nil
+}
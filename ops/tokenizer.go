@@ -0,0 +1,173 @@
+package ops
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+const (
+	startTextMark = "\x02"
+	endTextMark   = "\x03"
+)
+
+/*
+ * Tokenizer implements the com.microsoft Tokenizer op. Each string in the input is split either
+ * by the separators (a list of regular expressions) or by matching tokenexp, tokens shorter than
+ * mincharnum are dropped and every row is padded with pad_value up to the longest row.
+ *
+ * An [N] input produces an [N, T] output and an [N, C] input an [N, C, T] one, T being the most
+ * tokens any string has.
+ */
+type Tokenizer struct {
+	input         int
+	output        int
+	mark          bool
+	pad_value     []byte
+	mincharnum    int
+	char_tokenize bool
+	separators    *regexp.Regexp
+	tokenexp      *regexp.Regexp
+}
+
+func (t *Tokenizer) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	t.input = input
+	has_pad_value := false
+	var separators [][]byte
+	var tokenexp string
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "mark":
+			t.mark = attr.I != 0
+		case "pad_value":
+			t.pad_value = attr.S
+			has_pad_value = true
+		case "mincharnum":
+			t.mincharnum = int(attr.I)
+		case "separators":
+			separators = attr.Strings
+		case "tokenexp":
+			tokenexp = string(attr.S)
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+
+	if !has_pad_value {
+		return fmt.Errorf("tokenizer: pad_value attribute is required")
+	}
+	if t.mincharnum < 1 {
+		return fmt.Errorf("tokenizer: mincharnum must be a positive integer, got %d", t.mincharnum)
+	}
+	if len(separators) > 0 && tokenexp != "" {
+		return fmt.Errorf("tokenizer: separators and tokenexp cannot both be set")
+	}
+	if len(separators) == 0 && tokenexp == "" {
+		return fmt.Errorf("tokenizer: one of separators or tokenexp must be set")
+	}
+
+	if tokenexp != "" {
+		t.tokenexp, err = regexp.Compile(tokenexp)
+		if err != nil {
+			return fmt.Errorf("tokenizer: invalid tokenexp %q: %v", tokenexp, err)
+		}
+	} else if len(separators) == 1 && len(separators[0]) == 0 {
+		// A single empty separator means every character is a token
+		t.char_tokenize = true
+	} else {
+		patterns := make([]string, len(separators))
+		for i := range separators {
+			if len(separators[i]) == 0 {
+				return fmt.Errorf("tokenizer: an empty separator can only be used on its own")
+			}
+			patterns[i] = "(?:" + string(separators[i]) + ")"
+		}
+		t.separators, err = regexp.Compile(strings.Join(patterns, "|"))
+		if err != nil {
+			return fmt.Errorf("tokenizer: invalid separators %q: %v", separators, err)
+		}
+	}
+
+	t.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (t *Tokenizer) tokenize(text string) []string {
+	var candidates []string
+	if t.char_tokenize {
+		candidates = make([]string, 0, len(text))
+		for _, r := range text {
+			candidates = append(candidates, string(r))
+		}
+	} else if t.tokenexp != nil {
+		candidates = t.tokenexp.FindAllString(text, -1)
+	} else {
+		candidates = t.separators.Split(text, -1)
+	}
+
+	tokens := make([]string, 0, len(candidates)+2)
+	if t.mark {
+		tokens = append(tokens, startTextMark)
+	}
+	for _, c := range candidates {
+		if len(c) > 0 && utf8.RuneCountInString(c) >= t.mincharnum {
+			tokens = append(tokens, c)
+		}
+	}
+	if t.mark {
+		tokens = append(tokens, endTextMark)
+	}
+	return tokens
+}
+
+func (t *Tokenizer) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(t.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	if input.DType != tensor.String {
+		return fmt.Errorf("tokenizer: input datatype (%v) is invalid", input.DType)
+	}
+	if len(input.Shape) == 0 || len(input.Shape) > 2 {
+		return fmt.Errorf("tokenizer: want input of shape [N] or [N, C], got %v", input.Shape)
+	}
+	rows := input.Shape[0]
+	if len(input.Shape) == 2 {
+		rows *= input.Shape[1]
+	}
+
+	tokens := make([][]string, rows)
+	max_tokens := 0
+	for i := range rows {
+		tokens[i] = t.tokenize(string(input.StringData[i]))
+		max_tokens = max(max_tokens, len(tokens[i]))
+	}
+
+	shape := append(slices.Clone(input.Shape), max_tokens)
+	output, err := k.Output(t.output, shape, tensor.String)
+	if err != nil {
+		return err
+	}
+	for i := range rows {
+		row := output.StringData[i*max_tokens : (i+1)*max_tokens]
+		for j := range row {
+			if j < len(tokens[i]) {
+				row[j] = []byte(tokens[i][j])
+			} else {
+				row[j] = t.pad_value
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package ops
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// TopK returns the k largest, or smallest, values along an axis and their indices. Equal values
+// are ordered by index, as in onnxruntime.
+type TopK struct {
+	input   int
+	k       int
+	axis    int
+	largest bool
+	sorted  bool
+	values  int
+	indices int
+}
+
+func (t *TopK) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 2 {
+		return fmt.Errorf("%s: the input and k are required", node.OpType)
+	}
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	t.input = input
+	count, err := k.RegisterReader(node.Input[1])
+	if err != nil {
+		return err
+	}
+	t.k = count
+	t.axis = -1
+	t.largest = true
+	t.sorted = true
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "axis":
+			t.axis = int(attr.I)
+		case "largest":
+			t.largest = attr.I != 0
+		case "sorted":
+			t.sorted = attr.I != 0
+		default:
+			return fmt.Errorf("%s not supported for %s", attr.Name, node.OpType)
+		}
+	}
+	if len(node.Output) != 2 {
+		return fmt.Errorf("%s: values and indices outputs are required", node.OpType)
+	}
+	t.values = k.RegisterWriter(node.Output[0])
+	t.indices = k.RegisterWriter(node.Output[1])
+	return nil
+}
+
+func (t *TopK) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(t.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	rank := len(input.Shape)
+	if rank == 0 || rank > 2 {
+		return fmt.Errorf("topk: invalid shape %v", input.Shape)
+	}
+	axis := t.axis
+	if axis < -rank || axis >= rank {
+		return fmt.Errorf("topk: axis %d is out of range for shape %v", t.axis, input.Shape)
+	}
+	if axis < 0 {
+		axis += rank
+	}
+	count, err := k.Input(t.k)
+	if err != nil {
+		return err
+	}
+	counts, err := intValues(count.Tensor, "topk")
+	if err != nil {
+		return err
+	}
+	if len(counts) != 1 {
+		return fmt.Errorf("topk: k should hold a single value, got shape %v", count.Tensor.Shape)
+	}
+	top := int(counts[0])
+	if top < 0 || top > input.Shape[axis] {
+		return fmt.Errorf("topk: k %d is out of range for axis %d of shape %v", top, axis, input.Shape)
+	}
+
+	shape := slices.Clone(input.Shape)
+	shape[axis] = top
+	values, err := k.Output(t.values, shape, input.DType)
+	if err != nil {
+		return err
+	}
+	indices, err := k.Output(t.indices, slices.Clone(shape), tensor.Int64)
+	if err != nil {
+		return err
+	}
+
+	// The axis is walked with a stride, lines are the rows for the last axis and the columns for
+	// the first axis of a matrix
+	rows, cols := matrixShape(input.Shape)
+	lines, length, stride := rows, cols, 1
+	if rank == 2 && axis == 0 {
+		lines, length, stride = cols, rows, cols
+	}
+	switch input.DType {
+	case tensor.Float:
+		topK(input.FloatData, values.FloatData, indices.Int64Data, lines, length, stride, top, t.largest, t.sorted)
+	case tensor.Double:
+		topK(input.DoubleData, values.DoubleData, indices.Int64Data, lines, length, stride, top, t.largest, t.sorted)
+	case tensor.Int32:
+		topK(input.Int32Data, values.Int32Data, indices.Int64Data, lines, length, stride, top, t.largest, t.sorted)
+	case tensor.Int64:
+		topK(input.Int64Data, values.Int64Data, indices.Int64Data, lines, length, stride, top, t.largest, t.sorted)
+	default:
+		return fmt.Errorf("topk: input datatype (%v) is invalid", input.DType)
+	}
+	return nil
+}
+
+func topK[T tensor.Numeric](in, values []T, indices []int64, lines, length, stride, top int, largest, sorted bool) {
+	order := make([]int, length)
+	// A line starts at the beginning of a row, or at the top of a column
+	line_start := length
+	if stride > 1 {
+		line_start = 1
+	}
+	out_start := top
+	if stride > 1 {
+		out_start = 1
+	}
+	for line := range lines {
+		start := line * line_start
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(i, j int) int {
+			if largest {
+				return cmp.Compare(in[start+j*stride], in[start+i*stride])
+			}
+			return cmp.Compare(in[start+i*stride], in[start+j*stride])
+		})
+		best := order[:top]
+		if !sorted {
+			slices.Sort(best)
+		}
+		out := line * out_start
+		for i, index := range best {
+			values[out+i*stride] = in[start+index*stride]
+			indices[out+i*stride] = int64(index)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package ops
+
+import (
+	"slices"
+
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// DecisionStep is a branch a sample went through
+type DecisionStep struct {
+	NodeID    int64
+	Feature   int64
+	Mode      string
+	Threshold float64
+	Value     float64 // value of the feature in the sample, NaN when it is missing
+	Condition bool    // whether the true child was taken
+}
+
+// DecisionPath is the way of a sample through a tree. Node ids are the ones of the nodes_nodeids
+// attribute, for the opset 5 TreeEnsemble op they are numbered depth first from 0 in each tree.
+type DecisionPath struct {
+	TreeID int64
+	LeafID int64
+	Steps  []DecisionStep
+}
+
+// TreeModel is implemented by the ops evaluating a TreeEnsemble. It returns the ensemble and
+// the input of the last Compute.
+type TreeModel interface {
+	Ensemble(k *kernel.Kernel) (*TreeEnsemble, *tensor.Tensor, error)
+}
+
+/*
+ * DecisionPaths returns the leaf reached by every sample in every tree, like sklearn's apply.
+ * When steps is set, the branches leading to each leaf are listed from the root, like
+ * sklearn's decision_path.
+ */
+func (t *TreeEnsemble) DecisionPaths(X *tensor.Tensor, steps bool) ([][]DecisionPath, error) {
+	leaves, err := t.LeaveIndexTrees(X)
+	if err != nil {
+		return nil, err
+	}
+	c := t.compiled
+	nSamples, nTrees := leaves.Shape[0], leaves.Shape[1]
+	nFeatures := X.Shape[len(X.Shape)-1]
+	var values *tensor.Tensor
+	if steps {
+		values, err = X.Clone()
+		if err != nil {
+			return nil, err
+		}
+		values.Cast(tensor.Double)
+	}
+
+	paths := make([][]DecisionPath, nSamples)
+	for i := range paths {
+		paths[i] = make([]DecisionPath, nTrees)
+		for j := range paths[i] {
+			leaf := int32(leaves.Int64Data[i*nTrees+j])
+			path := &paths[i][j]
+			path.TreeID = t.Atts.nodes_treeids[leaf]
+			path.LeafID = t.Atts.nodes_nodeids[leaf]
+			if !steps {
+				continue
+			}
+			for node := leaf; c.parents[node] >= 0; node = c.parents[node] {
+				parent := c.parents[node]
+				feature := t.Atts.nodes_featureids[parent]
+				path.Steps = append(path.Steps, DecisionStep{
+					NodeID:    t.Atts.nodes_nodeids[parent],
+					Feature:   feature,
+					Mode:      string(t.Atts.nodes_modes[parent]),
+					Threshold: c.thresholds[parent],
+					Value:     values.DoubleData[i*nFeatures+int(feature)],
+					Condition: c.true_parent[node],
+				})
+			}
+			slices.Reverse(path.Steps)
+		}
+	}
+	return paths, nil
+}
+
+func (t *TreeEnsembleClassifier) Ensemble(k *kernel.Kernel) (*TreeEnsemble, *tensor.Tensor, error) {
+	input, err := lastInput(k, t.input)
+	return t.tree, input, err
+}
+
+func (t *TreeEnsembleRegressor) Ensemble(k *kernel.Kernel) (*TreeEnsemble, *tensor.Tensor, error) {
+	input, err := lastInput(k, t.input)
+	return t.tree, input, err
+}
+
+func (t *UnifiedTreeEnsemble) Ensemble(k *kernel.Kernel) (*TreeEnsemble, *tensor.Tensor, error) {
+	input, err := lastInput(k, t.input)
+	return t.tree, input, err
+}
@@ -0,0 +1,73 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+/*
+ * UnifiedTreeEnsemble implements the TreeEnsemble op of ai.onnx.ml opset 5, which replaces both
+ * TreeEnsembleClassifier and TreeEnsembleRegressor. Its attributes are converted to the legacy
+ * layout when the op is initialized, so it is evaluated like a regressor with n_targets outputs.
+ */
+type UnifiedTreeEnsemble struct {
+	tree   *TreeEnsemble
+	input  int
+	output int
+}
+
+func (t *UnifiedTreeEnsemble) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	input, err := k.RegisterReader(node.Input[0])
+	if err != nil {
+		return err
+	}
+	t.input = input
+
+	t.tree = &TreeEnsemble{}
+	err = t.tree.Init(node, k.Options)
+	if err != nil {
+		return err
+	}
+	if t.tree.Atts.tree_roots == nil {
+		return fmt.Errorf("treeensemble: tree_roots attribute is required")
+	}
+	if t.tree.Atts.n_targets <= 0 {
+		return fmt.Errorf("treeensemble: n_targets must be positive, got %d", t.tree.Atts.n_targets)
+	}
+	t.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (t *UnifiedTreeEnsemble) Compute(k *kernel.Kernel) error {
+	data, err := k.Input(t.input)
+	if err != nil {
+		return err
+	}
+	input := data.Tensor
+	if input.DType != tensor.Float && input.DType != tensor.Double {
+		return fmt.Errorf("treeensemble: input datatype (%v) is invalid", input.DType)
+	}
+
+	res, err := t.tree.Scores(input, input.DType)
+	if err != nil {
+		return err
+	}
+	err = applyPostTransform(res, t.tree.Atts.post_transform)
+	if err != nil {
+		return err
+	}
+
+	output, err := k.Output(t.output, res.Shape, res.DType)
+	if err != nil {
+		return err
+	}
+	if res.DType == tensor.Double {
+		copy(output.DoubleData, res.DoubleData)
+	} else {
+		copy(output.FloatData, res.FloatData)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package ops
+
+import "fmt"
+
+type aggregateFunction uint8
+
+const (
+	aggregateSum aggregateFunction = iota
+	aggregateAverage
+	aggregateMin
+	aggregateMax
+)
+
+func parseAggregateFunction(name string) (aggregateFunction, error) {
+	switch name {
+	case "SUM", "":
+		return aggregateSum, nil
+	case "AVERAGE":
+		return aggregateAverage, nil
+	case "MIN":
+		return aggregateMin, nil
+	case "MAX":
+		return aggregateMax, nil
+	}
+	return 0, fmt.Errorf("aggregate_function=%s not supported yet", name)
+}
+
+/*
+ * accumulate adds the weights of a leaf to the scores of a sample. SUM and AVERAGE add them,
+ * AVERAGE dividing by the number of trees once every tree is added. MIN and MAX keep the smallest
+ * or largest weight, seen telling which outputs already got one so that a weight of 0 isn't
+ * mistaken for a missing one. seen is only needed by MIN and MAX.
+ */
+func (c *compiledTrees) accumulate(leaf int32, row []float64, seen []bool) {
+	node := &c.nodes[leaf]
+	for j := node.leaf_start; j < node.leaf_end; j++ {
+		index := c.leaf_ids[j]
+		weight := c.leaf_weights[j]
+		switch c.aggregate {
+		case aggregateSum, aggregateAverage:
+			row[index] += weight
+		case aggregateMin:
+			if !seen[index] || weight < row[index] {
+				row[index] = weight
+			}
+			seen[index] = true
+		case aggregateMax:
+			if !seen[index] || weight > row[index] {
+				row[index] = weight
+			}
+			seen[index] = true
+		}
+	}
+}
+
+func (c *compiledTrees) seen(size int) []bool {
+	if c.aggregate == aggregateMin || c.aggregate == aggregateMax {
+		return make([]bool, size)
+	}
+	return nil
+}
+
+// Averages the accumulated scores over the trees when needed, then adds base_values. Outputs no
+// leaf contributed to keep their base value.
+func (t *TreeEnsemble) finishScores(scores []float64, nSamples int) {
+	c := t.compiled
+	if c.aggregate == aggregateAverage && len(c.roots) > 0 {
+		for i := range scores {
+			scores[i] /= float64(len(c.roots))
+		}
+	}
+	for i := 0; i < nSamples; i++ {
+		for j := 0; j < c.n_outputs && j < len(t.BaseValues); j++ {
+			scores[i*c.n_outputs+j] += t.BaseValues[j]
+		}
+	}
+}
@@ -22,10 +22,13 @@ func (t *TreeEnsembleClassifier) Init(k *kernel.Kernel, node *ir.NodeProto) erro
 	t.input = input
 
 	t.tree = &TreeEnsemble{}
-	err = t.tree.Init(node)
+	err = t.tree.Init(node, k.Options)
 	if err != nil {
 		return err
 	}
+	if t.tree.Weights == nil {
+		return fmt.Errorf("class weights are not set")
+	}
 
 	t.outputs = make([]int, len(node.Output))
 
@@ -43,8 +46,6 @@ func (t *TreeEnsembleClassifier) Compute(k *kernel.Kernel) error {
 	}
 	input := data.Tensor
 
-	leave_index := t.tree.LeaveIndexTrees(input)
-
 	len_class_label_int64s := len(t.tree.Atts.classlabels_int64s)
 	len_class_label_strings := len(t.tree.Atts.classlabels_strings)
 
@@ -54,58 +55,14 @@ func (t *TreeEnsembleClassifier) Compute(k *kernel.Kernel) error {
 	} else {
 		n_classes = len_class_label_strings
 	}
-	n_samples := leave_index.Shape[0]
-
-	res := tensor.CreateEmptyTensor([]int{n_samples, n_classes}, tensor.Float)
-	if t.tree.Atts.base_values == nil {
-		res.FloatData[0] = 0
-	} else {
-		baseValues := t.tree.Atts.base_values.FloatData
-		for i := 0; i < n_samples; i++ {
-			copy(res.FloatData[i*n_classes:(i+1)*n_classes], baseValues)
-		}
-	}
 
-	classIndex := make(map[TreeNodeKey][]int)
-	for i := 0; i < len(t.tree.Atts.class_treeids); i++ {
-		tid := t.tree.Atts.class_treeids[i]
-		nid := t.tree.Atts.class_nodeids[i]
-		key := TreeNodeKey{TreeID: tid, NodeID: nid}
-		classIndex[key] = append(classIndex[key], i)
-	}
-
-	var classWeights []float32
-	if t.tree.Atts.class_weights != nil {
-		classWeights = t.tree.Atts.class_weights.FloatData
-	} else if t.tree.Atts.class_weights_as_tensor != nil {
-		classWeights = t.tree.Atts.class_weights_as_tensor.FloatData
-	} else {
-		fmt.Errorf("class weights are not set")
+	// Scores are accumulated in float64 and written out as floats
+	res, err := t.tree.Scores(input, tensor.Double)
+	if err != nil {
+		return err
 	}
-
+	n_samples := res.Shape[0]
 	classIDs := t.tree.Atts.class_ids
-	numTrees := leave_index.Shape[1]
-
-	for i := 0; i < n_samples; i++ {
-		start := i * leave_index.Shape[1]
-		end := start + numTrees
-		indices := leave_index.Int64Data[start:end]
-		for _, idx := range indices {
-			treeID := t.tree.Atts.nodes_treeids[idx]
-			nodeID := t.tree.Atts.nodes_nodeids[idx]
-			key := TreeNodeKey{TreeID: treeID, NodeID: nodeID}
-			if its, ok := classIndex[key]; ok {
-				for _, it := range its {
-					classID := classIDs[it]
-					if int(classID) >= n_classes {
-						return fmt.Errorf("class id %d is out of bounds for class labels", classID)
-					}
-					resIdx := i*n_classes + int(classID)
-					res.FloatData[resIdx] += classWeights[it]
-				}
-			}
-		}
-	}
 
 	binary := false
 	uniqueClassIDs := make(map[int64]struct{})
@@ -115,48 +72,30 @@ func (t *TreeEnsembleClassifier) Compute(k *kernel.Kernel) error {
 	binary = len(uniqueClassIDs) == 1
 	if binary {
 		if n_classes == 1 && (len(t.tree.Atts.classlabels_int64s) == 1 || len(t.tree.Atts.classlabels_strings) == 1) {
-			newRes := tensor.CreateEmptyTensor([]int{n_samples, 2}, tensor.Float)
+			newRes := tensor.CreateEmptyTensor([]int{n_samples, 2}, tensor.Double)
 			for i := 0; i < n_samples; i++ {
-				newRes.FloatData[i*2+1] = res.FloatData[i*n_classes]
+				newRes.DoubleData[i*2+1] = res.DoubleData[i*n_classes]
 			}
-			copy(res.FloatData, newRes.FloatData)
+			copy(res.DoubleData, newRes.DoubleData)
 		} else {
 			for i := 0; i < n_samples; i++ {
-				res.FloatData[i*n_classes+1] = res.FloatData[i*n_classes]
+				res.DoubleData[i*n_classes+1] = res.DoubleData[i*n_classes]
 			}
 		}
 
 		for i := 0; i < n_samples; i++ {
 			if t.tree.Atts.post_transform == "NONE" || t.tree.Atts.post_transform == "" || t.tree.Atts.post_transform == "PROBIT" {
-				res.FloatData[i*n_classes] = 1 - res.FloatData[i*n_classes+1]
+				res.DoubleData[i*n_classes] = 1 - res.DoubleData[i*n_classes+1]
 			} else {
-				res.FloatData[i*n_classes] = -res.FloatData[i*n_classes+1]
+				res.DoubleData[i*n_classes] = -res.DoubleData[i*n_classes+1]
 			}
 		}
 
 	}
 
-	switch t.tree.Atts.post_transform {
-	case "LOGISITIC":
-		err = res.LogisticInPlace()
-		if err != nil {
-			return fmt.Errorf("error applying logistic: %v", err)
-		}
-	case "SOFTMAX":
-		err = res.SoftmaxInPlace()
-		if err != nil {
-			return fmt.Errorf("error applying softmax: %v", err)
-		}
-	case "PROBIT":
-		err = res.ProbitInPlace()
-		if err != nil {
-			return fmt.Errorf("error applying probit: %v", err)
-		}
-	case "SOFTMAX_ZERO":
-		err = res.SoftmaxZeroInPlace()
-		if err != nil {
-			return fmt.Errorf("error applying softmax_zero: %v", err)
-		}
+	err = applyPostTransform(res, t.tree.Atts.post_transform)
+	if err != nil {
+		return err
 	}
 
 	var scoresTensor *tensor.Tensor
@@ -164,14 +103,16 @@ func (t *TreeEnsembleClassifier) Compute(k *kernel.Kernel) error {
 	if err != nil {
 		return err
 	}
-	copy(scoresTensor.FloatData, res.FloatData)
+	for i := range res.DoubleData {
+		scoresTensor.FloatData[i] = float32(res.DoubleData[i])
+	}
 
 	// Determine labels == argmax
 	labels := make([]int64, n_samples)
 	for i := 0; i < n_samples; i++ {
 		start := i * n_classes
 		end := start + n_classes
-		row := res.FloatData[start:end]
+		row := res.DoubleData[start:end]
 		maxIdx := 0
 		maxVal := row[0]
 		for j := 1; j < n_classes; j++ {
@@ -0,0 +1,390 @@
+package ops
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+type nodeMode uint8
+
+const (
+	nodeLeaf nodeMode = iota
+	nodeBranchLEQ
+	nodeBranchLT
+	nodeBranchGTE
+	nodeBranchGT
+	nodeBranchEQ
+	nodeBranchNEQ
+	nodeBranchMember
+)
+
+var nodeModes = map[string]nodeMode{
+	"LEAF":          nodeLeaf,
+	"BRANCH_LEQ":    nodeBranchLEQ,
+	"BRANCH_LT":     nodeBranchLT,
+	"BRANCH_GTE":    nodeBranchGTE,
+	"BRANCH_GT":     nodeBranchGT,
+	"BRANCH_EQ":     nodeBranchEQ,
+	"BRANCH_NEQ":    nodeBranchNEQ,
+	"BRANCH_MEMBER": nodeBranchMember,
+}
+
+// Number of samples scored together, every tree is run over a batch before moving to the next one
+const treeBatchSize = 128
+
+type compiledNode struct {
+	feature      int32
+	mode         nodeMode
+	missing_true bool
+	true_child   int32
+	false_child  int32
+	leaf_start   int32 // the weights of a leaf are leaf_ids/leaf_weights[leaf_start:leaf_end]
+	leaf_end     int32
+}
+
+/*
+ * compiledTrees is the flat form of a TreeEnsemble built at Init. Nodes keep the position they
+ * have in the nodes_* attributes, children are positions in the same array and the weights of
+ * each leaf are a contiguous range, so scoring needs no map lookup or string comparison.
+ */
+type compiledTrees struct {
+	nodes        []compiledNode
+	thresholds   []float64
+	thresholds32 []float32
+	members      map[int][]float64
+	roots        []int32
+	leaf_ids     []int32
+	leaf_weights []float64
+	parents      []int32 // -1 for the roots
+	true_parent  []bool  // the node is the true child of its parent
+	n_outputs    int
+	n_features   int // the largest feature id used plus one
+	aggregate    aggregateFunction
+}
+
+func (t *TreeEnsemble) compile() error {
+	a := t.Atts
+	n := len(a.nodes_modes)
+	if len(a.nodes_treeids) != n || len(a.nodes_nodeids) != n || len(a.nodes_featureids) != n ||
+		len(a.nodes_truenodeids) != n || len(a.nodes_falsenodeids) != n {
+		return fmt.Errorf("all nodes_* attributes should have %d values", n)
+	}
+
+	c := &compiledTrees{
+		nodes:        make([]compiledNode, n),
+		thresholds:   t.Thresholds[:n],
+		thresholds32: t.Thresholds32[:n],
+		members:      t.Members,
+	}
+	aggregate, err := parseAggregateFunction(a.aggregate_function)
+	if err != nil {
+		return err
+	}
+	c.aggregate = aggregate
+
+	// Leaves either hold targets (regressors) or classes (classifiers)
+	treeids, nodeids, ids := a.target_treeids, a.target_nodeids, a.target_ids
+	c.n_outputs = int(a.n_targets)
+	if len(a.class_ids) > 0 {
+		treeids, nodeids, ids = a.class_treeids, a.class_nodeids, a.class_ids
+		c.n_outputs = max(len(a.classlabels_int64s), len(a.classlabels_strings))
+	}
+	if len(treeids) != len(ids) || len(nodeids) != len(ids) {
+		return fmt.Errorf("leaf tree ids (%d), node ids (%d) and ids (%d) should have the same length", len(treeids), len(nodeids), len(ids))
+	}
+	if len(t.Weights) < len(ids) {
+		return fmt.Errorf("weights has %d values, want %d", len(t.Weights), len(ids))
+	}
+	leafIndex := make(map[TreeNodeKey][]int)
+	for i := range ids {
+		if ids[i] < 0 || int(ids[i]) >= c.n_outputs {
+			return fmt.Errorf("target id %d is out of bounds for target labels", ids[i])
+		}
+		key := TreeNodeKey{TreeID: treeids[i], NodeID: nodeids[i]}
+		leafIndex[key] = append(leafIndex[key], i)
+	}
+
+	child := func(i int, nodeid int64) (int32, error) {
+		index, ok := t.NodeIndex[TreeNodeKey{TreeID: a.nodes_treeids[i], NodeID: nodeid}]
+		if !ok {
+			return 0, fmt.Errorf("tree %d: node %d not found", a.nodes_treeids[i], nodeid)
+		}
+		return int32(index), nil
+	}
+
+	for i := range n {
+		mode, ok := nodeModes[string(a.nodes_modes[i])]
+		if !ok {
+			return fmt.Errorf("node mode %s not supported", a.nodes_modes[i])
+		}
+		node := &c.nodes[i]
+		node.mode = mode
+		if mode == nodeLeaf {
+			node.leaf_start = int32(len(c.leaf_ids))
+			for _, it := range leafIndex[TreeNodeKey{TreeID: a.nodes_treeids[i], NodeID: a.nodes_nodeids[i]}] {
+				c.leaf_ids = append(c.leaf_ids, int32(ids[it]))
+				c.leaf_weights = append(c.leaf_weights, t.Weights[it])
+			}
+			node.leaf_end = int32(len(c.leaf_ids))
+			continue
+		}
+
+		if a.nodes_featureids[i] < 0 {
+			return fmt.Errorf("feature id %d cannot be negative", a.nodes_featureids[i])
+		}
+		node.feature = int32(a.nodes_featureids[i])
+		c.n_features = max(c.n_features, int(node.feature)+1)
+		if i < len(a.nodes_missing_value_tracks_true) {
+			node.missing_true = a.nodes_missing_value_tracks_true[i] >= 1
+		}
+		node.true_child, err = child(i, a.nodes_truenodeids[i])
+		if err != nil {
+			return err
+		}
+		node.false_child, err = child(i, a.nodes_falsenodeids[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	c.parents = make([]int32, n)
+	c.true_parent = make([]bool, n)
+	for i := range c.parents {
+		c.parents[i] = -1
+	}
+	for i := range c.nodes {
+		if c.nodes[i].mode != nodeLeaf {
+			c.parents[c.nodes[i].true_child] = int32(i)
+			c.true_parent[c.nodes[i].true_child] = true
+			c.parents[c.nodes[i].false_child] = int32(i)
+		}
+	}
+
+	c.roots = make([]int32, len(t.TreeIds))
+	for i, tid := range t.TreeIds {
+		c.roots[i] = int32(t.RootIndex[tid])
+	}
+	err = c.checkAcyclic()
+	if err != nil {
+		return err
+	}
+	t.compiled = c
+	return nil
+}
+
+// A node reachable from itself would make scoring loop forever
+func (c *compiledTrees) checkAcyclic() error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]uint8, len(c.nodes))
+	var visit func(node int32) bool
+	visit = func(node int32) bool {
+		switch state[node] {
+		case visiting:
+			return false
+		case done:
+			return true
+		}
+		state[node] = visiting
+		if c.nodes[node].mode != nodeLeaf && (!visit(c.nodes[node].true_child) || !visit(c.nodes[node].false_child)) {
+			return false
+		}
+		state[node] = done
+		return true
+	}
+	for _, root := range c.roots {
+		if !visit(root) {
+			return fmt.Errorf("tree ensemble contains a cycle")
+		}
+	}
+	return nil
+}
+
+// Returns the largest number of branches between a root and a leaf
+func (c *compiledTrees) depth() int {
+	depths := make([]int, len(c.nodes)) // of the subtree of each node plus one, 0 until it is known
+	var visit func(node int32) int
+	visit = func(node int32) int {
+		if depths[node] == 0 {
+			depths[node] = 1
+			if c.nodes[node].mode != nodeLeaf {
+				depths[node] += max(visit(c.nodes[node].true_child), visit(c.nodes[node].false_child))
+			}
+		}
+		return depths[node]
+	}
+	depth := 0
+	for _, root := range c.roots {
+		depth = max(depth, visit(root)-1)
+	}
+	return depth
+}
+
+/*
+ * descend returns the node x reaches from index after steps branches, or earlier when it reaches
+ * a leaf. A negative steps walks down to the leaf. This is the hot loop of scoring, so it is the
+ * only place the conditions of the branches are evaluated.
+ */
+func descend[T tensor.Float32_64](c *compiledTrees, x []T, thresholds []T, index int32, steps int) int32 {
+	for ; steps != 0; steps-- {
+		node := &c.nodes[index]
+		if node.mode == nodeLeaf {
+			return index
+		}
+		var r bool
+		v := x[node.feature]
+		// NaN is the only value not equal to itself
+		if v != v {
+			r = node.missing_true
+		} else {
+			th := thresholds[index]
+			switch node.mode {
+			case nodeBranchLEQ:
+				r = v <= th
+			case nodeBranchLT:
+				r = v < th
+			case nodeBranchGTE:
+				r = v >= th
+			case nodeBranchGT:
+				r = v > th
+			case nodeBranchEQ:
+				r = v == th
+			case nodeBranchNEQ:
+				r = v != th
+			case nodeBranchMember:
+				r = slices.Contains(c.members[int(index)], float64(v))
+			}
+		}
+		if r {
+			index = node.true_child
+		} else {
+			index = node.false_child
+		}
+	}
+	return index
+}
+
+// Returns the position of the leaf reached by x from root
+func traverseTree[T tensor.Float32_64](c *compiledTrees, x []T, thresholds []T, root int32) int32 {
+	return descend(c, x, thresholds, root, -1)
+}
+
+// Accumulates the leaf weights of every tree into scores, tree-major over batches of samples
+func scoreTrees[T tensor.Float32_64](c *compiledTrees, X []T, thresholds []T, nSamples, nFeatures int, scores []float64) {
+	seen := c.seen(len(scores))
+	for start := 0; start < nSamples; start += treeBatchSize {
+		end := min(start+treeBatchSize, nSamples)
+		for _, root := range c.roots {
+			for i := start; i < end; i++ {
+				leaf := traverseTree(c, X[i*nFeatures:(i+1)*nFeatures], thresholds, root)
+				row := scores[i*c.n_outputs : (i+1)*c.n_outputs]
+				if seen != nil {
+					c.accumulate(leaf, row, seen[i*c.n_outputs:(i+1)*c.n_outputs])
+				} else {
+					c.accumulate(leaf, row, nil)
+				}
+			}
+		}
+	}
+}
+
+// Sets the leaves of the trees firstTree to lastTree (excluded) in the [samples, trees] leaves
+func leaveIndexTrees[T tensor.Float32_64](c *compiledTrees, X []T, thresholds []T, nSamples, nFeatures, firstTree, lastTree int, leaves []int64) {
+	nTrees := len(c.roots)
+	for start := 0; start < nSamples; start += treeBatchSize {
+		end := min(start+treeBatchSize, nSamples)
+		for j := firstTree; j < lastTree; j++ {
+			root := c.roots[j]
+			for i := start; i < end; i++ {
+				leaves[i*nTrees+j] = int64(traverseTree(c, X[i*nFeatures:(i+1)*nFeatures], thresholds, root))
+			}
+		}
+	}
+}
+
+// Returns the samples of X in the precision they are compared in: float64 for double inputs or
+// when the thresholds are doubles, float32 otherwise.
+func (t *TreeEnsemble) features(X *tensor.Tensor) (int, int, []float32, []float64, error) {
+	shape := X.Shape
+	if len(shape) == 1 {
+		shape = []int{1, shape[0]}
+	}
+	nSamples, nFeatures := shape[0], shape[1]
+	if nFeatures < t.compiled.n_features {
+		return 0, 0, nil, nil, fmt.Errorf("input has %d features, the trees use %d", nFeatures, t.compiled.n_features)
+	}
+
+	double := t.Double || X.DType == tensor.Double
+	switch X.DType {
+	case tensor.Double:
+		return nSamples, nFeatures, nil, X.DoubleData, nil
+	case tensor.Float:
+		if !double {
+			return nSamples, nFeatures, X.FloatData, nil, nil
+		}
+	case tensor.Int32, tensor.Int64:
+	default:
+		return 0, 0, nil, nil, fmt.Errorf("input datatype (%v) is invalid", X.DType)
+	}
+
+	X, err := X.Clone()
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	if double {
+		X.Cast(tensor.Double)
+		return nSamples, nFeatures, nil, X.DoubleData, nil
+	}
+	X.Cast(tensor.Float)
+	return nSamples, nFeatures, X.FloatData, nil, nil
+}
+
+// Returns the position of the leaf reached in every tree by every sample as a [samples, trees] tensor
+func (t *TreeEnsemble) LeaveIndexTrees(X *tensor.Tensor) (*tensor.Tensor, error) {
+	nSamples, nFeatures, x32, x64, err := t.features(X)
+	if err != nil {
+		return nil, err
+	}
+	c := t.compiled
+	output := tensor.CreateEmptyTensor([]int{nSamples, len(c.roots)}, tensor.Int64)
+	if x64 != nil {
+		runTrees(t, x64, c.thresholds, t.quickThresholds(), nSamples, nFeatures, nil, output.Int64Data)
+	} else {
+		runTrees(t, x32, c.thresholds32, t.quickThresholds32(), nSamples, nFeatures, nil, output.Int64Data)
+	}
+	return output, nil
+}
+
+// Aggregates the weights of the leaves reached by each sample with the aggregate_function, then
+// adds base_values. Scores are accumulated in float64 and returned as a [samples, outputs]
+// tensor of type dtype, where outputs is n_targets or the number of classes.
+func (t *TreeEnsemble) Scores(X *tensor.Tensor, dtype tensor.DataType) (*tensor.Tensor, error) {
+	nSamples, nFeatures, x32, x64, err := t.features(X)
+	if err != nil {
+		return nil, err
+	}
+	c := t.compiled
+	scores := make([]float64, nSamples*c.n_outputs)
+	if x64 != nil {
+		runTrees(t, x64, c.thresholds, t.quickThresholds(), nSamples, nFeatures, scores, nil)
+	} else {
+		runTrees(t, x32, c.thresholds32, t.quickThresholds32(), nSamples, nFeatures, scores, nil)
+	}
+
+	t.finishScores(scores, nSamples)
+
+	res := tensor.CreateEmptyTensor([]int{nSamples, c.n_outputs}, dtype)
+	if dtype == tensor.Double {
+		copy(res.DoubleData, scores)
+	} else {
+		for i := range scores {
+			res.FloatData[i] = float32(scores[i])
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,183 @@
+package ops_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/ops"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+var allModes = []string{"BRANCH_LEQ", "BRANCH_LT", "BRANCH_GTE", "BRANCH_GT", "BRANCH_EQ", "BRANCH_NEQ"}
+
+// randomForest builds a TreeEnsembleRegressor node of complete trees with random splits
+func randomForest(r *rand.Rand, nTrees, depth, nFeatures int, modes []string) *ir.NodeProto {
+	var treeids, nodeids, featureids, truenodeids, falsenodeids, missing []int64
+	var targetTreeids, targetNodeids, targetIds []int64
+	var values, weights []float32
+	var nodeModes [][]byte
+	nNodes := 1<<(depth+1) - 1
+	for tree := range nTrees {
+		for node := range nNodes {
+			treeids = append(treeids, int64(tree))
+			nodeids = append(nodeids, int64(node))
+			values = append(values, float32(r.Intn(10)))
+			if node >= nNodes/2 {
+				nodeModes = append(nodeModes, []byte("LEAF"))
+				featureids = append(featureids, 0)
+				truenodeids = append(truenodeids, 0)
+				falsenodeids = append(falsenodeids, 0)
+				missing = append(missing, 0)
+				targetTreeids = append(targetTreeids, int64(tree))
+				targetNodeids = append(targetNodeids, int64(node))
+				targetIds = append(targetIds, 0)
+				weights = append(weights, r.Float32())
+				continue
+			}
+			nodeModes = append(nodeModes, []byte(modes[r.Intn(len(modes))]))
+			featureids = append(featureids, int64(r.Intn(nFeatures)))
+			truenodeids = append(truenodeids, int64(2*node+1))
+			falsenodeids = append(falsenodeids, int64(2*node+2))
+			missing = append(missing, int64(r.Intn(2)))
+		}
+	}
+	return &ir.NodeProto{
+		OpType: "TreeEnsembleRegressor",
+		Attribute: []*ir.AttributeProto{
+			{Name: "n_targets", I: 1},
+			{Name: "nodes_treeids", Ints: treeids},
+			{Name: "nodes_nodeids", Ints: nodeids},
+			{Name: "nodes_featureids", Ints: featureids},
+			{Name: "nodes_modes", Strings: nodeModes},
+			{Name: "nodes_values", Floats: values},
+			{Name: "nodes_truenodeids", Ints: truenodeids},
+			{Name: "nodes_falsenodeids", Ints: falsenodeids},
+			{Name: "nodes_missing_value_tracks_true", Ints: missing},
+			{Name: "target_treeids", Ints: targetTreeids},
+			{Name: "target_nodeids", Ints: targetNodeids},
+			{Name: "target_ids", Ints: targetIds},
+			{Name: "target_weights", Floats: weights},
+		},
+	}
+}
+
+// Integer features hit the equality splits, and some of them are missing
+func randomSamples(r *rand.Rand, nSamples, nFeatures int) *tensor.Tensor {
+	X := tensor.CreateEmptyTensor([]int{nSamples, nFeatures}, tensor.Float)
+	for i := range X.FloatData {
+		X.FloatData[i] = float32(r.Intn(10))
+		if r.Intn(20) == 0 {
+			X.FloatData[i] = float32(math.NaN())
+		}
+	}
+	return X
+}
+
+// Walks a tree of node from its attributes alone and returns the position of the leaf x reaches
+func leafPosition(node *ir.NodeProto, treeid int64, x []float32) int {
+	attributes := make(map[string]*ir.AttributeProto)
+	for _, attr := range node.Attribute {
+		attributes[attr.Name] = attr
+	}
+	treeids := attributes["nodes_treeids"].Ints
+	nodeids := attributes["nodes_nodeids"].Ints
+	position := func(nodeid int64) int {
+		for i := range treeids {
+			if treeids[i] == treeid && nodeids[i] == nodeid {
+				return i
+			}
+		}
+		panic("node not found")
+	}
+
+	index := position(0)
+	for {
+		mode := string(attributes["nodes_modes"].Strings[index])
+		if mode == "LEAF" {
+			return index
+		}
+		v := x[attributes["nodes_featureids"].Ints[index]]
+		th := attributes["nodes_values"].Floats[index]
+		var r bool
+		switch {
+		case math.IsNaN(float64(v)):
+			r = attributes["nodes_missing_value_tracks_true"].Ints[index] == 1
+		case mode == "BRANCH_LEQ":
+			r = v <= th
+		case mode == "BRANCH_LT":
+			r = v < th
+		case mode == "BRANCH_GTE":
+			r = v >= th
+		case mode == "BRANCH_GT":
+			r = v > th
+		case mode == "BRANCH_EQ":
+			r = v == th
+		case mode == "BRANCH_NEQ":
+			r = v != th
+		}
+		if r {
+			index = position(attributes["nodes_truenodeids"].Ints[index])
+		} else {
+			index = position(attributes["nodes_falsenodeids"].Ints[index])
+		}
+	}
+}
+
+func TestTreeEnsembleCompiledLeaves(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	nTrees, nSamples, nFeatures := 50, 300, 6
+	node := randomForest(r, nTrees, 5, nFeatures, allModes)
+	tree := &ops.TreeEnsemble{}
+	err := tree.Init(node, kernel.DefaultOptions())
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	X := randomSamples(r, nSamples, nFeatures)
+
+	leaves, err := tree.LeaveIndexTrees(X)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	for i := range nSamples {
+		row := X.FloatData[i*nFeatures : (i+1)*nFeatures]
+		for j, treeid := range tree.TreeIds {
+			want := leafPosition(node, treeid, row)
+			if got := leaves.Int64Data[i*nTrees+j]; got != int64(want) {
+				t.Fatalf("sample %d, tree %d: expected leaf %d, got %d", i, treeid, want, got)
+			}
+		}
+	}
+}
+
+func TestTreeEnsembleMissingFeature(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	tree := &ops.TreeEnsemble{}
+	err := tree.Init(randomForest(r, 3, 3, 6, allModes), kernel.DefaultOptions())
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	_, err = tree.Scores(randomSamples(r, 2, 2), tensor.Float)
+	if err == nil {
+		t.Fatalf("an input with less features than the trees use should be rejected")
+	}
+}
+
+func BenchmarkTreeEnsembleScores(b *testing.B) {
+	r := rand.New(rand.NewSource(7))
+	tree := &ops.TreeEnsemble{}
+	err := tree.Init(randomForest(r, 500, 6, 20, allModes), kernel.DefaultOptions())
+	if err != nil {
+		b.Fatalf("error shouldn't exist: %v", err)
+	}
+	X := randomSamples(r, 1000, 20)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = tree.Scores(X, tensor.Float)
+		if err != nil {
+			b.Fatalf("error shouldn't exist: %v", err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
 	"github.com/systemEng-Learning/go-ml-deployment/tensor"
 )
 
@@ -43,6 +44,65 @@ type TreeEnsembleAttributes struct {
 	target_weights_as_tensor        *tensor.Tensor
 	n_targets                       int64
 	aggregate_function              string
+	tree_roots                      []int64
+	nodes_trueleafs                 []int64
+	nodes_falseleafs                []int64
+	nodes_splits                    *tensor.Tensor
+	membership_values               *tensor.Tensor
+	leaf_targetids                  []int64
+	leaf_weights                    *tensor.Tensor
+}
+
+// Node modes and post transforms of the opset 5 TreeEnsemble op are enums instead of strings
+var treeNodeModes = []string{"BRANCH_LEQ", "BRANCH_LT", "BRANCH_GTE", "BRANCH_GT", "BRANCH_EQ", "BRANCH_NEQ", "BRANCH_MEMBER"}
+var treeAggregateFunctions = []string{"AVERAGE", "SUM", "MIN", "MAX"}
+var treePostTransforms = []string{"NONE", "SOFTMAX", "LOGISTIC", "SOFTMAX_ZERO", "PROBIT"}
+
+func enumName(names []string, value int64, attribute string) (string, error) {
+	if value < 0 || value >= int64(len(names)) {
+		return "", fmt.Errorf("%s value %d is not supported", attribute, value)
+	}
+	return names[value], nil
+}
+
+// Reads an integer tensor such as the uint8 nodes_modes, which is stored in int32_data or raw_data
+func tensorProtoInts(tp *ir.TensorProto) []int64 {
+	if len(tp.RawData) > 0 && tp.DataType == int32(ir.TensorProto_UINT8) {
+		values := make([]int64, len(tp.RawData))
+		for i := range tp.RawData {
+			values[i] = int64(tp.RawData[i])
+		}
+		return values
+	}
+	values := make([]int64, len(tp.Int32Data))
+	for i := range tp.Int32Data {
+		values[i] = int64(tp.Int32Data[i])
+	}
+	return values
+}
+
+// Returns the values of a float or double tensor attribute as float64
+func tensorDoubles(t *tensor.Tensor) []float64 {
+	if t.DType == tensor.Double {
+		return t.DoubleData
+	}
+	values := make([]float64, len(t.FloatData))
+	for i := range t.FloatData {
+		values[i] = float64(t.FloatData[i])
+	}
+	return values
+}
+
+// Creates a 1-D attribute tensor of the given type
+func valuesTensor(values []float64, dtype tensor.DataType) *tensor.Tensor {
+	if dtype == tensor.Double {
+		return &tensor.Tensor{Shape: []int{len(values)}, DType: tensor.Double, DoubleData: values}
+	}
+	floats := make([]float32, len(values))
+	for i := range values {
+		floats[i] = float32(values[i])
+	}
+	return tensor.Create1DFloatTensor(floats)
 }
 
 func removeDuplicatesAndSort(input []int64) []int64 {
@@ -67,13 +127,22 @@ type TreeNodeKey struct {
 }
 
 type TreeEnsemble struct {
-	Atts      *TreeEnsembleAttributes
-	TreeIds   []int64
-	RootIndex map[int64]int
-	NodeIndex map[TreeNodeKey]int
+	Atts         *TreeEnsembleAttributes
+	TreeIds      []int64
+	RootIndex    map[int64]int
+	NodeIndex    map[TreeNodeKey]int
+	Members      map[int][]float64 // values of each BRANCH_MEMBER node
+	Thresholds   []float64
+	Thresholds32 []float32
+	Weights      []float64 // target or class weights
+	BaseValues   []float64
+	Double       bool // thresholds were given as doubles, so float inputs are compared in float64
+	compiled     *compiledTrees
+	quickScorer  *quickScorer
+	Workers      int // goroutines used for scoring, the TreeWorkers option until it is changed
 }
 
-func (t *TreeEnsemble) Init(node *ir.NodeProto) error {
+func (t *TreeEnsemble) Init(node *ir.NodeProto, options kernel.Options) error {
 	t.Atts = &TreeEnsembleAttributes{}
 
 	for _, attr := range node.Attribute {
@@ -109,6 +178,14 @@ func (t *TreeEnsemble) Init(node *ir.NodeProto) error {
 			}
 			t.Atts.nodes_values_as_tensor = nodes_tensor
 		case "nodes_hitrates":
+			if attr.T != nil {
+				nodes_hitrates_tensor, err := tensor.FromTensorProto(attr.T)
+				if err != nil {
+					return fmt.Errorf("failed to create tensor from nodes_hitrates: %v", err)
+				}
+				t.Atts.nodes_hitrates_as_tensor = nodes_hitrates_tensor
+				continue
+			}
 			t.Atts.nodes_hitrates = &tensor.Tensor{
 				Shape:     []int{len(attr.Floats)},
 				DType:     tensor.Float,
@@ -121,6 +198,18 @@ func (t *TreeEnsemble) Init(node *ir.NodeProto) error {
 			}
 			t.Atts.nodes_hitrates_as_tensor = nodes_hitrates_tensor
 		case "nodes_modes":
+			if attr.T != nil {
+				modes := tensorProtoInts(attr.T)
+				t.Atts.nodes_modes = make([][]byte, len(modes))
+				for i := range modes {
+					mode, err := enumName(treeNodeModes, modes[i], "nodes_modes")
+					if err != nil {
+						return err
+					}
+					t.Atts.nodes_modes[i] = []byte(mode)
+				}
+				continue
+			}
 			t.Atts.nodes_modes = attr.Strings
 		case "nodes_truenodeids":
 			t.Atts.nodes_truenodeids = attr.Ints
@@ -151,6 +240,14 @@ func (t *TreeEnsemble) Init(node *ir.NodeProto) error {
 		case "classlabels_int64s":
 			t.Atts.classlabels_int64s = attr.Ints
 		case "post_transform":
+			if len(attr.S) == 0 {
+				post_transform, err := enumName(treePostTransforms, attr.I, "post_transform")
+				if err != nil {
+					return err
+				}
+				t.Atts.post_transform = post_transform
+				continue
+			}
 			t.Atts.post_transform = string(attr.S)
 		case "target_treeids":
 			t.Atts.target_treeids = attr.Ints
@@ -173,17 +270,63 @@ func (t *TreeEnsemble) Init(node *ir.NodeProto) error {
 		case "n_targets":
 			t.Atts.n_targets = attr.I
 		case "aggregate_function":
+			if len(attr.S) == 0 {
+				aggregate_function, err := enumName(treeAggregateFunctions, attr.I, "aggregate_function")
+				if err != nil {
+					return err
+				}
+				t.Atts.aggregate_function = aggregate_function
+				continue
+			}
 			t.Atts.aggregate_function = string(attr.S)
+		case "tree_roots":
+			t.Atts.tree_roots = attr.Ints
+		case "nodes_trueleafs":
+			t.Atts.nodes_trueleafs = attr.Ints
+		case "nodes_falseleafs":
+			t.Atts.nodes_falseleafs = attr.Ints
+		case "nodes_splits":
+			nodes_splits_tensor, err := tensor.FromTensorProto(attr.T)
+			if err != nil {
+				return fmt.Errorf("failed to create tensor from nodes_splits: %v", err)
+			}
+			t.Atts.nodes_splits = nodes_splits_tensor
+		case "membership_values":
+			membership_tensor, err := tensor.FromTensorProto(attr.T)
+			if err != nil {
+				return fmt.Errorf("failed to create tensor from membership_values: %v", err)
+			}
+			t.Atts.membership_values = membership_tensor
+		case "leaf_targetids":
+			t.Atts.leaf_targetids = attr.Ints
+		case "leaf_weights":
+			leaf_weights_tensor, err := tensor.FromTensorProto(attr.T)
+			if err != nil {
+				return fmt.Errorf("failed to create tensor from leaf_weights: %v", err)
+			}
+			t.Atts.leaf_weights = leaf_weights_tensor
 		default:
 			return fmt.Errorf("unsupported attribute: %s", attr.Name)
 		}
 	}
 
+	if t.Atts.tree_roots != nil {
+		err := t.convertFromV5()
+		if err != nil {
+			return err
+		}
+	}
+
+	err := t.resolveValues()
+	if err != nil {
+		return err
+	}
+
 	t.TreeIds = removeDuplicatesAndSort(t.Atts.nodes_treeids)
 
 	t.RootIndex = make(map[int64]int)
 	for _, tid := range t.TreeIds {
-		t.RootIndex[tid] = len(t.TreeIds)
+		t.RootIndex[tid] = len(t.Atts.nodes_treeids)
 	}
 
 	for index, tids := range t.Atts.nodes_treeids {
@@ -199,95 +342,221 @@ func (t *TreeEnsemble) Init(node *ir.NodeProto) error {
 		t.NodeIndex[key] = i
 	}
 
-	return nil
+	err = t.compile()
+	if err != nil {
+		return err
+	}
+	t.Workers = options.TreeWorkers
+	return t.SetStrategy(options.TreeStrategy)
 }
 
-func (t *TreeEnsemble) String() string {
-	var sb strings.Builder
-	sb.WriteString("TreeEnsemble:\n")
-	sb.WriteString(fmt.Sprintf("TreeIds: %v\n", t.TreeIds))
-	sb.WriteString(fmt.Sprintf("RootIndex: %v\n", t.RootIndex))
-	sb.WriteString(fmt.Sprintf("Attributes: %v\n", t.Atts))
-	return sb.String()
-}
+/*
+ * The opset 5 TreeEnsemble op stores leaves in their own arrays and references children by their
+ * position in the node (or leaf) arrays. convertFromV5 rewrites it into the per-node layout of
+ * TreeEnsembleClassifier/Regressor so that all three ops share the same traversal: every tree is
+ * walked from its root, and each branch or leaf reached becomes a node with a per-tree id. Leaves
+ * are written as LEAF nodes with a matching target_* entry.
+ */
+func (t *TreeEnsemble) convertFromV5() error {
+	a := t.Atts
+	n := len(a.nodes_featureids)
+	if a.nodes_splits == nil || a.leaf_weights == nil {
+		return fmt.Errorf("treeensemble: nodes_splits and leaf_weights are required")
+	}
+	splits := tensorDoubles(a.nodes_splits)
+	weights := tensorDoubles(a.leaf_weights)
+	if len(a.nodes_modes) != n || len(splits) != n || len(a.nodes_truenodeids) != n || len(a.nodes_falsenodeids) != n ||
+		len(a.nodes_trueleafs) != n || len(a.nodes_falseleafs) != n {
+		return fmt.Errorf("treeensemble: all nodes_* attributes should have %d values", n)
+	}
+	if len(a.nodes_missing_value_tracks_true) > 0 && len(a.nodes_missing_value_tracks_true) != n {
+		return fmt.Errorf("treeensemble: nodes_missing_value_tracks_true should have %d values", n)
+	}
+	if len(a.leaf_targetids) != len(weights) {
+		return fmt.Errorf("treeensemble: leaf_targetids length (%d) != leaf_weights length (%d)", len(a.leaf_targetids), len(weights))
+	}
+	var hitrates []float64
+	if a.nodes_hitrates_as_tensor != nil {
+		hitrates = tensorDoubles(a.nodes_hitrates_as_tensor)
+	}
+
+	// membership_values holds the sets of the BRANCH_MEMBER nodes in node order, separated by NaN
+	members := make(map[int64][]float64)
+	var values []float64
+	if a.membership_values != nil {
+		values = tensorDoubles(a.membership_values)
+	}
+	for i := range n {
+		if string(a.nodes_modes[i]) != "BRANCH_MEMBER" {
+			continue
+		}
+		set := []float64{}
+		for len(values) > 0 && !math.IsNaN(values[0]) {
+			set = append(set, values[0])
+			values = values[1:]
+		}
+		if len(values) > 0 {
+			values = values[1:]
+		}
+		members[int64(i)] = set
+	}
+
+	converted := &TreeEnsembleAttributes{
+		n_targets:          a.n_targets,
+		aggregate_function: a.aggregate_function,
+		post_transform:     a.post_transform,
+		tree_roots:         a.tree_roots,
+	}
+	var nodes_values, nodes_hitrates, target_weights []float64
+	t.Members = make(map[int][]float64)
 
-func (t *TreeEnsemble) LeafIndexTree(X []float32, treeid int64) int {
-	// compute the leaf index for one tree
-	index := t.RootIndex[treeid]
-	for string(t.Atts.nodes_modes[index]) != "LEAF" {
-		var r bool
-
-		x := X[t.Atts.nodes_featureids[index]]
-		if math.IsNaN(float64(x)) {
-
-			r = t.Atts.nodes_missing_value_tracks_true[index] >= 1
-
-		} else {
-			rules := t.Atts.nodes_modes[index]
-
-			th := t.Atts.nodes_values.FloatData[index]
-			switch string(rules) {
-			case "BRANCH_LEQ":
-				r = x <= th
-			case "BRANCH_LT":
-				r = x < th
-			case "BRANCH_EQ":
-				r = x == th
-			case "BRANCH_NEQ":
-				r = x != th
-			case "BRANCH_GT":
-				r = x > th
-			case "BRANCH_GTE":
-				r = x >= th
-			default:
-				return -1
+	for treeid, root := range a.tree_roots {
+		tid := int64(treeid)
+		nodeid := int64(0)
+		var visit func(index int64, leaf bool, depth int) (int64, error)
+		visit = func(index int64, leaf bool, depth int) (int64, error) {
+			if depth > n {
+				return 0, fmt.Errorf("treeensemble: tree %d contains a cycle", treeid)
+			}
+			if (leaf && (index < 0 || index >= int64(len(weights)))) || (!leaf && (index < 0 || index >= int64(n))) {
+				return 0, fmt.Errorf("treeensemble: tree %d references an invalid node %d", treeid, index)
+			}
+			id := nodeid
+			nodeid++
+			pos := len(converted.nodes_nodeids)
+			converted.nodes_treeids = append(converted.nodes_treeids, tid)
+			converted.nodes_nodeids = append(converted.nodes_nodeids, id)
+			converted.nodes_truenodeids = append(converted.nodes_truenodeids, 0)
+			converted.nodes_falsenodeids = append(converted.nodes_falsenodeids, 0)
+			if leaf {
+				converted.nodes_featureids = append(converted.nodes_featureids, 0)
+				converted.nodes_modes = append(converted.nodes_modes, []byte("LEAF"))
+				converted.nodes_missing_value_tracks_true = append(converted.nodes_missing_value_tracks_true, 0)
+				nodes_values = append(nodes_values, 0)
+				nodes_hitrates = append(nodes_hitrates, 1)
+				converted.target_treeids = append(converted.target_treeids, tid)
+				converted.target_nodeids = append(converted.target_nodeids, id)
+				converted.target_ids = append(converted.target_ids, a.leaf_targetids[index])
+				target_weights = append(target_weights, weights[index])
+				return id, nil
 			}
 
-		}
+			converted.nodes_featureids = append(converted.nodes_featureids, a.nodes_featureids[index])
+			converted.nodes_modes = append(converted.nodes_modes, a.nodes_modes[index])
+			missing := int64(0)
+			if len(a.nodes_missing_value_tracks_true) > 0 {
+				missing = a.nodes_missing_value_tracks_true[index]
+			}
+			converted.nodes_missing_value_tracks_true = append(converted.nodes_missing_value_tracks_true, missing)
+			nodes_values = append(nodes_values, splits[index])
+			hitrate := float64(1)
+			if len(hitrates) > 0 {
+				hitrate = hitrates[index]
+			}
+			nodes_hitrates = append(nodes_hitrates, hitrate)
+			if set, ok := members[index]; ok {
+				t.Members[pos] = set
+			}
 
-		var nid int64
-		if r {
-			nid = t.Atts.nodes_truenodeids[index]
-		} else {
-			nid = t.Atts.nodes_falsenodeids[index]
+			trueid, err := visit(a.nodes_truenodeids[index], a.nodes_trueleafs[index] != 0, depth+1)
+			if err != nil {
+				return 0, err
+			}
+			falseid, err := visit(a.nodes_falsenodeids[index], a.nodes_falseleafs[index] != 0, depth+1)
+			if err != nil {
+				return 0, err
+			}
+			converted.nodes_truenodeids[pos] = trueid
+			converted.nodes_falsenodeids[pos] = falseid
+			return id, nil
+		}
+		if _, err := visit(root, false, 0); err != nil {
+			return err
 		}
-		index = t.NodeIndex[TreeNodeKey{TreeID: treeid, NodeID: nid}]
-
 	}
-	return index
+
+	// Thresholds and weights keep the precision of nodes_splits and leaf_weights
+	converted.nodes_values_as_tensor = valuesTensor(nodes_values, a.nodes_splits.DType)
+	converted.nodes_hitrates = valuesTensor(nodes_hitrates, tensor.Float)
+	converted.target_weights_as_tensor = valuesTensor(target_weights, a.leaf_weights.DType)
+	t.Atts = converted
+	return nil
 }
 
-func (t *TreeEnsemble) LeaveIndexTrees(X *tensor.Tensor) *tensor.Tensor {
-	shape := X.Shape
-	if len(shape) == 1 {
-		shape = []int{1, shape[0]}
+// Thresholds, weights and base values can be set as floats or as tensors, the *_as_tensor
+// attributes take priority. They are all kept as float64, with a float32 copy of the thresholds
+// so that float inputs are compared in float32 when the thresholds are floats.
+func (t *TreeEnsemble) resolveValues() error {
+	a := t.Atts
+	thresholds := a.nodes_values
+	if a.nodes_values_as_tensor != nil {
+		thresholds = a.nodes_values_as_tensor
 	}
-	nSamples := shape[0]
-	nFeatures := shape[1]
-	cols := 0
-	outputs := []int64{}
-	for i := 0; i < nSamples; i++ {
-		startIdx := i * nFeatures
-		endIdx := startIdx + nFeatures
-		rowData := X.FloatData[startIdx:endIdx]
-		leaves := []int64{}
-		for _, treeid := range t.TreeIds {
-			o := t.LeafIndexTree(rowData, treeid)
-			leaves = append(leaves, int64(o))
+	if thresholds != nil {
+		if thresholds.DType != tensor.Float && thresholds.DType != tensor.Double {
+			return fmt.Errorf("nodes_values should be float or double, got %v", thresholds.DType)
 		}
+		t.Thresholds = tensorDoubles(thresholds)
+		t.Double = thresholds.DType == tensor.Double
+	}
+	if len(t.Thresholds) < len(a.nodes_modes) {
+		return fmt.Errorf("nodes_values has %d values, want %d", len(t.Thresholds), len(a.nodes_modes))
+	}
+	t.Thresholds32 = make([]float32, len(t.Thresholds))
+	for i := range t.Thresholds {
+		t.Thresholds32[i] = float32(t.Thresholds[i])
+	}
 
-		if cols == 0 {
-			cols = len(leaves)
+	for _, weights := range []*tensor.Tensor{a.target_weights_as_tensor, a.target_weights, a.class_weights_as_tensor, a.class_weights} {
+		if weights != nil {
+			t.Weights = tensorDoubles(weights)
+			break
 		}
-		cols = len(leaves)
-
-		outputs = append(outputs, leaves...)
 	}
+	if a.base_values_as_tensor != nil {
+		t.BaseValues = tensorDoubles(a.base_values_as_tensor)
+	} else if a.base_values != nil {
+		t.BaseValues = tensorDoubles(a.base_values)
+	}
+	return nil
+}
+
+func (t *TreeEnsemble) String() string {
+	var sb strings.Builder
+	sb.WriteString("TreeEnsemble:\n")
+	sb.WriteString(fmt.Sprintf("TreeIds: %v\n", t.TreeIds))
+	sb.WriteString(fmt.Sprintf("RootIndex: %v\n", t.RootIndex))
+	sb.WriteString(fmt.Sprintf("Attributes: %v\n", t.Atts))
+	return sb.String()
+}
 
-	tensor_output := &tensor.Tensor{
-		Shape:     []int{nSamples, cols},
-		DType:     tensor.Int64,
-		Int64Data: outputs,
+func applyPostTransform(res *tensor.Tensor, post_transform string) error {
+	var err error
+	switch post_transform {
+	case "NONE", "":
+		// No transformation needed
+	case "LOGISTIC":
+		err = res.LogisticInPlace()
+		if err != nil {
+			return fmt.Errorf("error applying logistic: %v", err)
+		}
+	case "SOFTMAX":
+		err = res.SoftmaxInPlace()
+		if err != nil {
+			return fmt.Errorf("error applying softmax: %v", err)
+		}
+	case "PROBIT":
+		err = res.ProbitInPlace()
+		if err != nil {
+			return fmt.Errorf("error applying probit: %v", err)
+		}
+	case "SOFTMAX_ZERO":
+		err = res.SoftmaxZeroInPlace()
+		if err != nil {
+			return fmt.Errorf("error applying softmax_zero: %v", err)
+		}
+	default:
+		return fmt.Errorf("post_transform=%s not implemented", post_transform)
 	}
-	return tensor_output
+	return nil
 }
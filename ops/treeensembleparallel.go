@@ -0,0 +1,91 @@
+package ops
+
+import (
+	"sync"
+
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Smallest number of trees worth giving to a worker
+const treeChunkSize = 32
+
+// Splits [0, n) into at most workers chunks of at least minSize items and runs fn on each of them
+// in its own goroutine
+func parallelChunks(n, workers, minSize int, fn func(start, end int)) {
+	chunks := min(workers, n/minSize)
+	if chunks <= 1 {
+		fn(0, n)
+		return
+	}
+	size := (n + chunks - 1) / chunks
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += size {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(start, min(start+size, n))
+		}()
+	}
+	wg.Wait()
+}
+
+func (t *TreeEnsemble) quickThresholds() [][]float64 {
+	if t.quickScorer == nil {
+		return nil
+	}
+	return t.quickScorer.thresholds
+}
+
+func (t *TreeEnsemble) quickThresholds32() [][]float32 {
+	if t.quickScorer == nil {
+		return nil
+	}
+	return t.quickScorer.thresholds32
+}
+
+/*
+ * runTrees fills either scores or leaves. Large batches are split by samples, which don't depend
+ * on each other. Small batches are split by trees: the leaves are found concurrently, then added
+ * to the scores in tree order. Either way every score is summed in the same order as on a single
+ * goroutine, so the results don't depend on the number of workers.
+ */
+func runTrees[T tensor.Float32_64](t *TreeEnsemble, X []T, thresholds []T, quick [][]T, nSamples, nFeatures int, scores []float64, leaves []int64) {
+	c, q := t.compiled, t.quickScorer
+	nTrees := len(c.roots)
+	bySamples := func(start, end int) {
+		x := X[start*nFeatures : end*nFeatures]
+		switch {
+		case q != nil && scores != nil:
+			quickScoreTrees(q, x, quick, end-start, nFeatures, scores[start*c.n_outputs:end*c.n_outputs])
+		case q != nil:
+			quickLeaveIndexTrees(q, x, quick, end-start, nFeatures, leaves[start*nTrees:end*nTrees])
+		case scores != nil:
+			scoreTrees(c, x, thresholds, end-start, nFeatures, scores[start*c.n_outputs:end*c.n_outputs])
+		default:
+			leaveIndexTrees(c, x, thresholds, end-start, nFeatures, 0, nTrees, leaves[start*nTrees:end*nTrees])
+		}
+	}
+	// QuickScorer evaluates all the trees of a sample at once, it can only be split by samples
+	if t.Workers <= 1 || q != nil || nSamples >= 2*treeBatchSize || nTrees < 2*treeChunkSize {
+		parallelChunks(nSamples, t.Workers, treeBatchSize, bySamples)
+		return
+	}
+
+	if leaves == nil {
+		leaves = make([]int64, nSamples*nTrees)
+	}
+	parallelChunks(nTrees, t.Workers, treeChunkSize, func(first, last int) {
+		leaveIndexTrees(c, X, thresholds, nSamples, nFeatures, first, last, leaves)
+	})
+	if scores == nil {
+		return
+	}
+	seen := c.seen(c.n_outputs)
+	for i := 0; i < nSamples; i++ {
+		clear(seen)
+		row := scores[i*c.n_outputs : (i+1)*c.n_outputs]
+		for _, leaf := range leaves[i*nTrees : (i+1)*nTrees] {
+			c.accumulate(int32(leaf), row, seen)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package ops_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/ops"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+func TestTreeEnsembleParallel(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	nFeatures := 10
+	for _, modes := range [][]string{allModes, quickScorerModes} {
+		node := randomForest(r, 200, 4, nFeatures, modes)
+		// A large batch is split by samples, a single row by trees
+		for _, nSamples := range []int{1000, 1} {
+			X := randomSamples(r, nSamples, nFeatures)
+			var wantScores, wantLeaves *tensor.Tensor
+			for _, workers := range []int{1, 2, 7, 32} {
+				options := kernel.DefaultOptions()
+				options.TreeWorkers = workers
+				tree := &ops.TreeEnsemble{}
+				err := tree.Init(node, options)
+				if err != nil {
+					t.Fatalf("error shouldn't exist: %v", err)
+				}
+				scores, err := tree.Scores(X, tensor.Double)
+				if err != nil {
+					t.Fatalf("error shouldn't exist: %v", err)
+				}
+				leaves, err := tree.LeaveIndexTrees(X)
+				if err != nil {
+					t.Fatalf("error shouldn't exist: %v", err)
+				}
+				if workers == 1 {
+					wantScores, wantLeaves = scores, leaves
+					continue
+				}
+				if !slices.Equal(wantScores.DoubleData, scores.DoubleData) {
+					t.Fatalf("%d samples, %d workers: scores differ from the sequential ones", nSamples, workers)
+				}
+				if !slices.Equal(wantLeaves.Int64Data, leaves.Int64Data) {
+					t.Fatalf("%d samples, %d workers: leaves differ from the sequential ones", nSamples, workers)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package ops
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Leaves of a tree are bits of a uint64
+const quickScorerMaxLeaves = 64
+
+// The automatic strategy uses QuickScorer for at least quickScorerMinTrees trees of at most
+// quickScorerMaxDepth branches from root to leaf. A few trees are traversed in less time than
+// QuickScorer takes to reset and scan its bitvectors, and the conditions of deeper trees fail too
+// often for it to skip much work.
+const (
+	quickScorerMinTrees = 16
+	quickScorerMaxDepth = 4
+)
+
+/*
+ * QuickScorer (Lucchese et al., SIGIR 2015) evaluates all the trees at once. The leaves of each
+ * tree are numbered left to right, the left child being the one taken when the condition holds,
+ * and every branch gets a mask clearing the leaves of its left subtree. The branches are grouped
+ * by feature and sorted by threshold, so for a sample only the branches whose condition fails are
+ * visited, each one and-ing its mask into the bitvector of its tree. The leftmost leaf left in
+ * a bitvector is the one a traversal would reach.
+ *
+ * Conditions are normalized to x <= th (or x < th when strict). BRANCH_GT and BRANCH_GTE are
+ * their negation with the children swapped, other modes can't be evaluated this way.
+ */
+type quickScorerFeature struct {
+	trees   []int32
+	masks   []uint64
+	strict  []bool
+	missing []int // branches whose condition fails on NaN
+}
+
+type quickScorer struct {
+	compiled     *compiledTrees
+	features     []quickScorerFeature
+	thresholds   [][]float64 // sorted thresholds of each feature
+	thresholds32 [][]float32
+	leaves       [][]int32 // leaves of each tree, left to right
+}
+
+type quickScorerBranch struct {
+	tree      int32
+	mask      uint64
+	strict    bool
+	nan_fails bool
+	threshold float64
+}
+
+func newQuickScorer(c *compiledTrees) (*quickScorer, error) {
+	q := &quickScorer{
+		compiled: c,
+		leaves:   make([][]int32, len(c.roots)),
+	}
+	branches := make([][]quickScorerBranch, c.n_features)
+	visited := make([]bool, len(c.nodes))
+
+	// Returns the bits of the leaves below node
+	var walk func(tree int32, index int32) (uint64, error)
+	walk = func(tree int32, index int32) (uint64, error) {
+		if visited[index] {
+			return 0, fmt.Errorf("node %d is shared between branches", index)
+		}
+		visited[index] = true
+		node := &c.nodes[index]
+		if node.mode == nodeLeaf {
+			position := len(q.leaves[tree])
+			if position >= quickScorerMaxLeaves {
+				return 0, fmt.Errorf("tree %d has more than %d leaves", tree, quickScorerMaxLeaves)
+			}
+			q.leaves[tree] = append(q.leaves[tree], index)
+			return 1 << position, nil
+		}
+
+		left, right := node.true_child, node.false_child
+		strict, swapped := false, false
+		switch node.mode {
+		case nodeBranchLEQ:
+		case nodeBranchLT:
+			strict = true
+		case nodeBranchGT:
+			left, right = right, left
+			swapped = true
+		case nodeBranchGTE:
+			left, right = right, left
+			strict, swapped = true, true
+		default:
+			return 0, fmt.Errorf("node mode %d can't be evaluated with QuickScorer", node.mode)
+		}
+		if math.IsNaN(c.thresholds[index]) {
+			return 0, fmt.Errorf("node %d has a NaN threshold", index)
+		}
+		leftBits, err := walk(tree, left)
+		if err != nil {
+			return 0, err
+		}
+		rightBits, err := walk(tree, right)
+		if err != nil {
+			return 0, err
+		}
+		branches[node.feature] = append(branches[node.feature], quickScorerBranch{
+			tree:      tree,
+			mask:      ^leftBits,
+			strict:    strict,
+			nan_fails: node.missing_true == swapped,
+			threshold: c.thresholds[index],
+		})
+		return leftBits | rightBits, nil
+	}
+	for tree, root := range c.roots {
+		_, err := walk(int32(tree), root)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q.features = make([]quickScorerFeature, c.n_features)
+	q.thresholds = make([][]float64, c.n_features)
+	q.thresholds32 = make([][]float32, c.n_features)
+	for f := range branches {
+		sort.SliceStable(branches[f], func(i, j int) bool {
+			return branches[f][i].threshold < branches[f][j].threshold
+		})
+		feature := &q.features[f]
+		for i, branch := range branches[f] {
+			feature.trees = append(feature.trees, branch.tree)
+			feature.masks = append(feature.masks, branch.mask)
+			feature.strict = append(feature.strict, branch.strict)
+			if branch.nan_fails {
+				feature.missing = append(feature.missing, i)
+			}
+			q.thresholds[f] = append(q.thresholds[f], branch.threshold)
+			q.thresholds32[f] = append(q.thresholds32[f], float32(branch.threshold))
+		}
+	}
+	return q, nil
+}
+
+// Sets the leaf reached in every tree by the sample x
+func quickScorerLeaves[T tensor.Float32_64](q *quickScorer, x []T, thresholds [][]T, vectors []uint64, leaves []int32) {
+	for i := range vectors {
+		vectors[i] = math.MaxUint64
+	}
+	for f := range q.features {
+		feature := &q.features[f]
+		v := x[f]
+		if v != v {
+			for _, i := range feature.missing {
+				vectors[feature.trees[i]] &= feature.masks[i]
+			}
+			continue
+		}
+		th := thresholds[f]
+		// Thresholds are sorted, the conditions of the remaining branches hold
+		for i := 0; i < len(th) && th[i] <= v; i++ {
+			if th[i] < v || feature.strict[i] {
+				vectors[feature.trees[i]] &= feature.masks[i]
+			}
+		}
+	}
+	for tree := range vectors {
+		leaves[tree] = q.leaves[tree][bits.TrailingZeros64(vectors[tree])]
+	}
+}
+
+func quickScoreTrees[T tensor.Float32_64](q *quickScorer, X []T, thresholds [][]T, nSamples, nFeatures int, scores []float64) {
+	c := q.compiled
+	vectors := make([]uint64, len(c.roots))
+	leaves := make([]int32, len(c.roots))
+	seen := c.seen(c.n_outputs)
+	for i := 0; i < nSamples; i++ {
+		quickScorerLeaves(q, X[i*nFeatures:(i+1)*nFeatures], thresholds, vectors, leaves)
+		clear(seen)
+		row := scores[i*c.n_outputs : (i+1)*c.n_outputs]
+		for _, leaf := range leaves {
+			c.accumulate(leaf, row, seen)
+		}
+	}
+}
+
+func quickLeaveIndexTrees[T tensor.Float32_64](q *quickScorer, X []T, thresholds [][]T, nSamples, nFeatures int, output []int64) {
+	nTrees := len(q.compiled.roots)
+	vectors := make([]uint64, nTrees)
+	leaves := make([]int32, nTrees)
+	for i := 0; i < nSamples; i++ {
+		quickScorerLeaves(q, X[i*nFeatures:(i+1)*nFeatures], thresholds, vectors, leaves)
+		for j := range leaves {
+			output[i*nTrees+j] = int64(leaves[j])
+		}
+	}
+}
+
+// SetStrategy builds the QuickScorer evaluator when the strategy asks for it, or when it is the
+// automatic choice and there are enough shallow trees for it to be faster
+func (t *TreeEnsemble) SetStrategy(strategy kernel.TreeStrategy) error {
+	t.quickScorer = nil
+	switch strategy {
+	case kernel.TreeStrategyTraversal:
+		return nil
+	case kernel.TreeStrategyAuto:
+		c := t.compiled
+		if len(c.roots) < quickScorerMinTrees || c.depth() > quickScorerMaxDepth {
+			return nil
+		}
+		q, err := newQuickScorer(c)
+		if err == nil {
+			t.quickScorer = q
+		}
+		return nil
+	case kernel.TreeStrategyQuickScorer:
+		q, err := newQuickScorer(t.compiled)
+		if err != nil {
+			return fmt.Errorf("trees can't be evaluated with QuickScorer: %v", err)
+		}
+		t.quickScorer = q
+		return nil
+	default:
+		return fmt.Errorf("tree strategy %d not supported", strategy)
+	}
+}
+
+// Strategy returns how the trees are evaluated, TreeStrategyTraversal or TreeStrategyQuickScorer
+func (t *TreeEnsemble) Strategy() kernel.TreeStrategy {
+	if t.quickScorer != nil {
+		return kernel.TreeStrategyQuickScorer
+	}
+	return kernel.TreeStrategyTraversal
+}
@@ -0,0 +1,128 @@
+package ops_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/ops"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+var quickScorerModes = []string{"BRANCH_LEQ", "BRANCH_LT", "BRANCH_GTE", "BRANCH_GT"}
+
+func TestTreeEnsembleQuickScorer(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	nFeatures := 8
+	tree := &ops.TreeEnsemble{}
+	err := tree.Init(randomForest(r, 100, 4, nFeatures, quickScorerModes), kernel.DefaultOptions())
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	X := randomSamples(r, 500, nFeatures)
+	double, err := X.Clone()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	double.Cast(tensor.Double)
+
+	for _, input := range []*tensor.Tensor{X, double} {
+		err = tree.SetStrategy(kernel.TreeStrategyTraversal)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+		wantLeaves, _ := tree.LeaveIndexTrees(input)
+		wantScores, _ := tree.Scores(input, tensor.Double)
+
+		err = tree.SetStrategy(kernel.TreeStrategyQuickScorer)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+		leaves, err := tree.LeaveIndexTrees(input)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+		if !slices.Equal(wantLeaves.Int64Data, leaves.Int64Data) {
+			t.Fatalf("QuickScorer leaves differ from the traversal ones")
+		}
+		scores, err := tree.Scores(input, tensor.Double)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+		if !slices.Equal(wantScores.DoubleData, scores.DoubleData) {
+			t.Fatalf("expected %v, got %v", wantScores.DoubleData, scores.DoubleData)
+		}
+	}
+}
+
+func TestTreeEnsembleQuickScorerUnsupported(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	tree := &ops.TreeEnsemble{}
+	// Equality splits can't be sorted by threshold
+	err := tree.Init(randomForest(r, 10, 3, 4, []string{"BRANCH_EQ"}), kernel.DefaultOptions())
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	if tree.SetStrategy(kernel.TreeStrategyQuickScorer) == nil {
+		t.Fatalf("QuickScorer shouldn't accept BRANCH_EQ splits")
+	}
+	// Trees with more than 64 leaves don't fit in the bitvectors
+	err = tree.Init(randomForest(r, 2, 7, 4, quickScorerModes), kernel.DefaultOptions())
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	if tree.SetStrategy(kernel.TreeStrategyQuickScorer) == nil {
+		t.Fatalf("QuickScorer shouldn't accept trees of 128 leaves")
+	}
+}
+
+// The automatic strategy only picks QuickScorer for many shallow trees it can evaluate
+func TestTreeEnsembleAutoStrategy(t *testing.T) {
+	cases := []struct {
+		trees, depth int
+		modes        []string
+		expected     kernel.TreeStrategy
+	}{
+		{16, 4, quickScorerModes, kernel.TreeStrategyQuickScorer},
+		{500, 2, quickScorerModes, kernel.TreeStrategyQuickScorer},
+		{15, 4, quickScorerModes, kernel.TreeStrategyTraversal},
+		{16, 5, quickScorerModes, kernel.TreeStrategyTraversal},
+		{16, 4, allModes, kernel.TreeStrategyTraversal},
+	}
+	r := rand.New(rand.NewSource(5))
+	for _, c := range cases {
+		tree := &ops.TreeEnsemble{}
+		err := tree.Init(randomForest(r, c.trees, c.depth, 6, c.modes), kernel.DefaultOptions())
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+		if strategy := tree.Strategy(); strategy != c.expected {
+			t.Errorf("%d trees of depth %d: expected strategy %d, got %d", c.trees, c.depth, c.expected, strategy)
+		}
+	}
+}
+
+func BenchmarkTreeEnsembleQuickScorer(b *testing.B) {
+	r := rand.New(rand.NewSource(7))
+	tree := &ops.TreeEnsemble{}
+	err := tree.Init(randomForest(r, 500, 5, 20, quickScorerModes), kernel.DefaultOptions())
+	if err != nil {
+		b.Fatalf("error shouldn't exist: %v", err)
+	}
+	X := randomSamples(r, 1000, 20)
+	for _, strategy := range []kernel.TreeStrategy{kernel.TreeStrategyTraversal, kernel.TreeStrategyQuickScorer} {
+		err = tree.SetStrategy(strategy)
+		if err != nil {
+			b.Fatalf("error shouldn't exist: %v", err)
+		}
+		b.Run([]string{"auto", "traversal", "quickscorer"}[strategy], func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err = tree.Scores(X, tensor.Float)
+				if err != nil {
+					b.Fatalf("error shouldn't exist: %v", err)
+				}
+			}
+		})
+	}
+}
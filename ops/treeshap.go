@@ -0,0 +1,244 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Smallest number of samples worth giving to a worker when explaining
+const shapChunkSize = 8
+
+type shapPathElement struct {
+	feature int32
+	zero    float64 // fraction of the cover flowing through the path when the feature is unknown
+	one     float64 // 1 when x follows the path, 0 otherwise
+	weight  float64
+}
+
+/*
+ * Shap computes the exact SHAP values of the raw scores with TreeSHAP (Lundberg et al., "Consistent
+ * Individualized Feature Attribution for Tree Ensembles", algorithm 2). The cover of every node,
+ * the number of training samples reaching it, is read from nodes_hitrates. A feature missing from
+ * a sample follows the child the trees send it to.
+ *
+ * Scores are explained before the post transform, and before the second column is added to the
+ * binary classifiers, so there is one output per target or class weight column. Summed and
+ * averaged ensembles are additive, min and max ones are not and can't be explained.
+ */
+func (t *TreeEnsemble) Shap(X *tensor.Tensor) (*Explanation, error) {
+	c := t.compiled
+	if c.aggregate != aggregateSum && c.aggregate != aggregateAverage {
+		return nil, fmt.Errorf("treeensemble: only SUM and AVERAGE ensembles can be explained")
+	}
+	covers, err := t.covers()
+	if err != nil {
+		return nil, err
+	}
+	nSamples, nFeatures, x32, x64, err := t.features(X)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := 1.0
+	if c.aggregate == aggregateAverage && len(c.roots) > 0 {
+		scale = 1 / float64(len(c.roots))
+	}
+	e := newExplanation(nSamples, c.n_outputs, nFeatures)
+	for _, root := range c.roots {
+		expectedValue(c, covers, root, scale, e.Bias)
+	}
+	for j := 0; j < c.n_outputs && j < len(t.BaseValues); j++ {
+		e.Bias[j] += t.BaseValues[j]
+	}
+
+	depth := 0
+	for _, root := range c.roots {
+		depth = max(depth, treeDepth(c, root))
+	}
+	// The path of a node is stored after the one of its parent, and is one element longer
+	bufferSize := (depth + 2) * (depth + 3) / 2
+	parallelChunks(nSamples, t.Workers, shapChunkSize, func(start, end int) {
+		buffer := make([]shapPathElement, bufferSize)
+		for i := start; i < end; i++ {
+			s := &treeShap{c: c, covers: covers, scale: scale, phi: e.Contributions[i]}
+			for _, root := range c.roots {
+				if x64 != nil {
+					shapRecurse(s, x64[i*nFeatures:(i+1)*nFeatures], c.thresholds, root, buffer, 0, 1, 1, -1)
+				} else {
+					shapRecurse(s, x32[i*nFeatures:(i+1)*nFeatures], c.thresholds32, root, buffer, 0, 1, 1, -1)
+				}
+			}
+		}
+	})
+	e.sumScores()
+	return e, nil
+}
+
+// Returns the cover of every node. An internal node nothing reaches would make the fractions
+// of its children undefined.
+func (t *TreeEnsemble) covers() ([]float64, error) {
+	hitrates := t.Atts.nodes_hitrates_as_tensor
+	if hitrates == nil {
+		hitrates = t.Atts.nodes_hitrates
+	}
+	if hitrates == nil {
+		return nil, fmt.Errorf("treeensemble: nodes_hitrates is required to explain the trees")
+	}
+	covers := tensorDoubles(hitrates)
+	if len(covers) != len(t.compiled.nodes) {
+		return nil, fmt.Errorf("treeensemble: nodes_hitrates has %d values for %d nodes", len(covers), len(t.compiled.nodes))
+	}
+	for i, node := range t.compiled.nodes {
+		if covers[i] < 0 || (node.mode != nodeLeaf && covers[i] == 0) {
+			return nil, fmt.Errorf("treeensemble: node %d has an invalid cover %v", t.Atts.nodes_nodeids[i], covers[i])
+		}
+	}
+	return covers, nil
+}
+
+// Adds to bias the leaf weights below index weighted by the fraction of the cover reaching them
+func expectedValue(c *compiledTrees, covers []float64, index int32, fraction float64, bias []float64) {
+	node := &c.nodes[index]
+	if node.mode == nodeLeaf {
+		for k := node.leaf_start; k < node.leaf_end; k++ {
+			bias[c.leaf_ids[k]] += fraction * c.leaf_weights[k]
+		}
+		return
+	}
+	for _, child := range []int32{node.true_child, node.false_child} {
+		expectedValue(c, covers, child, fraction*covers[child]/covers[index], bias)
+	}
+}
+
+func treeDepth(c *compiledTrees, index int32) int {
+	node := &c.nodes[index]
+	if node.mode == nodeLeaf {
+		return 0
+	}
+	return 1 + max(treeDepth(c, node.true_child), treeDepth(c, node.false_child))
+}
+
+type treeShap struct {
+	c      *compiledTrees
+	covers []float64
+	scale  float64
+	phi    [][]float64 // [outputs][features]
+}
+
+// Grows the path by one element, splitting the weights between the subsets with and without it
+func extendPath(path []shapPathElement, depth int, zero, one float64, feature int32) {
+	path[depth] = shapPathElement{feature: feature, zero: zero, one: one}
+	if depth == 0 {
+		path[depth].weight = 1
+	}
+	for i := depth - 1; i >= 0; i-- {
+		path[i+1].weight += one * path[i].weight * float64(i+1) / float64(depth+1)
+		path[i].weight = zero * path[i].weight * float64(depth-i) / float64(depth+1)
+	}
+}
+
+// Undoes extendPath for the element at index
+func unwindPath(path []shapPathElement, depth, index int) {
+	one, zero := path[index].one, path[index].zero
+	next := path[depth].weight
+	for i := depth - 1; i >= 0; i-- {
+		if one != 0 {
+			weight := path[i].weight
+			path[i].weight = next * float64(depth+1) / (float64(i+1) * one)
+			next = weight - path[i].weight*zero*float64(depth-i)/float64(depth+1)
+		} else {
+			path[i].weight = path[i].weight * float64(depth+1) / (zero * float64(depth-i))
+		}
+	}
+	for i := index; i < depth; i++ {
+		path[i].feature, path[i].zero, path[i].one = path[i+1].feature, path[i+1].zero, path[i+1].one
+	}
+}
+
+// Returns the total weight of the path once the element at index is unwound, without changing it
+func unwoundPathSum(path []shapPathElement, depth, index int) float64 {
+	one, zero := path[index].one, path[index].zero
+	total := 0.0
+	if one != 0 {
+		next := path[depth].weight
+		for i := depth - 1; i >= 0; i-- {
+			weight := next * float64(depth+1) / (float64(i+1) * one)
+			total += weight
+			next = path[i].weight - weight*zero*float64(depth-i)/float64(depth+1)
+		}
+	} else {
+		for i := depth - 1; i >= 0; i-- {
+			total += path[i].weight * float64(depth+1) / (zero * float64(depth-i))
+		}
+	}
+	return total
+}
+
+func shapRecurse[T tensor.Float32_64](s *treeShap, x []T, thresholds []T, index int32, parent []shapPathElement, depth int, zero, one float64, feature int32) {
+	path := parent[depth:]
+	copy(path, parent[:depth])
+	extendPath(path, depth, zero, one, feature)
+
+	c := s.c
+	node := &c.nodes[index]
+	if node.mode == nodeLeaf {
+		for i := 1; i <= depth; i++ {
+			weight := unwoundPathSum(path, depth, i) * (path[i].one - path[i].zero) * s.scale
+			for k := node.leaf_start; k < node.leaf_end; k++ {
+				s.phi[c.leaf_ids[k]][path[i].feature] += weight * c.leaf_weights[k]
+			}
+		}
+		return
+	}
+
+	hot, cold := node.true_child, node.false_child
+	if descend(c, x, thresholds, index, 1) != hot {
+		hot, cold = cold, hot
+	}
+	// A feature already split on above is unwound, the fractions of both splits multiply
+	incomingZero, incomingOne := 1.0, 1.0
+	for i := 1; i <= depth; i++ {
+		if path[i].feature == node.feature {
+			incomingZero, incomingOne = path[i].zero, path[i].one
+			unwindPath(path, depth, i)
+			depth--
+			break
+		}
+	}
+	cover := s.covers[index]
+	children := [2]int32{hot, cold}
+	ones := [2]float64{incomingOne, 0}
+	for j, child := range children {
+		// A child with no cover that x doesn't follow weighs nothing in any subset
+		childZero := s.covers[child] / cover * incomingZero
+		if childZero != 0 || ones[j] != 0 {
+			shapRecurse(s, x, thresholds, child, path, depth+1, childZero, ones[j], node.feature)
+		}
+	}
+}
+
+func (t *TreeEnsembleClassifier) Explain(k *kernel.Kernel) (*Explanation, error) {
+	input, err := lastInput(k, t.input)
+	if err != nil {
+		return nil, err
+	}
+	return t.tree.Shap(input)
+}
+
+func (t *TreeEnsembleRegressor) Explain(k *kernel.Kernel) (*Explanation, error) {
+	input, err := lastInput(k, t.input)
+	if err != nil {
+		return nil, err
+	}
+	return t.tree.Shap(input)
+}
+
+func (t *UnifiedTreeEnsemble) Explain(k *kernel.Kernel) (*Explanation, error) {
+	input, err := lastInput(k, t.input)
+	if err != nil {
+		return nil, err
+	}
+	return t.tree.Shap(input)
+}
@@ -0,0 +1,171 @@
+package ops_test
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/ops"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Adds to a randomForest node the covers of a training set reaching each leaf at random
+func withCovers(r *rand.Rand, node *ir.NodeProto, nTrees, depth int) []float32 {
+	nNodes := 1<<(depth+1) - 1
+	covers := make([]float32, nTrees*nNodes)
+	for tree := range nTrees {
+		tc := covers[tree*nNodes : (tree+1)*nNodes]
+		for n := nNodes - 1; n >= 0; n-- {
+			if n >= nNodes/2 {
+				tc[n] = float32(r.Intn(10))
+			} else {
+				tc[n] = tc[2*n+1] + tc[2*n+2]
+				if tc[n] == 0 {
+					tc[2*n+1], tc[n] = 1, 1
+				}
+			}
+		}
+	}
+	node.Attribute = append(node.Attribute, &ir.AttributeProto{Name: "nodes_hitrates", Floats: covers})
+	return covers
+}
+
+func TestTreeShapAdditivity(t *testing.T) {
+	for _, aggregate := range []string{"SUM", "AVERAGE"} {
+		r := rand.New(rand.NewSource(7))
+		nTrees, nSamples, nFeatures := 20, 50, 5
+		node := randomForest(r, nTrees, 4, nFeatures, allModes)
+		withCovers(r, node, nTrees, 4)
+		node.Attribute = append(node.Attribute,
+			&ir.AttributeProto{Name: "aggregate_function", S: []byte(aggregate)},
+			&ir.AttributeProto{Name: "base_values", Floats: []float32{0.5}})
+		tree := &ops.TreeEnsemble{}
+		err := tree.Init(node, kernel.DefaultOptions())
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+		X := randomSamples(r, nSamples, nFeatures)
+		scores, err := tree.Scores(X, tensor.Double)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+		e, err := tree.Shap(X)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+		for i := range nSamples {
+			total := e.Bias[0]
+			for _, phi := range e.Contributions[i][0] {
+				total += phi
+			}
+			if math.Abs(total-scores.DoubleData[i]) > 1e-9 {
+				t.Fatalf("%s, sample %d: contributions add up to %v, the score is %v", aggregate, i, total, scores.DoubleData[i])
+			}
+		}
+	}
+}
+
+// Computes the Shapley values of the trees by enumerating the subsets of features. The value of a
+// subset is the expected score when only its features are known, the others following the covers.
+func bruteForceShap(node *ir.NodeProto, covers []float32, nTrees, depth int, x []float32) []float64 {
+	var featureids []int64
+	var values, weights []float32
+	for _, attr := range node.Attribute {
+		switch attr.Name {
+		case "nodes_featureids":
+			featureids = attr.Ints
+		case "nodes_values":
+			values = attr.Floats
+		case "target_weights":
+			weights = attr.Floats
+		}
+	}
+	nNodes := 1<<(depth+1) - 1
+	var value func(tree, n int, known uint) float64
+	value = func(tree, n int, known uint) float64 {
+		i := tree*nNodes + n
+		if n >= nNodes/2 {
+			return float64(weights[tree*(nNodes/2+1)+n-nNodes/2])
+		}
+		feature := featureids[i]
+		if known&(1<<feature) != 0 {
+			if x[feature] <= values[i] {
+				return value(tree, 2*n+1, known)
+			}
+			return value(tree, 2*n+2, known)
+		}
+		left, right := covers[i-n+2*n+1], covers[i-n+2*n+2]
+		return (float64(left)*value(tree, 2*n+1, known) + float64(right)*value(tree, 2*n+2, known)) / float64(covers[i])
+	}
+	subset := func(known uint) float64 {
+		total := 0.0
+		for tree := range nTrees {
+			total += value(tree, 0, known)
+		}
+		return total
+	}
+
+	nFeatures := len(x)
+	phi := make([]float64, nFeatures)
+	factorial := func(n int) float64 {
+		f := 1.0
+		for i := 2; i <= n; i++ {
+			f *= float64(i)
+		}
+		return f
+	}
+	for f := range nFeatures {
+		for known := uint(0); known < 1<<nFeatures; known++ {
+			if known&(1<<f) != 0 {
+				continue
+			}
+			size := bits.OnesCount(known)
+			weight := factorial(size) * factorial(nFeatures-size-1) / factorial(nFeatures)
+			phi[f] += weight * (subset(known|1<<f) - subset(known))
+		}
+	}
+	return phi
+}
+
+func TestTreeShapBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	nTrees, depth, nSamples, nFeatures := 3, 3, 20, 4
+	node := randomForest(r, nTrees, depth, nFeatures, []string{"BRANCH_LEQ"})
+	covers := withCovers(r, node, nTrees, depth)
+	tree := &ops.TreeEnsemble{}
+	err := tree.Init(node, kernel.DefaultOptions())
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	X := tensor.CreateEmptyTensor([]int{nSamples, nFeatures}, tensor.Float)
+	for i := range X.FloatData {
+		X.FloatData[i] = float32(r.Intn(10))
+	}
+	e, err := tree.Shap(X)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	for i := range nSamples {
+		expected := bruteForceShap(node, covers, nTrees, depth, X.FloatData[i*nFeatures:(i+1)*nFeatures])
+		for f := range expected {
+			if math.Abs(expected[f]-e.Contributions[i][0][f]) > 1e-9 {
+				t.Fatalf("sample %d: expected %v, got %v", i, expected, e.Contributions[i][0])
+			}
+		}
+	}
+}
+
+func TestTreeShapWithoutCovers(t *testing.T) {
+	tree := &ops.TreeEnsemble{}
+	err := tree.Init(randomForest(rand.New(rand.NewSource(7)), 2, 2, 3, allModes), kernel.DefaultOptions())
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	_, err = tree.Shap(tensor.CreateEmptyTensor([]int{1, 3}, tensor.Float))
+	if err == nil {
+		t.Fatalf("trees without nodes_hitrates shouldn't be explained")
+	}
+}
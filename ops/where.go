@@ -0,0 +1,104 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"github.com/systemEng-Learning/go-ml-deployment/tensor"
+)
+
+// Where takes the values of X where the condition is true and those of Y elsewhere. The three
+// inputs are broadcast against each other.
+type Where struct {
+	condition int
+	x         int
+	y         int
+	output    int
+}
+
+func (w *Where) Init(k *kernel.Kernel, node *ir.NodeProto) error {
+	if len(node.Input) != 3 {
+		return fmt.Errorf("%s: a condition, X and Y are required", node.OpType)
+	}
+	inputs := make([]int, 3)
+	for i, name := range node.Input {
+		input, err := k.RegisterReader(name)
+		if err != nil {
+			return err
+		}
+		inputs[i] = input
+	}
+	w.condition, w.x, w.y = inputs[0], inputs[1], inputs[2]
+	if len(node.Attribute) > 0 {
+		return fmt.Errorf("%s not supported for %s", node.Attribute[0].Name, node.OpType)
+	}
+	w.output = k.RegisterWriter(node.Output[0])
+	return nil
+}
+
+func (w *Where) Compute(k *kernel.Kernel) error {
+	c, err := k.Input(w.condition)
+	if err != nil {
+		return err
+	}
+	a, err := k.Input(w.x)
+	if err != nil {
+		return err
+	}
+	b, err := k.Input(w.y)
+	if err != nil {
+		return err
+	}
+	condition, x, y := c.Tensor, a.Tensor, b.Tensor
+	if condition.DType != tensor.Bool {
+		return fmt.Errorf("where: condition is %v, not bool", condition.DType)
+	}
+	if x.DType != y.DType {
+		return fmt.Errorf("where: X and Y have different datatypes %v and %v", x.DType, y.DType)
+	}
+	shape, err := broadcastShape(x.Shape, y.Shape)
+	if err != nil {
+		return fmt.Errorf("where: %w", err)
+	}
+	shape, err = broadcastShape(condition.Shape, shape)
+	if err != nil {
+		return fmt.Errorf("where: %w", err)
+	}
+	output, err := k.Output(w.output, shape, x.DType)
+	if err != nil {
+		return err
+	}
+	strides := [][]int{
+		broadcastStrides(condition.Shape, shape),
+		broadcastStrides(x.Shape, shape),
+		broadcastStrides(y.Shape, shape),
+	}
+	switch x.DType {
+	case tensor.Float:
+		where(condition.BoolData, x.FloatData, y.FloatData, output.FloatData, shape, strides)
+	case tensor.Double:
+		where(condition.BoolData, x.DoubleData, y.DoubleData, output.DoubleData, shape, strides)
+	case tensor.Int32:
+		where(condition.BoolData, x.Int32Data, y.Int32Data, output.Int32Data, shape, strides)
+	case tensor.Int64:
+		where(condition.BoolData, x.Int64Data, y.Int64Data, output.Int64Data, shape, strides)
+	case tensor.String:
+		where(condition.BoolData, x.StringData, y.StringData, output.StringData, shape, strides)
+	case tensor.Bool:
+		where(condition.BoolData, x.BoolData, y.BoolData, output.BoolData, shape, strides)
+	default:
+		return fmt.Errorf("where: input datatype (%v) is invalid", x.DType)
+	}
+	return nil
+}
+
+func where[T any](condition []bool, x, y, out []T, shape []int, strides [][]int) {
+	walk(shape, strides, func(i int, offsets []int) {
+		if condition[offsets[0]] {
+			out[i] = x[offsets[1]]
+		} else {
+			out[i] = y[offsets[2]]
+		}
+	})
+}
@@ -14,9 +14,16 @@ type ZipMap struct {
 	classlabels_int64s  []int64
 	classlabels_strings []string
 	use_strings         bool
+	raw                 bool // the probabilities are output as a matrix instead of maps
 	output              int
 }
 
+// SetRaw makes the op output its input probability matrix instead of a map per sample, which
+// saves building the maps when the caller only needs the probabilities
+func (z *ZipMap) SetRaw(raw bool) {
+	z.raw = raw
+}
+
 func (z *ZipMap) Init(k *kernel.Kernel, node *ir.NodeProto) error {
 	input, err := k.RegisterReader(node.Input[0])
 	if err != nil {
@@ -48,7 +55,7 @@ func (z *ZipMap) Compute(k *kernel.Kernel) error {
 		return err
 	}
 	input := data.Tensor
-	if input.DType != tensor.Float {
+	if input.DType != tensor.Float && input.DType != tensor.Double {
 		return errors.ErrUnsupported
 	}
 	rows := input.Shape[0]
@@ -58,34 +65,64 @@ func (z *ZipMap) Compute(k *kernel.Kernel) error {
 	} else {
 		cols = input.Shape[1]
 	}
+	labels := len(z.classlabels_int64s)
 	if z.use_strings {
-		if cols != len(z.classlabels_strings) {
-			return fmt.Errorf("zipmap: input features per batch %d != number of classlabels %d", cols, len(z.classlabels_strings))
-		}
-		output, err := k.Output(z.output, []int{rows}, tensor.StringMap)
-		if err != nil {
-			return err
-		}
-		for i := range rows {
-			output.StringMap[i] = make(map[string]float32)
-			for j := range cols {
-				output.StringMap[i][z.classlabels_strings[j]] = input.FloatData[i*cols+j]
-			}
-		}
-	} else {
-		if cols != len(z.classlabels_int64s) {
-			return fmt.Errorf("zipmap: input features per batch %d != number of classlabels %d", cols, len(z.classlabels_int64s))
-		}
-		output, err := k.Output(z.output, []int{rows}, tensor.IntMap)
-		if err != nil {
-			return err
-		}
-		for i := range rows {
-			output.IntMap[i] = make(map[int64]float32)
-			for j := range cols {
-				output.IntMap[i][z.classlabels_int64s[j]] = input.FloatData[i*cols+j]
-			}
-		}
+		labels = len(z.classlabels_strings)
+	}
+	if cols != labels {
+		return fmt.Errorf("zipmap: input features per batch %d != number of classlabels %d", cols, labels)
+	}
+	if z.raw {
+		return z.passThrough(k, data)
+	}
+	if k.Get(z.output) == input {
+		// The input was passed through on an earlier run, it must not be turned into maps
+		k.Put(z.output, nil)
+	}
+
+	dtype := tensor.IntMap
+	if z.use_strings && input.DType == tensor.Double {
+		dtype = tensor.StringDoubleMap
+	} else if z.use_strings {
+		dtype = tensor.StringMap
+	} else if input.DType == tensor.Double {
+		dtype = tensor.IntDoubleMap
+	}
+	output, err := k.Output(z.output, []int{rows}, dtype)
+	if err != nil {
+		return err
+	}
+	switch dtype {
+	case tensor.StringMap:
+		zip(output.StringMap, z.classlabels_strings, input.FloatData, cols)
+	case tensor.StringDoubleMap:
+		zip(output.StringDoubleMap, z.classlabels_strings, input.DoubleData, cols)
+	case tensor.IntMap:
+		zip(output.IntMap, z.classlabels_int64s, input.FloatData, cols)
+	case tensor.IntDoubleMap:
+		zip(output.IntDoubleMap, z.classlabels_int64s, input.DoubleData, cols)
 	}
 	return nil
 }
+
+// Places the probabilities in the output as they are, the input is only cloned when another op
+// reads it too
+func (z *ZipMap) passThrough(k *kernel.Kernel, data kernel.Data) error {
+	if data.Readers == 1 {
+		return k.Put(z.output, data.Tensor)
+	}
+	output, err := data.Tensor.Clone()
+	if err != nil {
+		return err
+	}
+	return k.Put(z.output, output)
+}
+
+func zip[K comparable, V float32 | float64](maps []map[K]V, labels []K, values []V, cols int) {
+	for i := range maps {
+		maps[i] = make(map[K]V, cols)
+		for j := range cols {
+			maps[i][labels[j]] = values[i*cols+j]
+		}
+	}
+}
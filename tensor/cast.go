@@ -14,6 +14,30 @@ func cast[From Numeric, To Numeric](from []From, to []To, length int) []To {
 	return to
 }
 
+func fromBool[To Numeric](from []bool, to []To, length int) []To {
+	if len(to) < length {
+		to = make([]To, length)
+	}
+	for i := range length {
+		to[i] = 0
+		if from[i] {
+			to[i] = 1
+		}
+	}
+	return to
+}
+
+// Any value other than zero is true
+func toBool[From Numeric](from []From, to []bool, length int) []bool {
+	if len(to) < length {
+		to = make([]bool, length)
+	}
+	for i := range length {
+		to[i] = from[i] != 0
+	}
+	return to
+}
+
 func (t *Tensor) Cast(to DataType) {
 	if t.DType == IntMap || t.DType == StringMap {
 		log.Println("casting map-like tensors isn't supported")
@@ -22,10 +46,7 @@ func (t *Tensor) Cast(to DataType) {
 	if t.DType == to {
 		return
 	}
-	length := t.Shape[0]
-	if len(t.Shape) == 2 {
-		length *= t.Shape[1]
-	}
+	length := Size(t.Shape)
 
 	if t.DType == Float && to == Double {
 		t.DoubleData = cast(t.FloatData, t.DoubleData, length)
@@ -51,6 +72,22 @@ func (t *Tensor) Cast(to DataType) {
 		t.DoubleData = cast(t.Int64Data, t.DoubleData, length)
 	} else if t.DType == Int64 && to == Int32 {
 		t.Int32Data = cast(t.Int64Data, t.Int32Data, length)
+	} else if t.DType == Bool && to == Float {
+		t.FloatData = fromBool(t.BoolData, t.FloatData, length)
+	} else if t.DType == Bool && to == Double {
+		t.DoubleData = fromBool(t.BoolData, t.DoubleData, length)
+	} else if t.DType == Bool && to == Int32 {
+		t.Int32Data = fromBool(t.BoolData, t.Int32Data, length)
+	} else if t.DType == Bool && to == Int64 {
+		t.Int64Data = fromBool(t.BoolData, t.Int64Data, length)
+	} else if t.DType == Float && to == Bool {
+		t.BoolData = toBool(t.FloatData, t.BoolData, length)
+	} else if t.DType == Double && to == Bool {
+		t.BoolData = toBool(t.DoubleData, t.BoolData, length)
+	} else if t.DType == Int32 && to == Bool {
+		t.BoolData = toBool(t.Int32Data, t.BoolData, length)
+	} else if t.DType == Int64 && to == Bool {
+		t.BoolData = toBool(t.Int64Data, t.BoolData, length)
 	} else {
 		log.Fatalf("unsupported cast combination: %v -> %v", t.DType, to)
 	}
@@ -110,3 +110,23 @@ func TestCastFloatToInt32PrecisionLoss(t *testing.T) {
 		t.Errorf("Expected Int32Data to be %v, but got %v", expected, tensor.Int32Data)
 	}
 }
+
+func TestCastBool(t *testing.T) {
+	tensor := &Tensor{
+		DType:    Bool,
+		Shape:    []int{3},
+		BoolData: []bool{true, false, true},
+	}
+
+	tensor.Cast(Float)
+	expected := []float32{1, 0, 1}
+	if !reflect.DeepEqual(tensor.FloatData, expected) {
+		t.Errorf("Expected FloatData to be %v, but got %v", expected, tensor.FloatData)
+	}
+
+	tensor.FloatData[1] = -0.5
+	tensor.Cast(Bool)
+	if !reflect.DeepEqual(tensor.BoolData, []bool{true, true, true}) {
+		t.Errorf("Expected every non zero value to be true, got %v", tensor.BoolData)
+	}
+}
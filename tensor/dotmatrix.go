@@ -0,0 +1,71 @@
+package tensor
+
+import "sync"
+
+const (
+	// Smallest number of multiply-adds worth giving to a goroutine
+	dotChunkWork = 1 << 18
+	// Rows of the transposed matrix multiplied by every row of the first one before going on, so
+	// that they stay in cache
+	dotBlockRows = 64
+)
+
+/*
+ * dotMatrix multiplies a [rows, inner] matrix by a transposed [cols, inner] one, as Dot does,
+ * when both and the output have the same floating point type. The products are accumulated in
+ * double precision like the other paths of Dot, 4 rows of b at a time so that each value of a is
+ * loaded once for all of them. Large batches are split by rows over at most workers goroutines.
+ */
+func dotMatrix[T Float32_64](a, b, out []T, rows, cols, inner, workers int) {
+	chunks := min(workers, rows, rows*cols*inner/dotChunkWork)
+	if chunks <= 1 {
+		dotRows(a, b, out, 0, rows, cols, inner)
+		return
+	}
+	size := (rows + chunks - 1) / chunks
+	var wg sync.WaitGroup
+	for start := 0; start < rows; start += size {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dotRows(a, b, out, start, min(start+size, rows), cols, inner)
+		}()
+	}
+	wg.Wait()
+}
+
+func dotRows[T Float32_64](a, b, out []T, start, end, cols, inner int) {
+	for block := 0; block < cols; block += dotBlockRows {
+		block_end := min(block+dotBlockRows, cols)
+		for i := start; i < end; i++ {
+			x := a[i*inner : (i+1)*inner]
+			o := out[i*cols : (i+1)*cols]
+			j := block
+			for ; j+4 <= block_end; j += 4 {
+				b0 := b[j*inner : (j+1)*inner]
+				b1 := b[(j+1)*inner : (j+2)*inner]
+				b2 := b[(j+2)*inner : (j+3)*inner]
+				b3 := b[(j+3)*inner : (j+4)*inner]
+				b0, b1, b2, b3 = b0[:len(x)], b1[:len(x)], b2[:len(x)], b3[:len(x)]
+				var s0, s1, s2, s3 float64
+				for p, v := range x {
+					w := float64(v)
+					s0 += w * float64(b0[p])
+					s1 += w * float64(b1[p])
+					s2 += w * float64(b2[p])
+					s3 += w * float64(b3[p])
+				}
+				o[j], o[j+1], o[j+2], o[j+3] = T(s0), T(s1), T(s2), T(s3)
+			}
+			for ; j < block_end; j++ {
+				row := b[j*inner : (j+1)*inner]
+				row = row[:len(x)]
+				var s float64
+				for p, v := range x {
+					s += float64(v) * float64(row[p])
+				}
+				o[j] = T(s)
+			}
+		}
+	}
+}
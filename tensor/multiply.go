@@ -17,6 +17,12 @@ If t is 2-D and other is 1-D, it will be a sum product over the last axis of t a
 *
 */
 func (t *Tensor) Dot(other *Tensor, out *Tensor) (*Tensor, error) {
+	return t.ParallelDot(other, out, 1)
+}
+
+// ParallelDot is Dot with large products of float or double matrices split by rows over at most
+// workers goroutines. 1 or less multiplies on the calling goroutine.
+func (t *Tensor) ParallelDot(other *Tensor, out *Tensor, workers int) (*Tensor, error) {
 	// Cannot multiply maps
 	if t.DType == IntMap || t.DType == StringMap || other.DType == IntMap || other.DType == StringMap {
 		return nil, errors.New("cannot execute dot operations on map tensors")
@@ -26,7 +32,7 @@ func (t *Tensor) Dot(other *Tensor, out *Tensor) (*Tensor, error) {
 		return t.vectorMultiply(other, out)
 	}
 	if len(t.Shape) == 2 && len(other.Shape) == 2 {
-		return t.matrixMultiply(other, out)
+		return t.matrixMultiply(other, out, workers)
 	}
 	if len(t.Shape) == 2 && len(other.Shape) == 1 {
 		return t.matrixVectorMultiply(other, out)
@@ -190,7 +196,7 @@ func (t *Tensor) vectorMultiply(other *Tensor, out *Tensor) (*Tensor, error) {
 	return out, nil
 }
 
-func (t *Tensor) matrixMultiply(other *Tensor, out *Tensor) (*Tensor, error) {
+func (t *Tensor) matrixMultiply(other *Tensor, out *Tensor, workers int) (*Tensor, error) {
 	// Ensure both have the same number of columns
 	if t.Shape[1] != other.Shape[1] {
 		return nil, errors.New("both matrix column axis are not equal")
@@ -198,6 +204,16 @@ func (t *Tensor) matrixMultiply(other *Tensor, out *Tensor) (*Tensor, error) {
 	if out == nil {
 		out = createOutputTensor(t.DType, other.DType, []int{t.Shape[0], other.Shape[0]})
 	}
+	if t.DType == other.DType && out.DType == t.DType {
+		switch t.DType {
+		case Float:
+			dotMatrix(t.FloatData, other.FloatData, out.FloatData, t.Shape[0], other.Shape[0], t.Shape[1], workers)
+			return out, nil
+		case Double:
+			dotMatrix(t.DoubleData, other.DoubleData, out.DoubleData, t.Shape[0], other.Shape[0], t.Shape[1], workers)
+			return out, nil
+		}
+	}
 
 	if out.DType == Float {
 		var sum float64
@@ -1,6 +1,9 @@
 package tensor
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func TestDot_Vector(t *testing.T) {
 	a := &Tensor{FloatData: []float32{1, 2, 3}, Shape: []int{3}, DType: Float}
@@ -252,3 +255,56 @@ func TestDot_Vector_Int32FloatInputs(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, result.DoubleData[0])
 	}
 }
+
+// A product large enough to be split over goroutines, with a number of columns that is not a
+// multiple of the 4 computed together
+func TestDot_MatrixMatrixLarge(t *testing.T) {
+	rows, inner, cols := 2000, 70, 67
+	a := &Tensor{DoubleData: make([]float64, rows*inner), Shape: []int{rows, inner}, DType: Double}
+	b := &Tensor{DoubleData: make([]float64, cols*inner), Shape: []int{cols, inner}, DType: Double}
+	for i := range a.DoubleData {
+		a.DoubleData[i] = float64(i%13) - 6
+	}
+	for i := range b.DoubleData {
+		b.DoubleData[i] = float64(i%11) * 0.25
+	}
+	result, err := a.ParallelDot(b, nil, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := range rows {
+		for j := range cols {
+			expected := 0.0
+			for p := range inner {
+				expected += a.DoubleData[i*inner+p] * b.DoubleData[j*inner+p]
+			}
+			if result.DoubleData[i*cols+j] != expected {
+				t.Fatalf("Expected %v at (%d, %d), got %v", expected, i, j, result.DoubleData[i*cols+j])
+			}
+		}
+	}
+}
+
+// The product of a batch by the transposed weights of a dense layer of 100 units
+func BenchmarkDot_MatrixMatrix(b *testing.B) {
+	for _, rows := range []int{1, 100, 10000} {
+		b.Run(fmt.Sprint(rows), func(b *testing.B) {
+			inner, cols := 64, 100
+			a := &Tensor{FloatData: make([]float32, rows*inner), Shape: []int{rows, inner}, DType: Float}
+			w := &Tensor{FloatData: make([]float32, cols*inner), Shape: []int{cols, inner}, DType: Float}
+			for i := range a.FloatData {
+				a.FloatData[i] = float32(i%7) - 3
+			}
+			for i := range w.FloatData {
+				w.FloatData[i] = float32(i%5) * 0.1
+			}
+			out := &Tensor{FloatData: make([]float32, rows*cols), Shape: []int{rows, cols}, DType: Float}
+			b.ResetTimer()
+			for range b.N {
+				if _, err := a.Dot(w, out); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,12 @@ func (t *Tensor) Square() {
 	if len(t.Shape) == 2 {
 		length *= t.Shape[1]
 	}
+	if t.DType == Double {
+		for i := range length {
+			t.DoubleData[i] = t.DoubleData[i] * t.DoubleData[i]
+		}
+		return
+	}
 	for i := range length {
 		t.FloatData[i] = t.FloatData[i] * t.FloatData[i]
 	}
@@ -17,6 +23,12 @@ func (t *Tensor) Cube() {
 	if len(t.Shape) == 2 {
 		length *= t.Shape[1]
 	}
+	if t.DType == Double {
+		for i := range length {
+			t.DoubleData[i] = t.DoubleData[i] * t.DoubleData[i] * t.DoubleData[i]
+		}
+		return
+	}
 	for i := range length {
 		t.FloatData[i] = t.FloatData[i] * t.FloatData[i] * t.FloatData[i]
 	}
@@ -27,6 +39,12 @@ func (t *Tensor) Power(degree float64) {
 	if len(t.Shape) == 2 {
 		length *= t.Shape[1]
 	}
+	if t.DType == Double {
+		for i := range length {
+			t.DoubleData[i] = math.Pow(t.DoubleData[i], degree)
+		}
+		return
+	}
 	for i := range length {
 		t.FloatData[i] = float32(math.Pow(float64(t.FloatData[i]), degree))
 	}
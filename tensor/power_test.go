@@ -64,3 +64,14 @@ func TestRaiseTo42D(t *testing.T) {
 		t.Errorf("expected %v, got %v", expected, a.FloatData)
 	}
 }
+
+func TestPowersDouble(t *testing.T) {
+	a := mustTensor(CreateEmptyTensor([]int{2, 2}, Double), []float64{1, 2, 3, 0.5})
+	a.Square()
+	a.Cube()
+	a.Power(0.5)
+	expected := []float64{1, 8, 27, 0.125}
+	if !reflect.DeepEqual(a.DoubleData, expected) {
+		t.Errorf("expected %v, got %v", expected, a.DoubleData)
+	}
+}
@@ -7,6 +7,12 @@ func (t *Tensor) Tanh(out *Tensor) {
 	if len(t.Shape) == 2 {
 		length *= t.Shape[1]
 	}
+	if out.DType == Double {
+		for i := range length {
+			out.DoubleData[i] = math.Tanh(out.DoubleData[i])
+		}
+		return
+	}
 	for i := range length {
 		out.FloatData[i] = float32(math.Tanh(float64(out.FloatData[i])))
 	}
@@ -27,3 +27,14 @@ func matricesClose(a, b []float32, tol float64) bool {
 	}
 	return true
 }
+
+func TestTanhDouble(t *testing.T) {
+	a := mustTensor(CreateEmptyTensor([]int{3}, Double), []float64{-1, 0, 0.5})
+	a.Tanh(a)
+	expected := []float64{math.Tanh(-1), 0, math.Tanh(0.5)}
+	for i := range expected {
+		if a.DoubleData[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, a.DoubleData)
+		}
+	}
+}
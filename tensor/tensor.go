@@ -1,8 +1,10 @@
 package tensor
 
 import (
+	"encoding/binary"
 	"fmt"
 	"log"
+	"math"
 	"slices"
 	"strings"
 
@@ -24,6 +26,8 @@ const (
 	IntStringMap
 	IntDoubleMap
 	StringDoubleMap
+	Sequence
+	Bool
 )
 
 var dataTypeMap = map[DataType]string{
@@ -39,6 +43,8 @@ var dataTypeMap = map[DataType]string{
 	IntStringMap:    "intstringmap",
 	IntDoubleMap:    "intdoublemap",
 	StringDoubleMap: "stringdoublemap",
+	Sequence:        "sequence",
+	Bool:            "bool",
 }
 
 func (dt DataType) String() string {
@@ -59,6 +65,8 @@ type Tensor struct {
 	IntStringMap    []map[int64][]byte
 	IntDoubleMap    []map[int64]float64
 	StringDoubleMap []map[string]float64
+	SequenceData    []*Tensor // the tensors of a sequence, which can have different shapes
+	BoolData        []bool
 }
 
 func (t *Tensor) Clone() (*Tensor, error) {
@@ -86,6 +94,17 @@ func (t *Tensor) Clone() (*Tensor, error) {
 		newTensor.IntDoubleMap = slices.Clone(t.IntDoubleMap)
 	case StringDoubleMap:
 		newTensor.StringDoubleMap = slices.Clone(t.StringDoubleMap)
+	case Bool:
+		newTensor.BoolData = slices.Clone(t.BoolData)
+	case Sequence:
+		newTensor.SequenceData = make([]*Tensor, len(t.SequenceData))
+		for i, item := range t.SequenceData {
+			clone, err := item.Clone()
+			if err != nil {
+				return nil, err
+			}
+			newTensor.SequenceData[i] = clone
+		}
 	default:
 		return nil, fmt.Errorf("tensor copy: unsupported data type %d", t.DType)
 	}
@@ -99,10 +118,7 @@ func CreateEmptyTensor(shape []int, dataType DataType) *Tensor {
 		Shape: shape,
 		DType: dataType,
 	}
-	size := shape[0]
-	if len(shape) == 2 {
-		size *= shape[1]
-	}
+	size := Size(shape)
 
 	switch dataType {
 	case Float:
@@ -127,6 +143,10 @@ func CreateEmptyTensor(shape []int, dataType DataType) *Tensor {
 		t.IntDoubleMap = make([]map[int64]float64, shape[0])
 	case StringDoubleMap:
 		t.StringDoubleMap = make([]map[string]float64, shape[0])
+	case Sequence:
+		t.SequenceData = make([]*Tensor, shape[0])
+	case Bool:
+		t.BoolData = make([]bool, size)
 	}
 
 	return t
@@ -158,6 +178,10 @@ func CreateOrReuseTensor(t *Tensor, shape []int, dtype DataType) *Tensor {
 			DType: dtype,
 		}
 		t.Alloc()
+	} else if t.DType != dtype {
+		t.Shape = shape
+		t.DType = dtype
+		t.Alloc()
 	} else {
 		t.Reuse(shape)
 	}
@@ -188,6 +212,10 @@ func (t *Tensor) Clear() {
 		t.IntDoubleMap = nil
 	case StringDoubleMap:
 		t.StringDoubleMap = nil
+	case Sequence:
+		t.SequenceData = nil
+	case Bool:
+		t.BoolData = nil
 	}
 }
 
@@ -215,15 +243,25 @@ func (t *Tensor) Capacity() int {
 		return len(t.IntDoubleMap)
 	case StringDoubleMap:
 		return len(t.StringDoubleMap)
+	case Sequence:
+		return len(t.SequenceData)
+	case Bool:
+		return len(t.BoolData)
 	}
 	return 0
 }
 
-func (t *Tensor) Alloc() {
-	capacity := t.Shape[0]
-	if len(t.Shape) > 1 {
-		capacity *= t.Shape[1]
+// Returns the number of elements of a tensor of the given shape
+func Size(shape []int) int {
+	size := 1
+	for _, d := range shape {
+		size *= d
 	}
+	return size
+}
+
+func (t *Tensor) Alloc() {
+	capacity := Size(t.Shape)
 	switch t.DType {
 	case Float:
 		t.FloatData = make([]float32, capacity)
@@ -247,15 +285,16 @@ func (t *Tensor) Alloc() {
 		t.IntDoubleMap = make([]map[int64]float64, t.Shape[0])
 	case StringDoubleMap:
 		t.StringDoubleMap = make([]map[string]float64, t.Shape[0])
+	case Sequence:
+		t.SequenceData = make([]*Tensor, t.Shape[0])
+	case Bool:
+		t.BoolData = make([]bool, capacity)
 	}
 }
 
 func (t *Tensor) Reuse(shape []int) {
 	capacity := t.Capacity()
-	count := shape[0]
-	if len(shape) > 1 {
-		count *= shape[1]
-	}
+	count := Size(shape)
 	t.Shape = shape
 	if capacity < count {
 		t.Alloc()
@@ -285,6 +324,8 @@ func (t *Tensor) rawData() any {
 		return t.Int64Data
 	case String:
 		return t.StringData
+	case Bool:
+		return t.BoolData
 	default:
 		return nil
 	}
@@ -340,6 +381,8 @@ func (t *Tensor) print1D(s *strings.Builder) {
 			fmt.Fprintf(s, "%v", t.StringMap[i])
 		case String:
 			s.WriteString(string(t.StringData[i]))
+		case Bool:
+			fmt.Fprintf(s, "%t", t.BoolData[i])
 		case StringIntMap:
 			fmt.Fprintf(s, "%v", t.StringIntMap[i])
 		case IntStringMap:
@@ -384,6 +427,8 @@ func (t *Tensor) print2D(s *strings.Builder) {
 				fmt.Fprintf(s, "%f", t.DoubleData[i*m+j])
 			case String:
 				s.WriteString(string(t.StringData[i*m+j]))
+			case Bool:
+				fmt.Fprintf(s, "%t", t.BoolData[i*m+j])
 			}
 			if j < m-1 {
 				s.WriteString(", ")
@@ -407,6 +452,8 @@ func OnnxTypeToDtype(elemType int32) DataType {
 		return Double
 	case "STRING":
 		return String
+	case "BOOL":
+		return Bool
 	default:
 		log.Printf("onnx type %s has not been defined.\n", elemTypeStr)
 		return Undefined
@@ -425,26 +472,67 @@ func FromTensorProto(Tp *ir.TensorProto) (*Tensor, error) {
 	switch elemTypeStr {
 	case "FLOAT":
 		t.FloatData = Tp.FloatData
-		t.Shape = []int{len(t.FloatData)}
+		if len(Tp.RawData) > 0 {
+			t.FloatData = decodeRaw(Tp.RawData, 4, func(b []byte) float32 {
+				return math.Float32frombits(binary.LittleEndian.Uint32(b))
+			})
+		}
 		t.DType = Float
-		return t, nil
 	case "INT32":
 		t.Int32Data = Tp.Int32Data
+		if len(Tp.RawData) > 0 {
+			t.Int32Data = decodeRaw(Tp.RawData, 4, func(b []byte) int32 {
+				return int32(binary.LittleEndian.Uint32(b))
+			})
+		}
 		t.DType = Int32
-		return t, nil
 	case "INT64":
 		t.Int64Data = Tp.Int64Data
+		if len(Tp.RawData) > 0 {
+			t.Int64Data = decodeRaw(Tp.RawData, 8, func(b []byte) int64 {
+				return int64(binary.LittleEndian.Uint64(b))
+			})
+		}
 		t.DType = Int64
-		return t, nil
 	case "DOUBLE":
 		t.DoubleData = Tp.DoubleData
+		if len(Tp.RawData) > 0 {
+			t.DoubleData = decodeRaw(Tp.RawData, 8, func(b []byte) float64 {
+				return math.Float64frombits(binary.LittleEndian.Uint64(b))
+			})
+		}
 		t.DType = Double
-		return t, nil
 	case "STRING":
 		t.StringData = Tp.StringData
 		t.DType = String
-		return t, nil
+	case "BOOL":
+		// Bools are stored in int32_data, or a byte each in raw_data
+		t.BoolData = make([]bool, len(Tp.Int32Data))
+		for i, v := range Tp.Int32Data {
+			t.BoolData[i] = v != 0
+		}
+		if len(Tp.RawData) > 0 {
+			t.BoolData = decodeRaw(Tp.RawData, 1, func(b []byte) bool {
+				return b[0] != 0
+			})
+		}
+		t.DType = Bool
 	default:
 		return nil, fmt.Errorf("tensor copy: unsupported data type %d", t.DType)
 	}
+	// A tensor without dims holding a single value is a scalar. Attribute tensors are also often
+	// stored without dims, when they hold several values they are treated as 1-D
+	if len(t.Shape) == 0 && t.Capacity() != 1 {
+		t.Shape = []int{t.Capacity()}
+	}
+	return t, nil
+}
+
+// Tensors in onnx files are usually stored as little endian bytes in raw_data instead of the typed fields
+func decodeRaw[T any](raw []byte, size int, decode func([]byte) T) []T {
+	data := make([]T, len(raw)/size)
+	for i := range data {
+		data[i] = decode(raw[i*size : (i+1)*size])
+	}
+	return data
 }
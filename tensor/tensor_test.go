@@ -0,0 +1,35 @@
+package tensor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+// Test that a tensor without dims holding a single value stays a scalar
+func TestFromTensorProtoScalar(t *testing.T) {
+	tp := &ir.TensorProto{DataType: int32(ir.TensorProto_INT64), Int64Data: []int64{2}}
+	tensor, err := FromTensorProto(tp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tensor.Shape) != 0 {
+		t.Errorf("Expected a scalar shape, but got %v", tensor.Shape)
+	}
+	if !reflect.DeepEqual(tensor.Int64Data, []int64{2}) {
+		t.Errorf("Expected Int64Data to be [2], but got %v", tensor.Int64Data)
+	}
+}
+
+// Test that a tensor without dims holding several values is treated as 1-D
+func TestFromTensorProtoWithoutDims(t *testing.T) {
+	tp := &ir.TensorProto{DataType: int32(ir.TensorProto_FLOAT), FloatData: []float32{1, 2, 3}}
+	tensor, err := FromTensorProto(tp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tensor.Shape, []int{3}) {
+		t.Errorf("Expected shape [3], but got %v", tensor.Shape)
+	}
+}
@@ -0,0 +1,68 @@
+package tests
+
+import "testing"
+
+func TestClip(t *testing.T) {
+	sg := Test("Clip")
+	sg.addInput("X", []int{2, 3}, [][]float32{{-3, 0.5, 2}, {7, -1, 4}})
+	sg.addInput("min", []int{1}, []float32{-1})
+	sg.addInput("max", []int{1}, []float32{4})
+	sg.addOutput("Y", [][]float32{{-1, 0.5, 2}, {4, -1, 4}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	// Without max, the values are only limited from below
+	sg = Test("Clip")
+	sg.addInput("X", []int{4}, []int64{-3, 0, 2, 9})
+	sg.addInput("min", []int{1}, []int64{0})
+	sg.addOutput("Y", []int64{0, 0, 2, 9})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	// Opset 6 exports give the bounds as attributes
+	sg = Test("Clip")
+	sg.addAttribute("min", float32(0))
+	sg.addAttribute("max", float32(1))
+	sg.addInput("X", []int{3}, []float64{-0.5, 0.25, 1.5})
+	sg.addOutput("Y", []float64{0, 0.25, 1})
+	sg.errorBound = 0.00001
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestClipBoundShape(t *testing.T) {
+	sg := Test("Clip")
+	sg.addInput("X", []int{2}, []float32{1, 2})
+	sg.addInput("min", []int{2}, []float32{0, 0})
+	sg.addOutput("Y", []float32{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("a bound with more than one value should be rejected")
+	}
+}
+
+func TestReciprocal(t *testing.T) {
+	sg := Test("Reciprocal")
+	sg.addInput("X", []int{2, 2}, [][]float32{{1, 2}, {-4, 0.5}})
+	sg.addOutput("Y", [][]float32{{1, 0.5}, {-0.25, 2}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("Reciprocal")
+	sg.addInput("X", []int{2}, []int64{1, 2})
+	sg.addOutput("Y", []int64{})
+	err = sg.Execute(t)
+	if err == nil {
+		t.Fatalf("integer inputs should be rejected")
+	}
+}
@@ -3,11 +3,15 @@ package tests
 import (
 	"log"
 	"math"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/systemEng-Learning/go-ml-deployment/graph"
 	"github.com/systemEng-Learning/go-ml-deployment/ir"
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type SingleNodeGraph struct {
@@ -16,11 +20,26 @@ type SingleNodeGraph struct {
 	expected      []any
 	errorBound    float64
 	isRelativeErr bool
+	options       kernel.Options
 	graph         *graph.Graph
 }
 
+// loadModel reads a JSON model from testdata and returns its graph
+func loadModel(t testing.TB, filename string) *ir.GraphProto {
+	path := filepath.Join("../testdata", filename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file %s: %v", path, err)
+	}
+	var model ir.ModelProto
+	if err := protojson.Unmarshal(data, &model); err != nil {
+		t.Fatalf("failed to unmarshal file %s: %v", path, err)
+	}
+	return model.GetGraph()
+}
+
 func Test(nodeName string) *SingleNodeGraph {
-	sg := SingleNodeGraph{}
+	sg := SingleNodeGraph{options: kernel.DefaultOptions()}
 	sg.onnxGraph = &ir.GraphProto{}
 	sg.onnxGraph.Node = append(sg.onnxGraph.Node, &ir.NodeProto{OpType: nodeName})
 	return &sg
@@ -31,6 +50,8 @@ func (sg *SingleNodeGraph) addAttribute(name string, value any) {
 	switch item := value.(type) {
 	case int64:
 		attr.I = item
+	case float32:
+		attr.F = item
 	case []byte:
 		attr.S = item
 	case []int64:
@@ -44,6 +65,8 @@ func (sg *SingleNodeGraph) addAttribute(name string, value any) {
 		}
 	case [][]byte:
 		attr.Strings = item
+	case *ir.TensorProto:
+		attr.T = item
 	default:
 		log.Fatalf("unsupported type for %v", item)
 	}
@@ -66,6 +89,8 @@ func (sg *SingleNodeGraph) addInput(name string, shape []int, value any) {
 		elemType = ir.TensorProto_DataType_value["DOUBLE"]
 	case string, []string, [][]string:
 		elemType = ir.TensorProto_DataType_value["STRING"]
+	case bool, []bool, [][]bool:
+		elemType = ir.TensorProto_DataType_value["BOOL"]
 	default:
 		elemType = 0
 	}
@@ -120,6 +145,32 @@ func (sg *SingleNodeGraph) addInputMap(name string, value any) {
 	sg.onnxGraph.Node[0].Input = append(sg.onnxGraph.Node[0].Input, name)
 }
 
+// addInputSequence declares a sequence input whose items are of elemType
+func (sg *SingleNodeGraph) addInputSequence(name string, value any, elemType *ir.TypeProto) {
+	sg.inputs = append(sg.inputs, value)
+	input := ir.ValueInfoProto{Name: name}
+	input.Type = &ir.TypeProto{Value: &ir.TypeProto_SequenceType{
+		SequenceType: &ir.TypeProto_Sequence{ElemType: elemType},
+	}}
+	sg.onnxGraph.Input = append(sg.onnxGraph.Input, &input)
+	sg.onnxGraph.Node[0].Input = append(sg.onnxGraph.Node[0].Input, name)
+}
+
+// Model wraps a graph loaded from testdata, its inputs and expected outputs are set with feed and expect
+func Model(t testing.TB, filename string) *SingleNodeGraph {
+	sg := SingleNodeGraph{options: kernel.DefaultOptions()}
+	sg.onnxGraph = loadModel(t, filename)
+	return &sg
+}
+
+func (sg *SingleNodeGraph) feed(value any) {
+	sg.inputs = append(sg.inputs, value)
+}
+
+func (sg *SingleNodeGraph) expect(value any) {
+	sg.expected = append(sg.expected, value)
+}
+
 func (sg *SingleNodeGraph) setInput(index int, value any) {
 	sg.inputs[index] = value
 }
@@ -133,7 +184,7 @@ func (sg *SingleNodeGraph) addOutput(name string, value any) {
 
 func (sg *SingleNodeGraph) InitOnly() error {
 	graph := graph.Graph{}
-	err := graph.Init(sg.onnxGraph)
+	err := graph.InitWithOptions(sg.onnxGraph, sg.options)
 	if err != nil {
 		return err
 	}
@@ -183,6 +234,16 @@ func (sg *SingleNodeGraph) RunOnly(t testing.TB, bench bool) error {
 					t.Fatalf("expected %v, got %v", o, item)
 				}
 			}
+		case [][][]string:
+			o := sg.expected[i].([][][]string)
+			if !reflect.DeepEqual(o, item) {
+				t.Fatalf("expected %v, got %v", o, item)
+			}
+		case [][]string:
+			o := sg.expected[i].([][]string)
+			if !reflect.DeepEqual(o, item) {
+				t.Fatalf("expected %v, got %v", o, item)
+			}
 		case [][]int64:
 			o := sg.expected[i].([][]int64)
 			if !reflect.DeepEqual(o, item) {
@@ -240,11 +301,44 @@ func (sg *SingleNodeGraph) RunOnly(t testing.TB, bench bool) error {
 					}
 				}
 			}
+		case []bool, [][]bool:
+			if !reflect.DeepEqual(sg.expected[i], item) {
+				t.Fatalf("expected %v, got %v", sg.expected[i], item)
+			}
+		case []any:
+			if !reflect.DeepEqual(sg.expected[i], item) {
+				t.Fatalf("expected %v, got %v", sg.expected[i], item)
+			}
+		case []map[int64]float32:
+			compareMaps(t, sg, sg.expected[i], item)
+		case []map[int64]float64:
+			compareMaps(t, sg, sg.expected[i], item)
+		case []map[string]float64:
+			compareMaps(t, sg, sg.expected[i], item)
 		}
 	}
 	return nil
 }
 
+func compareMaps[K comparable, V float32 | float64](t testing.TB, sg *SingleNodeGraph, expected any, item []map[K]V) {
+	o := expected.([]map[K]V)
+	if len(o) != len(item) {
+		t.Fatalf("expected %v, got %v", o, item)
+	}
+	for x := range o {
+		for k, v := range o[x] {
+			var err float64 = sg.errorBound
+			if sg.isRelativeErr {
+				err *= math.Abs(float64(v))
+			}
+			got, ok := item[x][k]
+			if !ok || math.Abs(float64(v-got)) >= err {
+				t.Fatalf("expected %v, got %v", o, item)
+			}
+		}
+	}
+}
+
 func (sg *SingleNodeGraph) Execute(t testing.TB) error {
 	err := sg.InitOnly()
 	if err != nil {
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+func TestGather(t *testing.T) {
+	input := [][]float32{{1, 2, 3}, {4, 5, 6}}
+	sg := Test("Gather")
+	sg.addAttribute("axis", int64(1))
+	sg.addInput("X", []int{2, 3}, input)
+	sg.addInput("indices", []int{3}, []int64{2, 0, -1})
+	sg.addOutput("Y", [][]float32{{3, 1, 3}, {6, 4, 6}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("Gather")
+	sg.addInput("X", []int{2, 3}, [][]string{{"a", "b", "c"}, {"d", "e", "f"}})
+	sg.addInput("indices", []int{3}, []int32{1, 1, 0})
+	sg.addOutput("Y", [][]string{{"d", "e", "f"}, {"d", "e", "f"}, {"a", "b", "c"}})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+// A scalar index initializer removes the gathered axis
+func TestGatherScalarIndex(t *testing.T) {
+	sg := Test("Gather")
+	sg.addAttribute("axis", int64(1))
+	sg.addInput("X", []int{2, 3}, [][]float32{{1, 2, 3}, {4, 5, 6}})
+	sg.onnxGraph.Initializer = append(sg.onnxGraph.Initializer, &ir.TensorProto{
+		Name: "indices", DataType: int32(ir.TensorProto_INT64), Int64Data: []int64{1},
+	})
+	sg.onnxGraph.Node[0].Input = append(sg.onnxGraph.Node[0].Input, "indices")
+	sg.addOutput("Y", []float32{2, 5})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestGatherOutOfRange(t *testing.T) {
+	sg := Test("Gather")
+	sg.addInput("X", []int{3}, []int64{1, 2, 3})
+	sg.addInput("indices", []int{1}, []int64{3})
+	sg.addOutput("Y", []int64{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("an index past the end of the axis should be rejected")
+	}
+}
+
+func TestWhere(t *testing.T) {
+	sg := Test("Where")
+	sg.addInput("C", []int{2, 1}, [][]bool{{true}, {false}})
+	sg.addInput("X", []int{1, 3}, [][]int64{{1, 2, 3}})
+	sg.addInput("Y", []int{1}, []int64{-1})
+	sg.addOutput("Z", [][]int64{{1, 2, 3}, {-1, -1, -1}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("Where")
+	sg.addInput("C", []int{3}, []bool{true, false, true})
+	sg.addInput("X", []int{3}, []float32{1, 2, 3})
+	sg.addInput("Y", []int{3}, []float32{4, 5, 6})
+	sg.addOutput("Z", []float32{1, 5, 3})
+	sg.errorBound = 0.00001
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+package tests
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCDist(t *testing.T) {
+	for _, c := range []struct {
+		metric   string
+		expected [][]float32
+	}{
+		{"sqeuclidean", [][]float32{{0, 25}, {2, 13}}},
+		{"euclidean", [][]float32{{0, 5}, {1.4142135, 3.6055512}}},
+	} {
+		sg := Test("CDist")
+		sg.addAttribute("metric", []byte(c.metric))
+		sg.addInput("A", []int{2, 2}, [][]float32{{0, 0}, {1, 1}})
+		sg.addInput("B", []int{2, 2}, [][]float32{{0, 0}, {3, 4}})
+		sg.addOutput("Y", c.expected)
+		sg.errorBound = 0.00001
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+	}
+}
+
+// More rows than the blocks the distances are computed by, in double precision
+func TestCDistBlocks(t *testing.T) {
+	a := make([][]float64, 70)
+	b := make([][]float64, 300)
+	for _, m := range [][][]float64{a, b} {
+		for i := range m {
+			m[i] = []float64{math.Sin(float64(len(m) + i)), math.Cos(float64(i)), float64(i%7) / 3}
+		}
+	}
+	expected := make([][]float64, len(a))
+	for i := range a {
+		expected[i] = make([]float64, len(b))
+		for j := range b {
+			for f := range a[i] {
+				expected[i][j] += (a[i][f] - b[j][f]) * (a[i][f] - b[j][f])
+			}
+			expected[i][j] = math.Sqrt(expected[i][j])
+		}
+	}
+	sg := Test("CDist")
+	sg.addAttribute("metric", []byte("euclidean"))
+	sg.addInput("A", []int{len(a), 3}, a)
+	sg.addInput("B", []int{len(b), 3}, b)
+	sg.addOutput("Y", expected)
+	sg.errorBound = 1e-7
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestMatMul(t *testing.T) {
+	sg := Test("MatMul")
+	sg.addInput("A", []int{2, 3}, [][]float32{{1, 2, 3}, {4, 5, 6}})
+	sg.addInput("B", []int{3, 2}, [][]float32{{1, 0}, {0, 1}, {1, 1}})
+	sg.addOutput("Y", [][]float32{{4, 5}, {10, 11}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	// A vector on the right is a column, its dimension is dropped
+	sg = Test("MatMul")
+	sg.addInput("A", []int{2, 3}, [][]int64{{1, 2, 3}, {4, 5, 6}})
+	sg.addInput("B", []int{3}, []int64{1, 1, 2})
+	sg.addOutput("Y", []int64{9, 21})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestGemm(t *testing.T) {
+	a := [][]float32{{1, 2}, {3, 4}}
+	b := [][]float32{{1, 0}, {1, 1}, {0, 2}}
+	for _, c := range []struct {
+		name     string
+		c        []float32
+		cshape   []int
+		expected [][]float32
+	}{
+		{"without C", nil, nil, [][]float32{{-2, -6, -8}, {-6, -14, -16}}},
+		{"row C", []float32{1, 2, 3}, []int{3}, [][]float32{{-1.5, -5, -6.5}, {-5.5, -13, -14.5}}},
+		{"column C", []float32{10, 20}, []int{2, 1}, [][]float32{{3, -1, -3}, {4, -4, -6}}},
+	} {
+		sg := Test("Gemm")
+		sg.addAttribute("alpha", float32(-2))
+		sg.addAttribute("beta", float32(0.5))
+		sg.addAttribute("transB", int64(1))
+		sg.addInput("A", []int{2, 2}, a)
+		sg.addInput("B", []int{3, 2}, b)
+		if c.c != nil {
+			if len(c.cshape) == 1 {
+				sg.addInput("C", c.cshape, c.c)
+			} else {
+				sg.addInput("C", c.cshape, [][]float32{{c.c[0]}, {c.c[1]}})
+			}
+		}
+		sg.addOutput("Y", c.expected)
+		sg.errorBound = 0.00001
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", c.name, err)
+		}
+	}
+
+	sg := Test("Gemm")
+	sg.addAttribute("transA", int64(1))
+	sg.addInput("A", []int{2, 3}, [][]int64{{1, 2, 3}, {4, 5, 6}})
+	sg.addInput("B", []int{2, 1}, [][]int64{{1}, {1}})
+	sg.addOutput("Y", [][]int64{{5}, {7}, {9}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+// Integer products are summed in int64, 2^53 + 1 isn't a double
+func TestMatMulInt64Exact(t *testing.T) {
+	sg := Test("MatMul")
+	sg.addInput("A", []int{1, 2}, [][]int64{{1 << 53, 1}})
+	sg.addInput("B", []int{2, 1}, [][]int64{{1}, {1}})
+	sg.addOutput("Y", [][]int64{{1<<53 + 1}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+// Integer products are summed in int64 and, with alpha and beta at 1, added to C exactly
+func TestGemmInt64Exact(t *testing.T) {
+	sg := Test("Gemm")
+	sg.addInput("A", []int{1, 2}, [][]int64{{1 << 53, 1}})
+	sg.addInput("B", []int{2, 1}, [][]int64{{1}, {1}})
+	sg.addInput("C", []int{1}, []int64{2})
+	sg.addOutput("Y", [][]int64{{1<<53 + 3}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestMatMulMisaligned(t *testing.T) {
+	sg := Test("MatMul")
+	sg.addInput("A", []int{2, 3}, [][]float32{{1, 2, 3}, {4, 5, 6}})
+	sg.addInput("B", []int{2, 2}, [][]float32{{1, 0}, {0, 1}})
+	sg.addOutput("Y", [][]float32{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("shapes that don't align should be rejected")
+	}
+}
+
+func TestBinaryBroadcast(t *testing.T) {
+	for _, c := range []struct {
+		op       string
+		expected [][]float32
+	}{
+		{"Add", [][]float32{{11, 22}, {13, 24}, {15, 26}}},
+		{"Sub", [][]float32{{-9, -18}, {-7, -16}, {-5, -14}}},
+		{"Mul", [][]float32{{10, 40}, {30, 80}, {50, 120}}},
+		{"Div", [][]float32{{0.1, 0.1}, {0.3, 0.2}, {0.5, 0.3}}},
+	} {
+		sg := Test(c.op)
+		sg.addInput("A", []int{3, 2}, [][]float32{{1, 2}, {3, 4}, {5, 6}})
+		sg.addInput("B", []int{2}, []float32{10, 20})
+		sg.addOutput("Y", c.expected)
+		sg.errorBound = 0.00001
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", c.op, err)
+		}
+	}
+
+	// A column and a row are broadcast to a matrix
+	sg := Test("Add")
+	sg.addInput("A", []int{2, 1}, [][]int64{{1}, {2}})
+	sg.addInput("B", []int{1, 3}, [][]int64{{10, 20, 30}})
+	sg.addOutput("Y", [][]int64{{11, 21, 31}, {12, 22, 32}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestBinaryIntegerDivisionByZero(t *testing.T) {
+	sg := Test("Div")
+	sg.addInput("A", []int{2}, []int64{1, 2})
+	sg.addInput("B", []int{2}, []int64{1, 0})
+	sg.addOutput("Y", []int64{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("an integer division by zero should be rejected")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	sg := Test("Equal")
+	sg.addInput("A", []int{2, 3}, [][]int64{{0, 1, 2}, {2, 2, 0}})
+	sg.addInput("B", []int{1}, []int64{2})
+	sg.addOutput("Y", [][]bool{{false, false, true}, {true, true, false}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	sg := Test("Sqrt")
+	sg.addInput("X", []int{3}, []float64{4, 2, 0})
+	sg.addOutput("Y", []float64{2, 1.4142135623730951, 0})
+	sg.errorBound = 1e-12
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	input := [][]float32{{1, 2, 3}, {4, 5, 6}}
+	for _, c := range []struct {
+		op       string
+		axes     []int64
+		keepdims int64
+		expected any
+	}{
+		{"ReduceSum", []int64{1}, 1, [][]float32{{6}, {15}}},
+		{"ReduceSum", []int64{0}, 0, []float32{5, 7, 9}},
+		{"ReduceMean", []int64{-1}, 1, [][]float32{{2}, {5}}},
+		{"ReduceMean", nil, 0, []float32{3.5}},
+		{"ReduceSumSquare", []int64{1}, 0, []float32{14, 77}},
+	} {
+		sg := Test(c.op)
+		if c.axes != nil {
+			sg.addAttribute("axes", c.axes)
+		}
+		sg.addAttribute("keepdims", c.keepdims)
+		sg.addInput("X", []int{2, 3}, input)
+		sg.addOutput("Y", c.expected)
+		sg.errorBound = 0.00001
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", c.op, err)
+		}
+	}
+}
+
+// From opset 13, ReduceSum takes its axes as an input
+func TestReduceSumAxesInput(t *testing.T) {
+	sg := Test("ReduceSum")
+	sg.addInput("X", []int{2, 3}, [][]int64{{1, 2, 3}, {4, 5, 6}})
+	sg.addInput("axes", []int{1}, []int64{1})
+	sg.addOutput("Y", [][]int64{{6}, {15}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	input := [][]float32{{1, 4, 3, 4}, {8, 6, 7, 5}}
+	for _, c := range []struct {
+		name    string
+		attrs   map[string]int64
+		values  [][]float32
+		indices [][]int64
+	}{
+		{"largest", nil, [][]float32{{4, 4}, {8, 7}}, [][]int64{{1, 3}, {0, 2}}},
+		{"smallest", map[string]int64{"largest": 0}, [][]float32{{1, 3}, {5, 6}}, [][]int64{{0, 2}, {3, 1}}},
+		{"unsorted", map[string]int64{"sorted": 0}, [][]float32{{4, 4}, {8, 7}}, [][]int64{{1, 3}, {0, 2}}},
+		{"first axis", map[string]int64{"axis": 0}, [][]float32{{8, 6, 7, 5}, {1, 4, 3, 4}}, [][]int64{{1, 1, 1, 1}, {0, 0, 0, 0}}},
+	} {
+		sg := Test("TopK")
+		for name, v := range c.attrs {
+			sg.addAttribute(name, v)
+		}
+		sg.addInput("X", []int{2, 4}, input)
+		sg.addInput("K", []int{1}, []int64{2})
+		sg.addOutput("Values", c.values)
+		sg.addOutput("Indices", c.indices)
+		sg.errorBound = 0.00001
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", c.name, err)
+		}
+	}
+}
+
+func TestArgMax(t *testing.T) {
+	input := [][]float32{{1, 5, 5}, {7, 2, 7}}
+	sg := Test("ArgMax")
+	sg.addAttribute("axis", int64(1))
+	sg.addInput("X", []int{2, 3}, input)
+	sg.addOutput("Y", [][]int64{{1}, {0}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("ArgMax")
+	sg.addAttribute("axis", int64(1))
+	sg.addAttribute("keepdims", int64(0))
+	sg.addAttribute("select_last_index", int64(1))
+	sg.addInput("X", []int{2, 3}, input)
+	sg.addOutput("Y", []int64{2, 2})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestArgMin(t *testing.T) {
+	input := [][]float32{{3, 1, 1}, {0, 2, 0}}
+	sg := Test("ArgMin")
+	sg.addAttribute("axis", int64(1))
+	sg.addAttribute("keepdims", int64(0))
+	sg.addInput("X", []int{2, 3}, input)
+	sg.addOutput("Y", []int64{1, 0})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("ArgMin")
+	sg.addAttribute("select_last_index", int64(1))
+	sg.addInput("X", []int{2, 3}, input)
+	sg.addOutput("Y", [][]int64{{1, 0, 1}})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestArrayFeatureExtractor(t *testing.T) {
+	sg := Test("ArrayFeatureExtractor")
+	sg.addInput("X", []int{2, 3}, [][]float32{{1, 2, 3}, {4, 5, 6}})
+	sg.addInput("Y", []int{2}, []int64{2, 0})
+	sg.addOutput("Z", [][]float32{{3, 1}, {6, 4}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	// Labels are looked up from a vector, which gives a single row
+	sg = Test("ArrayFeatureExtractor")
+	sg.addInput("X", []int{3}, []string{"a", "b", "c"})
+	sg.addInput("Y", []int{2, 2}, [][]int64{{2, 2}, {0, 1}})
+	sg.addOutput("Z", [][]string{{"c", "c", "a", "b"}})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestArrayFeatureExtractorOutOfRange(t *testing.T) {
+	sg := Test("ArrayFeatureExtractor")
+	sg.addInput("X", []int{3}, []int64{1, 2, 3})
+	sg.addInput("Y", []int{1}, []int64{3})
+	sg.addOutput("Z", [][]int64{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("an index past the last column should be rejected")
+	}
+}
+
+func TestReshapeAndFlatten(t *testing.T) {
+	sg := Test("Reshape")
+	sg.addInput("X", []int{1, 6}, [][]int64{{1, 2, 3, 4, 5, 6}})
+	sg.addInput("shape", []int{2}, []int64{-1, 3})
+	sg.addOutput("Y", [][]int64{{1, 2, 3}, {4, 5, 6}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("Reshape")
+	sg.addInput("X", []int{2, 3}, [][]int64{{1, 2, 3}, {4, 5, 6}})
+	sg.addInput("shape", []int{2}, []int64{-1, 4})
+	sg.addOutput("Y", [][]int64{})
+	err = sg.Execute(t)
+	if err == nil {
+		t.Fatalf("a shape that doesn't fit the data should be rejected")
+	}
+
+	sg = Test("Flatten")
+	sg.addAttribute("axis", int64(0))
+	sg.addInput("X", []int{2, 2}, [][]float32{{1, 2}, {3, 4}})
+	sg.addOutput("Y", [][]float32{{1, 2, 3, 4}})
+	sg.errorBound = 0.00001
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	sg := Test("Concat")
+	sg.addAttribute("axis", int64(1))
+	sg.addInput("A", []int{2, 1}, [][]float32{{1}, {2}})
+	sg.addInput("B", []int{2, 2}, [][]float32{{3, 4}, {5, 6}})
+	sg.addOutput("Y", [][]float32{{1, 3, 4}, {2, 5, 6}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("Concat")
+	sg.addAttribute("axis", int64(0))
+	sg.addInput("A", []int{1, 2}, [][]int64{{1, 2}})
+	sg.addInput("B", []int{2, 2}, [][]int64{{3, 4}, {5, 6}})
+	sg.addOutput("Y", [][]int64{{1, 2}, {3, 4}, {5, 6}})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
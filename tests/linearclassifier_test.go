@@ -2,6 +2,8 @@ package tests
 
 import (
 	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
 )
 
 type ValidInputTypes interface {
@@ -308,3 +310,132 @@ func BenchmarkLinearClassifier(b *testing.B) {
 		sg.RunOnly(b, true)
 	}
 }
+
+var linearModelSamples = [][]float32{{5.1, 3.5, 1.4, 0.2}, {6.2, 2.9, 4.3, 1.3}, {7.7, 3.0, 6.1, 2.3}, {5.9, 3.0, 5.1, 1.8}}
+
+// Hand-written graphs laid out like the skl2onnx exports of LogisticRegression and RidgeClassifier
+// on iris, OvR probabilities being normalized by a Normalizer node after the LinearClassifier. The
+// coefficients are rounded made-up values, not a trained model, and the expected outputs are
+// computed from them in float64, not by onnxruntime.
+func TestLinearClassifierModels(t *testing.T) {
+	models := []struct {
+		file   string
+		labels []int64
+		scores [][]float32
+	}{
+		{
+			"logreg_ovr.protojson",
+			[]int64{0, 1, 2, 2},
+			[][]float32{
+				{0.880355971, 0.119633816, 1.02129294e-05},
+				{0.0337729276, 0.821355339, 0.144871733},
+				{0.000179840964, 0.351955723, 0.647864436},
+				{0.00170081864, 0.292557601, 0.705741581},
+			},
+		},
+		{
+			"logreg_multinomial.protojson",
+			[]int64{0, 1, 2, 2},
+			[][]float32{
+				{0.984842703, 0.0151572872, 9.74731728e-09},
+				{0.0134484696, 0.962818568, 0.023732962},
+				{2.73989862e-06, 0.0474257523, 0.952571508},
+				{0.00159671749, 0.493710688, 0.504692595},
+			},
+		},
+		{
+			"ridge_classifier.protojson",
+			[]int64{0, 1, 1, 1},
+			[][]float32{
+				{0.911999944, -0.428999959, -1.48299987},
+				{0.0509999577, 0.291000032, -1.34199989},
+				{-0.26100004, 0.270000036, -1.0089999},
+				{-0.137000042, 0.304000033, -1.16699989},
+			},
+		},
+	}
+	for _, m := range models {
+		sg := Model(t, m.file)
+		sg.feed(linearModelSamples)
+		sg.expect(m.labels)
+		sg.expect(m.scores)
+		sg.errorBound = 0.00001
+		if err := sg.Execute(t); err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", m.file, err)
+		}
+
+		// Double and integer inputs give the same scores
+		samples := make([][]float64, len(linearModelSamples))
+		for i := range samples {
+			samples[i] = make([]float64, len(linearModelSamples[i]))
+			for j := range samples[i] {
+				samples[i][j] = float64(linearModelSamples[i][j])
+			}
+		}
+		sg.setInput(0, samples)
+		if err := sg.RunOnly(t, false); err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", m.file, err)
+		}
+	}
+}
+
+func TestLinearClassifierBinaryMultinomial(t *testing.T) {
+	for _, multi_class := range []int64{0, 1} {
+		sg := Test("LinearClassifier")
+		sg.addAttribute("coefficients", []float32{2, -1})
+		sg.addAttribute("intercepts", []float32{0.5})
+		sg.addAttribute("classlabels_ints", []int64{0, 1})
+		sg.addAttribute("multi_class", multi_class)
+		sg.addAttribute("post_transform", []byte("SOFTMAX"))
+		sg.addInput("X", []int{2, 2}, [][]float32{{1, 3}, {1, 0}})
+		sg.addOutput("Y", []int64{0, 1})
+		if multi_class == 0 {
+			// One-vs-rest gives the negative class 1 - score
+			sg.addOutput("Z", [][]float32{{1.5, -0.5}, {-1.5, 2.5}})
+		} else {
+			// Multinomial normalizes the opposite scores of both classes together
+			sg.addOutput("Z", [][]float32{{0.731059, 0.268941}, {0.006693, 0.993307}})
+		}
+		sg.errorBound = 0.00001
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+	}
+}
+
+func TestLinearClassifierInvalidMultiClass(t *testing.T) {
+	sg := Test("LinearClassifier")
+	sg.addAttribute("coefficients", []float32{2, -1})
+	sg.addAttribute("classlabels_ints", []int64{0, 1})
+	sg.addAttribute("multi_class", int64(2))
+	sg.addInput("X", []int{1, 2}, [][]float32{{1, 3}})
+	err := sg.InitOnly()
+	if err == nil {
+		t.Fatalf("multi_class 2 should be rejected")
+	}
+}
+
+// The input is read by another node after the classifier, it must keep its shape and type
+func TestLinearClassifierSharedInput(t *testing.T) {
+	sg := Test("LinearClassifier")
+	sg.addAttribute("coefficients", []float32{1, 0, 0, 0, 1, 0})
+	sg.addAttribute("intercepts", []float32{0, 0})
+	sg.addAttribute("classlabels_ints", []int64{0, 1})
+	sg.addInput("X", []int{3}, []float32{1, 2, 4})
+	sg.addOutput("Y", []int64{1})
+	sg.addOutput("Z", [][]float32{{1, 2}})
+	sg.onnxGraph.Node = append(sg.onnxGraph.Node, &ir.NodeProto{
+		OpType:    "Normalizer",
+		Input:     []string{"X"},
+		Output:    []string{"N"},
+		Attribute: []*ir.AttributeProto{{Name: "norm", S: []byte("MAX")}},
+	})
+	sg.onnxGraph.Output = append(sg.onnxGraph.Output, &ir.ValueInfoProto{Name: "N"})
+	sg.expect([]float32{0.25, 0.5, 1})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ops"
+)
+
+func checkExplanation(t *testing.T, e *ops.Explanation, bias []float64, contributions [][][]float64, scores, transformed [][]float64) {
+	t.Helper()
+	near := func(a, b float64) bool { return math.Abs(a-b) < 0.00001 }
+	for j := range bias {
+		if !near(bias[j], e.Bias[j]) {
+			t.Fatalf("expected bias %v, got %v", bias, e.Bias)
+		}
+	}
+	for i := range contributions {
+		for j := range contributions[i] {
+			for f := range contributions[i][j] {
+				if !near(contributions[i][j][f], e.Contributions[i][j][f]) {
+					t.Fatalf("sample %d: expected contributions %v, got %v", i, contributions[i], e.Contributions[i])
+				}
+			}
+		}
+		for j := range scores[i] {
+			if !near(scores[i][j], e.Scores[i][j]) {
+				t.Fatalf("sample %d: expected scores %v, got %v", i, scores[i], e.Scores[i])
+			}
+		}
+		if len(transformed[i]) != len(e.Transformed[i]) {
+			t.Fatalf("sample %d: expected transformed scores %v, got %v", i, transformed[i], e.Transformed[i])
+		}
+		for j := range transformed[i] {
+			if !near(transformed[i][j], e.Transformed[i][j]) {
+				t.Fatalf("sample %d: expected transformed scores %v, got %v", i, transformed[i], e.Transformed[i])
+			}
+		}
+	}
+}
+
+func TestLinearClassifierExplain(t *testing.T) {
+	sg := Test("LinearClassifier")
+	sg.addAttribute("coefficients", []float32{-0.22562418, 0.34188559, 0.68346153,
+		-0.68051993, -0.1975279, 0.03748541})
+	sg.addAttribute("intercepts", []float32{-3.91601811, 0.42575697, 0.13731251})
+	sg.addAttribute("classlabels_ints", []int64{1, 2, 3})
+	sg.addAttribute("post_transform", []byte("LOGISTIC"))
+	sg.addInput("X", []int{2, 2}, [][]float32{{1, 0}, {3, 44}})
+	sg.addOutput("Y", []int64{2, 1})
+	sg.addOutput("Z", [][]float32{{0.015647972, 0.751983387, 0.484950699}, {0.999971055, 1.17855e-12, 0.767471158}})
+	err := sg.InitOnly()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	e, err := sg.graph.Explain(sg.inputs)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	checkExplanation(t, e,
+		[]float64{-3.91601811, 0.42575697, 0.13731251},
+		[][][]float64{
+			{{-0.22562418, 0}, {0.68346153, 0}, {-0.1975279, 0}},
+			{{-0.67687254, 15.04296596}, {2.05038459, -29.94287692}, {-0.5925837, 1.64935804}},
+		},
+		[][]float64{{-4.14164229, 1.1092185, -0.06021539}, {10.45007543, -27.46673545, 1.19408663}},
+		[][]float64{{0.015647972, 0.751983387, 0.484950699}, {0.999971055, 1.17855e-12, 0.767471158}})
+}
+
+func TestLinearClassifierExplainBinary(t *testing.T) {
+	sg := Test("LinearClassifier")
+	sg.addAttribute("coefficients", []float32{2, -1})
+	sg.addAttribute("intercepts", []float32{0.5})
+	sg.addAttribute("classlabels_ints", []int64{0, 1})
+	sg.addInput("X", []int{1, 2}, [][]float32{{1, 3}})
+	sg.addOutput("Y", []int64{0})
+	sg.addOutput("Z", [][]float32{{1.5, -0.5}})
+	err := sg.InitOnly()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	e, err := sg.graph.Explain(sg.inputs)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	// The explanation is of the positive class, the negative one is derived from it
+	checkExplanation(t, e, []float64{0.5}, [][][]float64{{{2, -3}}}, [][]float64{{-0.5}}, [][]float64{{1.5, -0.5}})
+}
+
+func TestLinearRegressorExplain(t *testing.T) {
+	sg := Test("LinearRegressor")
+	sg.addAttribute("coefficients", []float32{0.1, 0.2, 0.3, 0.4})
+	sg.addAttribute("intercepts", []float32{0.5, 0.1})
+	sg.addAttribute("post_transform", []byte("PROBIT"))
+	sg.addInput("X", []int{2, 2}, [][]float64{{1, 1}, {0, 0.5}})
+	// PROBIT values come from the erfinv approximation the op uses
+	sg.addOutput("Y", [][]float32{{0.841756, 0.841756}, {0.253353, -0.524446}})
+	sg.errorBound = 0.00001
+	err := sg.InitOnly()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	e, err := sg.graph.Explain(sg.inputs)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	checkExplanation(t, e, []float64{0.5, 0.1},
+		[][][]float64{{{0.1, 0.2}, {0.3, 0.4}}, {{0, 0.1}, {0, 0.2}}},
+		[][]float64{{0.8, 0.8}, {0.6, 0.3}},
+		[][]float64{{0.841756, 0.841756}, {0.253353, -0.524446}})
+}
@@ -0,0 +1,52 @@
+package tests
+
+import "testing"
+
+func TestActivations(t *testing.T) {
+	input := [][]float32{{-2, 0}, {0.5, 3}}
+	for _, c := range []struct {
+		op       string
+		expected [][]float32
+	}{
+		{"Relu", [][]float32{{0, 0}, {0.5, 3}}},
+		{"Tanh", [][]float32{{-0.9640276, 0}, {0.46211717, 0.9950548}}},
+		{"Sigmoid", [][]float32{{0.11920292, 0.5}, {0.62245935, 0.95257413}}},
+	} {
+		sg := Test(c.op)
+		sg.addInput("X", []int{2, 2}, input)
+		sg.addOutput("Y", c.expected)
+		sg.errorBound = 0.00001
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", c.op, err)
+		}
+	}
+
+	sg := Test("Relu")
+	sg.addInput("X", []int{3}, []int64{-1, 0, 4})
+	sg.addOutput("Y", []int64{0, 0, 4})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSoftmax(t *testing.T) {
+	sg := Test("Softmax")
+	sg.addInput("X", []int{2, 3}, [][]float64{{1, 2, 3}, {1000, 1000, 1000}})
+	sg.addOutput("Y", [][]float64{{0.09003057317038046, 0.24472847105479764, 0.6652409557748219}, {1.0 / 3, 1.0 / 3, 1.0 / 3}})
+	sg.errorBound = 1e-12
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("Softmax")
+	sg.addAttribute("axis", int64(0))
+	sg.addInput("X", []int{2, 2}, [][]float32{{1, 2}, {3, 4}})
+	sg.addOutput("Y", [][]float32{})
+	err = sg.Execute(t)
+	if err == nil {
+		t.Fatalf("a softmax over the rows should be rejected")
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import "testing"
+
+func TestExpAndLog(t *testing.T) {
+	sg := Test("Exp")
+	sg.addInput("X", []int{3}, []float32{0, 1, -1})
+	sg.addOutput("Y", []float32{1, 2.7182817, 0.36787945})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("Log")
+	sg.addInput("X", []int{2, 2}, [][]float64{{1, 2.718281828459045}, {0.5, 10}})
+	sg.addOutput("Y", [][]float64{{0, 1}, {-0.6931471805599453, 2.302585092994046}})
+	sg.errorBound = 1e-12
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("Log")
+	sg.addInput("X", []int{1}, []int64{1})
+	sg.addOutput("Y", []int64{})
+	err = sg.Execute(t)
+	if err == nil {
+		t.Fatalf("the log of an integer tensor should be rejected")
+	}
+}
+
+func TestPow(t *testing.T) {
+	sg := Test("Pow")
+	sg.addInput("X", []int{2, 2}, [][]float32{{1, -2}, {3, 0.5}})
+	sg.addInput("Y", []int{1}, []float32{2})
+	sg.addOutput("Z", [][]float32{{1, 4}, {9, 0.25}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestGreaterAndLess(t *testing.T) {
+	for _, c := range []struct {
+		op       string
+		expected [][]bool
+	}{
+		{"Greater", [][]bool{{false, false, true}, {true, false, false}}},
+		{"Less", [][]bool{{true, false, false}, {false, false, true}}},
+	} {
+		sg := Test(c.op)
+		sg.addInput("A", []int{2, 3}, [][]float32{{0, 1, 2}, {3, 1, -1}})
+		sg.addInput("B", []int{1}, []float32{1})
+		sg.addOutput("Y", c.expected)
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", c.op, err)
+		}
+	}
+}
+
+func TestReduceLogSumExp(t *testing.T) {
+	sg := Test("ReduceLogSumExp")
+	sg.addAttribute("axes", []int64{1})
+	sg.addInput("X", []int{2, 2}, [][]float64{{0, 0}, {1000, 1000}})
+	sg.addOutput("Y", [][]float64{{0.6931471805599453}, {1000.6931471805599}})
+	sg.errorBound = 1e-9
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("ReduceLogSumExp")
+	sg.addAttribute("keepdims", int64(0))
+	sg.addInput("X", []int{3}, []float32{1, 2, 3})
+	sg.addOutput("Y", []float32{3.407606})
+	sg.errorBound = 0.00001
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
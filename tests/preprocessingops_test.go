@@ -0,0 +1,39 @@
+package tests
+
+import "testing"
+
+func TestOneHot(t *testing.T) {
+	sg := Test("OneHot")
+	sg.addInput("indices", []int{4}, []int64{0, 2, -1, 3})
+	sg.addInput("depth", []int{1}, []int64{3})
+	sg.addInput("values", []int{2}, []float32{0, 1})
+	sg.addOutput("Y", [][]float32{{1, 0, 0}, {0, 0, 1}, {0, 0, 1}, {0, 0, 0}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = Test("OneHot")
+	sg.addAttribute("axis", int64(0))
+	sg.addInput("indices", []int{3}, []float32{1, 0, 1.7})
+	sg.addInput("depth", []int{1}, []float32{2})
+	sg.addInput("values", []int{2}, []string{"off", "on"})
+	sg.addOutput("Y", [][]string{{"off", "on", "off"}, {"on", "off", "on"}})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestOneHotDepth(t *testing.T) {
+	sg := Test("OneHot")
+	sg.addInput("indices", []int{2}, []int64{0, 1})
+	sg.addInput("depth", []int{2}, []int64{2, 3})
+	sg.addInput("values", []int{2}, []int64{0, 1})
+	sg.addOutput("Y", [][]int64{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("a depth that isn't a scalar should be rejected")
+	}
+}
@@ -2,6 +2,8 @@ package tests
 
 import (
 	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
 )
 
 type scalerInput interface {
@@ -68,3 +70,23 @@ func TestScalerOffset1(t *testing.T) {
 		t.Fatalf("error shouldn't exist: %v", err)
 	}
 }
+
+// The input is also an output of the graph, the scaler must leave it as it is
+func TestScalerSharedInput(t *testing.T) {
+	sg := Test("Scaler")
+	sg.addAttribute("scale", []float32{2})
+	sg.addAttribute("offset", []float32{1})
+
+	input := []float64{0.25, 1.5, -3, 4}
+	shape := []int{2, 2}
+	sg.addInput("X", shape, input)
+	sg.addOutput("Y", [][]float32{{-1.5, 1}, {-8, 6}})
+	sg.onnxGraph.Output = append(sg.onnxGraph.Output, &ir.ValueInfoProto{Name: "X"})
+	sg.expected = append(sg.expected, [][]float64{{0.25, 1.5}, {-3, 4}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+func tensorType(elemType string) *ir.TypeProto {
+	return &ir.TypeProto{Value: &ir.TypeProto_TensorType{
+		TensorType: &ir.TypeProto_Tensor{ElemType: ir.TensorProto_DataType_value[elemType]},
+	}}
+}
+
+func TestSequenceConstruct(t *testing.T) {
+	sg := Test("SequenceConstruct")
+	sg.addInput("X", []int{2, 2}, [][]float32{{1, 2}, {3, 4}})
+	sg.addInput("Y", []int{3}, []float32{5, 6, 7})
+	sg.addOutput("S", []any{[][]float32{{1, 2}, {3, 4}}, []float32{5, 6, 7}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSequenceConstructMixedTypes(t *testing.T) {
+	sg := Test("SequenceConstruct")
+	sg.addInput("X", []int{2}, []float32{1, 2})
+	sg.addInput("Y", []int{2}, []int64{3, 4})
+	sg.addOutput("S", []any{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("a sequence of tensors of different types should be rejected")
+	}
+}
+
+func TestSequenceAt(t *testing.T) {
+	sequence := []any{[]int64{1, 2}, []int64{3}, []int64{4, 5, 6}}
+	for _, c := range []struct {
+		position int64
+		expected []int64
+	}{{0, []int64{1, 2}}, {2, []int64{4, 5, 6}}, {-2, []int64{3}}} {
+		sg := Test("SequenceAt")
+		sg.addInputSequence("S", sequence, tensorType("INT64"))
+		sg.addInput("P", []int{1}, []int64{c.position})
+		sg.addOutput("Y", c.expected)
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+	}
+}
+
+func TestSequenceAtOutOfBounds(t *testing.T) {
+	sg := Test("SequenceAt")
+	sg.addInputSequence("S", []any{[]float32{1}}, tensorType("FLOAT"))
+	sg.addInput("P", []int{1}, []int64{1})
+	sg.addOutput("Y", []float32{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("a position past the end of the sequence should be rejected")
+	}
+}
+
+func TestSequenceLength(t *testing.T) {
+	sg := Test("SequenceLength")
+	sg.addInputSequence("S", []any{[]string{"a"}, []string{"b", "c"}}, tensorType("STRING"))
+	sg.addOutput("L", []int64{2})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSequenceEmpty(t *testing.T) {
+	sg := Test("SequenceEmpty")
+	sg.addAttribute("dtype", int64(ir.TensorProto_DataType_value["FLOAT"]))
+	sg.addOutput("S", []any{})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSplitToSequenceColumns(t *testing.T) {
+	sg := Test("SplitToSequence")
+	sg.addAttribute("axis", int64(1))
+	sg.addAttribute("keepdims", int64(0))
+	sg.addInput("X", []int{3, 2}, [][]float32{{1, 2}, {3, 4}, {5, 6}})
+	sg.addOutput("S", []any{[]float32{1, 3, 5}, []float32{2, 4, 6}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSplitToSequenceLengths(t *testing.T) {
+	sg := Test("SplitToSequence")
+	sg.addInput("X", []int{3, 2}, [][]int64{{1, 2}, {3, 4}, {5, 6}})
+	sg.addInput("split", []int{2}, []int64{2, 1})
+	sg.addOutput("S", []any{[][]int64{{1, 2}, {3, 4}}, [][]int64{{5, 6}}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSplitToSequenceScalarSplit(t *testing.T) {
+	sg := Test("SplitToSequence")
+	sg.addInput("X", []int{5}, []float64{1, 2, 3, 4, 5})
+	sg.addInput("split", []int{1}, []int64{2})
+	sg.addOutput("S", []any{[]float64{1, 2}, []float64{3, 4}, []float64{5}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSplitToSequenceBadLengths(t *testing.T) {
+	sg := Test("SplitToSequence")
+	sg.addInput("X", []int{3}, []float32{1, 2, 3})
+	sg.addInput("split", []int{2}, []int64{1, 1})
+	sg.addOutput("S", []any{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("splits that don't cover the axis should be rejected")
+	}
+}
+
+// sklearn exports declare their dict inputs as sequences of maps, one map per row
+func TestSequenceOfMapsInput(t *testing.T) {
+	dv := Test("DictVectorizer")
+	dv.addAttribute("string_vocabulary", []string{"a", "b", "c"})
+	mapType := &ir.TypeProto{Value: &ir.TypeProto_MapType{MapType: &ir.TypeProto_Map{
+		KeyType:   ir.TensorProto_DataType_value["STRING"],
+		ValueType: tensorType("INT64"),
+	}}}
+	dv.addInputSequence("X", []map[string]int64{{"a": 1, "c": 2}, {"b": 3}}, mapType)
+	dv.addOutput("Y", [][]int64{{1, 0, 2}, {0, 3, 0}})
+	err := dv.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
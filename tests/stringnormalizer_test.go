@@ -0,0 +1,58 @@
+package tests
+
+import "testing"
+
+func TestStringNormalizerLowerStopwords(t *testing.T) {
+	sg := Test("StringNormalizer")
+	sg.addAttribute("case_change_action", []byte("LOWER"))
+	sg.addAttribute("is_case_sensitive", int64(0))
+	sg.addAttribute("stopwords", []string{"monday"})
+	sg.addInput("X", []int{4}, []string{"monday", "tuesday", "WEDNESDAY", "Monday"})
+	sg.addOutput("Y", []string{"tuesday", "wednesday"})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestStringNormalizerUpperCaseSensitive(t *testing.T) {
+	sg := Test("StringNormalizer")
+	sg.addAttribute("case_change_action", []byte("UPPER"))
+	sg.addAttribute("is_case_sensitive", int64(1))
+	sg.addAttribute("stopwords", []string{"monday"})
+	sg.addInput("X", []int{4}, []string{"monday", "tuesday", "wednesday", "Monday"})
+	sg.addOutput("Y", []string{"TUESDAY", "WEDNESDAY", "MONDAY"})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestStringNormalizer2D(t *testing.T) {
+	sg := Test("StringNormalizer")
+	sg.addAttribute("case_change_action", []byte("NONE"))
+	sg.addAttribute("stopwords", []string{"the"})
+	sg.addInput("X", []int{1, 3}, [][]string{{"The", "quick", "Fox"}})
+	sg.addOutput("Y", [][]string{{"quick", "Fox"}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestStringNormalizerAllStopwords(t *testing.T) {
+	sg := Test("StringNormalizer")
+	sg.addAttribute("case_change_action", []byte("LOWER"))
+	sg.addAttribute("stopwords", []string{"a", "the"})
+	sg.addInput("X", []int{2}, []string{"The", "A"})
+	sg.addOutput("Y", []string{""})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestStringNormalizerInvalidAction(t *testing.T) {
+	sg := Test("StringNormalizer")
+	sg.addAttribute("case_change_action", []byte("TITLE"))
+	sg.addInput("X", []int{1}, []string{"a"})
+	if err := sg.Execute(t); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -262,3 +262,38 @@ func TestSVMClassifierLinear(t *testing.T) {
 		t.Fatalf("got error: %v", err)
 	}
 }
+
+// A NuSVC export with three classes, each pair of classes has its own classifier
+func TestSVMClassifierNuSVC(t *testing.T) {
+	for _, double := range []bool{false, true} {
+		sg := Test("SVMClassifier")
+		sg.addAttribute("kernel_type", []byte("RBF"))
+		sg.addAttribute("coefficients", []float32{0.8, 0.6, -0.9, 0.45, 0.3, 0.55, 0.25, 1.0, -0.7, -0.35})
+		sg.addAttribute("support_vectors", []float32{0.5, 1.0, 1.0, 0.2, 3.0, 3.1, -2.0, 0.5, -1.5, -1.0})
+		sg.addAttribute("vectors_per_class", []int64{2, 1, 2})
+		sg.addAttribute("rho", []float32{0.12, -0.05, 0.3})
+		sg.addAttribute("kernel_params", []float32{0.3, 0, 3})
+		sg.addAttribute("classlabels_ints", []int64{0, 1, 2})
+		input := []float32{0.7, 0.6, 2.8, 2.9, -1.8, -0.2, 0, 0}
+		if double {
+			input64 := make([]float64, len(input))
+			for i := range input {
+				input64[i] = float64(input[i])
+			}
+			sg.addInput("X", []int{4, 2}, input64)
+		} else {
+			sg.addInput("X", []int{4, 2}, input)
+		}
+		sg.addOutput("Y", []int64{0, 1, 0, 0})
+		sg.addOutput("Z", [][]float32{
+			{1.4018275, 0.7828516, 0.2150121},
+			{-0.6777744, -0.0012024, 1.2761477},
+			{0.2806271, 0.6705579, -0.5782221},
+			{1.1056352, 0.7499061, -0.0238577},
+		})
+		sg.errorBound = 0.00001
+		if err := sg.Execute(t); err != nil {
+			t.Fatalf("error shouldn't exist: %v", err)
+		}
+	}
+}
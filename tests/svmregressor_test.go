@@ -99,3 +99,87 @@ func TestSVMRegressorLinear(t *testing.T) {
 	sg.errorBound = 0.0025
 	sg.Execute(t)
 }
+
+// OneClassSVM exports predict 1 for inliers and -1 for outliers
+func TestSVMRegressorOneClassSVM(t *testing.T) {
+	oneClass := func() *SingleNodeGraph {
+		sg := Test("SVMRegressor")
+		sg.addAttribute("kernel_type", []byte("RBF"))
+		sg.addAttribute("coefficients", []float32{0.31, 0.5, 0.19, 0.42})
+		sg.addAttribute("support_vectors", []float32{0.2, 1.1, 1.9, -0.4, -1.3, 0.7, 0.8, 2.2})
+		sg.addAttribute("rho", []float32{-0.45})
+		sg.addAttribute("kernel_params", []float32{0.5, 0, 3})
+		sg.addAttribute("n_supports", int64(4))
+		sg.addAttribute("one_class", int64(1))
+		sg.errorBound = 0.00001
+		return sg
+	}
+	// The decision function is 0.1231, -0.4495, 0.0775, -0.4052, 0.1960
+	expected := [][]float32{{1}, {-1}, {1}, {-1}, {1}}
+
+	sg := oneClass()
+	sg.addInput("X", []int{5, 2}, []float32{0.3, 0.9, 4, 4, 1.5, 0.1, -3, 1, 0.5, 1.5})
+	sg.addOutput("Y", expected)
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = oneClass()
+	sg.addInput("X", []int{5, 2}, []float64{0.3, 0.9, 4, 4, 1.5, 0.1, -3, 1, 0.5, 1.5})
+	sg.addOutput("Y", expected)
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+// SGDOneClassSVM exports are linear one-class SVMs, rho being minus the offset
+func TestSVMRegressorSGDOneClassSVM(t *testing.T) {
+	sg := Test("SVMRegressor")
+	sg.addAttribute("kernel_type", []byte("LINEAR"))
+	sg.addAttribute("coefficients", []float32{0.75, -0.4, 0.2})
+	sg.addAttribute("rho", []float32{-1.1})
+	sg.addAttribute("n_supports", int64(0))
+	sg.addAttribute("one_class", int64(1))
+	// The decision function is -0.25, 0.2, -2.125, 1.95
+	sg.addInput("X", []int{4, 3}, []int64{1, 0, 0, 2, 1, 1, 0, 3, -1, 3, -2, 0})
+	sg.addOutput("Y", [][]float32{{-1}, {1}, {-1}, {1}})
+	sg.errorBound = 0.00001
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+// Double inputs are scored in double precision, the graph can be fed either type
+func TestSVMRegressorNuSVRDouble(t *testing.T) {
+	sg := Test("SVMRegressor")
+	sg.addAttribute("kernel_type", []byte("RBF"))
+	sg.addAttribute("coefficients", []float32{-1.7902966, 1.05962596, -1.54324389, -0.43658884, 0.79025169, 1.92025169})
+	sg.addAttribute("support_vectors", []float32{0, 0.5, 32, 1, 1.5, 1, 2, 2.9, -32, 3, 13.3, -11, 12, 12.9, -312, 43, 413.3, -114})
+	sg.addAttribute("rho", []float32{1.96923464})
+	sg.addAttribute("kernel_params", []float32{0.001, 0, 3})
+	sg.addAttribute("n_supports", int64(6))
+	sg.addInput("X", []int{3, 3}, []float64{1, 0.0, 0.4, 3.0, 44.0, -3, 43.0, 413.3, -114})
+	sg.addOutput("Y", [][]float32{{1.51230766}, {1.77893206}, {3.88948633}})
+	sg.errorBound = 0.00001
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	sg.setInput(0, [][]float32{{1, 0.0, 0.4}, {3.0, 44.0, -3}, {43.0, 413.3, -114}})
+	if err := sg.RunOnly(t, false); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestSVMRegressorLinearSVRDouble(t *testing.T) {
+	sg := Test("SVMRegressor")
+	sg.addAttribute("kernel_type", []byte("LINEAR"))
+	sg.addAttribute("coefficients", []float32{0.28290501, -0.0266512, 0.01674867})
+	sg.addAttribute("rho", []float32{1.24032312})
+	sg.addAttribute("n_supports", int64(0))
+	sg.addInput("X", []int{2, 3}, [][]float64{{1, 0.0, 0.4}, {23.0, 3311.3, -222}})
+	sg.addOutput("Y", [][]float32{{1.52992759}, {-84.22117216}})
+	sg.errorBound = 0.0025
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package tests
+
+import "testing"
+
+// StringNormalizer -> Tokenizer -> TfIdfVectorizer -> Normalizer -> LinearClassifier, a hand-written
+// graph laid out like the skl2onnx export of a TfidfVectorizer + LogisticRegression pipeline. The
+// vocabulary, weights and coefficients are made up, not fitted, and the expected outputs are computed
+// from them in float64, not by onnxruntime.
+func TestTextClassifierPipeline(t *testing.T) {
+	sg := Model(t, "text_classifier.protojson")
+	sg.feed([]string{"Good movie, GREAT acting", "a bad bad movie", "Great great GOOD movie"})
+	sg.expect([]string{"pos", "neg", "pos"})
+	sg.expect([][]float32{
+		{0.13492289688805645, 0.8650771031119436},
+		{0.8586291962966013, 0.14137080370339872},
+		{0.11864225970255521, 0.8813577402974447},
+	})
+	sg.errorBound = 0.00001
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import "testing"
+
+type tfidfCase struct {
+	min_gram_length int64
+	max_gram_length int64
+	max_skip_count  int64
+	ngram_counts    []int64
+	ngram_indexes   []int64
+	pool_int64s     []int64
+}
+
+func (c tfidfCase) graph(mode string) *SingleNodeGraph {
+	sg := Test("TfIdfVectorizer")
+	sg.addAttribute("mode", []byte(mode))
+	sg.addAttribute("min_gram_length", c.min_gram_length)
+	sg.addAttribute("max_gram_length", c.max_gram_length)
+	sg.addAttribute("max_skip_count", c.max_skip_count)
+	sg.addAttribute("ngram_counts", c.ngram_counts)
+	sg.addAttribute("ngram_indexes", c.ngram_indexes)
+	sg.addAttribute("pool_int64s", c.pool_int64s)
+	sg.errorBound = 0.00001
+	return sg
+}
+
+// The cases below are taken from the onnx backend tests for TfIdfVectorizer
+var bigramsCase = tfidfCase{2, 2, 0, []int64{0, 4}, []int64{0, 1, 2, 3, 4, 5, 6}, []int64{2, 3, 5, 4, 5, 6, 7, 8, 6, 7}}
+
+func TestTfIdfVectorizerBatchOnlyBigramsSkip0(t *testing.T) {
+	sg := bigramsCase.graph("TF")
+	sg.addInput("X", []int{2, 6}, [][]int32{{1, 1, 3, 3, 3, 7}, {8, 6, 7, 5, 6, 8}})
+	sg.addOutput("Y", [][]float32{{0, 0, 0, 0, 0, 0, 0}, {0, 0, 0, 0, 1, 0, 1}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTfIdfVectorizerBatchOnlyBigramsSkip5(t *testing.T) {
+	c := bigramsCase
+	c.max_skip_count = 5
+	sg := c.graph("TF")
+	sg.addInput("X", []int{2, 6}, [][]int32{{1, 1, 3, 3, 3, 7}, {8, 6, 7, 5, 6, 8}})
+	sg.addOutput("Y", [][]float32{{0, 0, 0, 0, 0, 0, 0}, {0, 0, 0, 0, 1, 1, 1}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTfIdfVectorizerBatchUniAndBigramsSkip5(t *testing.T) {
+	c := bigramsCase
+	c.min_gram_length = 1
+	c.max_skip_count = 5
+	sg := c.graph("TF")
+	sg.addInput("X", []int{2, 6}, [][]int64{{1, 1, 3, 3, 3, 7}, {8, 6, 7, 5, 6, 8}})
+	sg.addOutput("Y", [][]float32{{0, 3, 0, 0, 0, 0, 0}, {0, 0, 1, 0, 1, 1, 1}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTfIdfVectorizerOnlyBigramsSkip0(t *testing.T) {
+	sg := bigramsCase.graph("TF")
+	sg.addInput("X", []int{12}, []int32{1, 1, 3, 3, 3, 7, 8, 6, 7, 5, 6, 8})
+	sg.addOutput("Y", []float32{0, 0, 0, 0, 1, 1, 1})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTfIdfVectorizerOnlyBigramsLevelEmpty(t *testing.T) {
+	c := tfidfCase{2, 2, 0, []int64{0, 0}, []int64{0, 1, 2}, []int64{5, 6, 7, 8, 6, 7}}
+	sg := c.graph("TF")
+	sg.addInput("X", []int{12}, []int32{1, 1, 3, 3, 3, 7, 8, 6, 7, 5, 6, 8})
+	sg.addOutput("Y", []float32{1, 1, 1})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTfIdfVectorizerUniAndBigramsSkip5(t *testing.T) {
+	c := bigramsCase
+	c.min_gram_length = 1
+	c.max_skip_count = 5
+	sg := c.graph("TF")
+	sg.addInput("X", []int{12}, []int32{1, 1, 3, 3, 3, 7, 8, 6, 7, 5, 6, 8})
+	sg.addOutput("Y", []float32{0, 3, 1, 0, 1, 3, 1})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTfIdfVectorizerWeightedModes(t *testing.T) {
+	values := []struct {
+		mode     string
+		expected [][]float32
+	}{
+		{"TF", [][]float32{{2, 1, 1}, {1, 1, 1}}},
+		{"IDF", [][]float32{{0.5, 2, 3}, {0.5, 2, 3}}},
+		{"TFIDF", [][]float32{{1, 2, 3}, {0.5, 2, 3}}},
+	}
+	for _, v := range values {
+		sg := Test("TfIdfVectorizer")
+		sg.addAttribute("mode", []byte(v.mode))
+		sg.addAttribute("min_gram_length", int64(1))
+		sg.addAttribute("max_gram_length", int64(2))
+		sg.addAttribute("max_skip_count", int64(0))
+		sg.addAttribute("ngram_counts", []int64{0, 2})
+		sg.addAttribute("ngram_indexes", []int64{0, 1, 2})
+		sg.addAttribute("pool_strings", []string{"good", "movie", "good", "movie"})
+		sg.addAttribute("weights", []float32{0.5, 2, 3})
+		sg.addInput("X", []int{2, 4}, [][]string{{"good", "good", "movie", "#"}, {"not", "good", "movie", "#"}})
+		sg.addOutput("Y", v.expected)
+		sg.errorBound = 0.00001
+		if err := sg.Execute(t); err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", v.mode, err)
+		}
+	}
+}
+
+func TestTfIdfVectorizerInvalidPool(t *testing.T) {
+	c := tfidfCase{2, 2, 0, []int64{0, 4}, []int64{0, 1, 2, 3}, []int64{2, 3, 5, 4, 5, 6, 7, 8, 6, 7}}
+	sg := c.graph("TF")
+	sg.addInput("X", []int{2}, []int32{1, 1})
+	if err := sg.Execute(t); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import "testing"
+
+func TestTokenizerSeparators(t *testing.T) {
+	sg := Test("Tokenizer")
+	sg.addAttribute("mark", int64(0))
+	sg.addAttribute("mincharnum", int64(1))
+	sg.addAttribute("pad_value", []byte("#"))
+	sg.addAttribute("separators", []string{" ", ";"})
+	sg.addInput("X", []int{2}, []string{"abc def;g", "hi"})
+	sg.addOutput("Y", [][]string{{"abc", "def", "g"}, {"hi", "#", "#"}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTokenizerMincharnum(t *testing.T) {
+	sg := Test("Tokenizer")
+	sg.addAttribute("mark", int64(0))
+	sg.addAttribute("mincharnum", int64(2))
+	sg.addAttribute("pad_value", []byte("#"))
+	sg.addAttribute("separators", []string{" "})
+	sg.addInput("X", []int{2, 1}, [][]string{{"a big  cat"}, {"x y"}})
+	sg.addOutput("Y", [][][]string{{{"big", "cat"}}, {{"#", "#"}}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+// An [N, C] input gets the tokens of every string on a third axis
+func TestTokenizerColumns(t *testing.T) {
+	sg := Test("Tokenizer")
+	sg.addAttribute("mark", int64(0))
+	sg.addAttribute("mincharnum", int64(1))
+	sg.addAttribute("pad_value", []byte("#"))
+	sg.addAttribute("separators", []string{" "})
+	sg.addInput("X", []int{2, 2}, [][]string{{"a b c", "d"}, {"", "e f"}})
+	sg.addOutput("Y", [][][]string{{{"a", "b", "c"}, {"d", "#", "#"}}, {{"#", "#", "#"}, {"e", "f", "#"}}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTokenizerMark(t *testing.T) {
+	sg := Test("Tokenizer")
+	sg.addAttribute("mark", int64(1))
+	sg.addAttribute("mincharnum", int64(1))
+	sg.addAttribute("pad_value", []byte("#"))
+	sg.addAttribute("separators", []string{" "})
+	sg.addInput("X", []int{2}, []string{"one two", ""})
+	sg.addOutput("Y", [][]string{{"\x02", "one", "two", "\x03"}, {"\x02", "\x03", "#", "#"}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTokenizerTokenExp(t *testing.T) {
+	sg := Test("Tokenizer")
+	sg.addAttribute("mark", int64(0))
+	sg.addAttribute("mincharnum", int64(2))
+	sg.addAttribute("pad_value", []byte("#"))
+	sg.addAttribute("tokenexp", []byte(`\w+`))
+	sg.addInput("X", []int{2}, []string{"Hello, world! I'm here.", "no-op"})
+	sg.addOutput("Y", [][]string{{"Hello", "world", "here"}, {"no", "op", "#"}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTokenizerCharacters(t *testing.T) {
+	sg := Test("Tokenizer")
+	sg.addAttribute("mark", int64(0))
+	sg.addAttribute("mincharnum", int64(1))
+	sg.addAttribute("pad_value", []byte("#"))
+	sg.addAttribute("separators", []string{""})
+	sg.addInput("X", []int{2}, []string{"añb", "c"})
+	sg.addOutput("Y", [][]string{{"a", "ñ", "b"}, {"c", "#", "#"}})
+	if err := sg.Execute(t); err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTokenizerSeparatorsAndTokenExp(t *testing.T) {
+	sg := Test("Tokenizer")
+	sg.addAttribute("mincharnum", int64(1))
+	sg.addAttribute("pad_value", []byte("#"))
+	sg.addAttribute("separators", []string{" "})
+	sg.addAttribute("tokenexp", []byte(`\w+`))
+	sg.addInput("X", []int{1}, []string{"a b"})
+	if err := sg.Execute(t); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ops"
+)
+
+func TestTreeDecisionPaths(t *testing.T) {
+	sg := aggregateRegressor("SUM")
+	sg.addOutput("Y", [][]float32{{16, 22}, {18, 24}, {12, 26}})
+	err := sg.InitOnly()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	leaves, err := sg.graph.DecisionPaths(sg.inputs, false)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	expected := [][]ops.DecisionPath{
+		{{TreeID: 0, LeafID: 1}, {TreeID: 1, LeafID: 1}},
+		{{TreeID: 0, LeafID: 2}, {TreeID: 1, LeafID: 1}},
+		{{TreeID: 0, LeafID: 2}, {TreeID: 1, LeafID: 2}},
+	}
+	if !reflect.DeepEqual(expected, leaves) {
+		t.Fatalf("expected %v, got %v", expected, leaves)
+	}
+
+	paths, err := sg.graph.DecisionPaths(sg.inputs, true)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	step := func(threshold float64, value float64, condition bool) []ops.DecisionStep {
+		return []ops.DecisionStep{{NodeID: 0, Feature: 0, Mode: "BRANCH_LEQ", Threshold: threshold, Value: value, Condition: condition}}
+	}
+	expected = [][]ops.DecisionPath{
+		{{TreeID: 0, LeafID: 1, Steps: step(0.5, 0, true)}, {TreeID: 1, LeafID: 1, Steps: step(1.5, 0, true)}},
+		{{TreeID: 0, LeafID: 2, Steps: step(0.5, 1, false)}, {TreeID: 1, LeafID: 1, Steps: step(1.5, 1, true)}},
+		{{TreeID: 0, LeafID: 2, Steps: step(0.5, 2, false)}, {TreeID: 1, LeafID: 2, Steps: step(1.5, 2, false)}},
+	}
+	if !reflect.DeepEqual(expected, paths) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+}
+
+func TestTreeDecisionPathsDeepTree(t *testing.T) {
+	sg := Test("TreeEnsemble")
+	sg.addAttribute("n_targets", int64(2))
+	sg.addAttribute("aggregate_function", int64(1))
+	sg.addAttribute("tree_roots", []int64{0})
+	sg.addAttribute("nodes_modes", modesTensor(0, 0, 0))
+	sg.addAttribute("nodes_featureids", []int64{0, 1, 0})
+	sg.addAttribute("nodes_splits", doubleTensor(3.14, 1.2, 4.2))
+	sg.addAttribute("nodes_truenodeids", []int64{1, 0, 1})
+	sg.addAttribute("nodes_trueleafs", []int64{0, 1, 1})
+	sg.addAttribute("nodes_falsenodeids", []int64{2, 2, 3})
+	sg.addAttribute("nodes_falseleafs", []int64{0, 1, 1})
+	sg.addAttribute("leaf_targetids", []int64{0, 1, 0, 1})
+	sg.addAttribute("leaf_weights", doubleTensor(5.23, 12.12, -12.23, 7.21))
+	sg.addInput("X", []int{1, 2}, []float64{1, 3.4})
+	sg.addOutput("Y", [][]float64{{-12.23, 0}})
+	err := sg.InitOnly()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	paths, err := sg.graph.DecisionPaths(sg.inputs, true)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	// Nodes are numbered depth first: 0 the root, 1 its true child, 2 and 3 the leaves below it
+	expected := []ops.DecisionPath{{TreeID: 0, LeafID: 3, Steps: []ops.DecisionStep{
+		{NodeID: 0, Feature: 0, Mode: "BRANCH_LEQ", Threshold: 3.14, Value: 1, Condition: true},
+		{NodeID: 1, Feature: 1, Mode: "BRANCH_LEQ", Threshold: 1.2, Value: 3.4, Condition: false},
+	}}}
+	if !reflect.DeepEqual(expected, paths[0]) {
+		t.Fatalf("expected %v, got %v", expected, paths[0])
+	}
+}
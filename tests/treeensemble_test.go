@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/ir"
+)
+
+func floatTensor(values ...float32) *ir.TensorProto {
+	return &ir.TensorProto{DataType: int32(ir.TensorProto_FLOAT), Dims: []int64{int64(len(values))}, FloatData: values}
+}
+
+func doubleTensor(values ...float64) *ir.TensorProto {
+	return &ir.TensorProto{DataType: int32(ir.TensorProto_DOUBLE), Dims: []int64{int64(len(values))}, DoubleData: values}
+}
+
+// Modes are uint8 tensors, which are stored in int32_data
+func modesTensor(values ...int32) *ir.TensorProto {
+	return &ir.TensorProto{DataType: int32(ir.TensorProto_UINT8), Dims: []int64{int64(len(values))}, Int32Data: values}
+}
+
+func treeEnsembleSingleTree(double bool) *SingleNodeGraph {
+	sg := Test("TreeEnsemble")
+	sg.addAttribute("n_targets", int64(2))
+	sg.addAttribute("aggregate_function", int64(1))
+	sg.addAttribute("post_transform", int64(0))
+	sg.addAttribute("tree_roots", []int64{0})
+	sg.addAttribute("nodes_modes", modesTensor(0, 0, 0))
+	sg.addAttribute("nodes_featureids", []int64{0, 0, 0})
+	if double {
+		sg.addAttribute("nodes_splits", doubleTensor(3.14, 1.2, 4.2))
+		sg.addAttribute("leaf_weights", doubleTensor(5.23, 12.12, -12.23, 7.21))
+	} else {
+		sg.addAttribute("nodes_splits", floatTensor(3.14, 1.2, 4.2))
+		sg.addAttribute("leaf_weights", floatTensor(5.23, 12.12, -12.23, 7.21))
+	}
+	sg.addAttribute("nodes_truenodeids", []int64{1, 0, 1})
+	sg.addAttribute("nodes_trueleafs", []int64{0, 1, 1})
+	sg.addAttribute("nodes_falsenodeids", []int64{2, 2, 3})
+	sg.addAttribute("nodes_falseleafs", []int64{0, 1, 1})
+	sg.addAttribute("leaf_targetids", []int64{0, 1, 0, 1})
+	sg.errorBound = 0.00001
+	return sg
+}
+
+func TestTreeEnsembleSingleTree(t *testing.T) {
+	sg := treeEnsembleSingleTree(true)
+	sg.addInput("X", []int{3, 2}, []float64{1.2, 3.4, -0.12, 1.66, 4.14, 1.77})
+	sg.addOutput("Y", [][]float64{{5.23, 0}, {5.23, 0}, {0, 12.12}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	sg = treeEnsembleSingleTree(false)
+	sg.addInput("X", []int{3, 2}, []float32{1.2, 3.4, -0.12, 1.66, 4.14, 1.77})
+	sg.addOutput("Y", [][]float32{{5.23, 0}, {5.23, 0}, {0, 12.12}})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTreeEnsembleSetMembership(t *testing.T) {
+	nan := float32(math.NaN())
+	sg := Test("TreeEnsemble")
+	sg.addAttribute("n_targets", int64(4))
+	sg.addAttribute("aggregate_function", int64(1))
+	sg.addAttribute("post_transform", int64(0))
+	sg.addAttribute("tree_roots", []int64{0})
+	sg.addAttribute("membership_values", floatTensor(1.2, 3.7, 8, 9, nan, 12, 7, nan))
+	sg.addAttribute("nodes_modes", modesTensor(0, 6, 6))
+	sg.addAttribute("nodes_featureids", []int64{0, 0, 0})
+	sg.addAttribute("nodes_splits", floatTensor(11, 232344, nan))
+	sg.addAttribute("nodes_truenodeids", []int64{1, 0, 1})
+	sg.addAttribute("nodes_trueleafs", []int64{0, 1, 1})
+	sg.addAttribute("nodes_falsenodeids", []int64{2, 2, 3})
+	sg.addAttribute("nodes_falseleafs", []int64{1, 0, 1})
+	sg.addAttribute("leaf_targetids", []int64{0, 1, 2, 3})
+	sg.addAttribute("leaf_weights", floatTensor(1, 10, 1000, 100))
+	sg.addInput("X", []int{6, 1}, []float32{1.2, 3.4, -0.12, nan, 12, 7})
+	sg.addOutput("Y", [][]float32{
+		{1, 0, 0, 0},
+		{0, 0, 0, 100},
+		{0, 0, 0, 100},
+		{0, 0, 1000, 0},
+		{0, 0, 1000, 0},
+		{0, 10, 0, 0},
+	})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTreeEnsembleAverage(t *testing.T) {
+	// Two stumps on the same feature, the average is divided by the number of trees
+	sg := Test("TreeEnsemble")
+	sg.addAttribute("n_targets", int64(1))
+	sg.addAttribute("aggregate_function", int64(0))
+	sg.addAttribute("tree_roots", []int64{0, 1})
+	sg.addAttribute("nodes_modes", modesTensor(0, 3))
+	sg.addAttribute("nodes_featureids", []int64{0, 0})
+	sg.addAttribute("nodes_splits", floatTensor(1, 2))
+	sg.addAttribute("nodes_truenodeids", []int64{0, 2})
+	sg.addAttribute("nodes_trueleafs", []int64{1, 1})
+	sg.addAttribute("nodes_falsenodeids", []int64{1, 3})
+	sg.addAttribute("nodes_falseleafs", []int64{1, 1})
+	sg.addAttribute("leaf_targetids", []int64{0, 0, 0, 0})
+	sg.addAttribute("leaf_weights", floatTensor(2, 4, 10, 20))
+	sg.addInput("X", []int{3, 1}, []float32{0.5, 1.5, 3})
+	sg.addOutput("Y", [][]float32{{11}, {12}, {7}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTreeEnsembleInvalidTree(t *testing.T) {
+	sg := Test("TreeEnsemble")
+	sg.addAttribute("n_targets", int64(1))
+	sg.addAttribute("tree_roots", []int64{0})
+	sg.addAttribute("nodes_modes", modesTensor(0))
+	sg.addAttribute("nodes_featureids", []int64{0})
+	sg.addAttribute("nodes_splits", floatTensor(1))
+	sg.addAttribute("nodes_truenodeids", []int64{0})
+	sg.addAttribute("nodes_trueleafs", []int64{0})
+	sg.addAttribute("nodes_falsenodeids", []int64{0})
+	sg.addAttribute("nodes_falseleafs", []int64{1})
+	sg.addAttribute("leaf_targetids", []int64{0})
+	sg.addAttribute("leaf_weights", floatTensor(1))
+	sg.addInput("X", []int{1, 1}, []float32{0.5})
+	sg.addOutput("Y", [][]float32{{1}})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("a tree with a cycle should be rejected")
+	}
+}
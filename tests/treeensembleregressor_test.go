@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/systemEng-Learning/go-ml-deployment/kernel"
+)
+
+// A stump whose threshold isn't representable as a float32, inputs just above it should take the
+// false branch whatever their type.
+func doubleStump(sg *SingleNodeGraph) {
+	sg.addAttribute("nodes_treeids", []int64{0, 0, 0})
+	sg.addAttribute("nodes_nodeids", []int64{0, 1, 2})
+	sg.addAttribute("nodes_featureids", []int64{0, 0, 0})
+	sg.addAttribute("nodes_modes", []string{"BRANCH_LEQ", "LEAF", "LEAF"})
+	sg.addAttribute("nodes_values_as_tensor", doubleTensor(0.1, 0, 0))
+	sg.addAttribute("nodes_truenodeids", []int64{1, 0, 0})
+	sg.addAttribute("nodes_falsenodeids", []int64{2, 0, 0})
+	sg.addAttribute("nodes_missing_value_tracks_true", []int64{0, 0, 0})
+}
+
+func TestTreeEnsembleRegressorDoubleThresholds(t *testing.T) {
+	regressor := func() *SingleNodeGraph {
+		sg := Test("TreeEnsembleRegressor")
+		doubleStump(sg)
+		sg.addAttribute("n_targets", int64(1))
+		sg.addAttribute("target_treeids", []int64{0, 0})
+		sg.addAttribute("target_nodeids", []int64{1, 2})
+		sg.addAttribute("target_ids", []int64{0, 0})
+		sg.addAttribute("target_weights_as_tensor", doubleTensor(1, 2))
+		sg.addAttribute("base_values_as_tensor", doubleTensor(0.5))
+		sg.errorBound = 0.00001
+		return sg
+	}
+
+	sg := regressor()
+	sg.addInput("X", []int{3, 1}, []float64{0.05, 0.1, 0.1000000001})
+	sg.addOutput("Y", [][]float32{{1.5}, {1.5}, {2.5}})
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	// float32(0.1) is slightly above 0.1, float inputs are compared in double precision
+	sg = regressor()
+	sg.addInput("X", []int{2, 1}, []float32{0.05, 0.1})
+	sg.addOutput("Y", [][]float32{{1.5}, {2.5}})
+	err = sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestTreeEnsembleClassifierDoubleWeights(t *testing.T) {
+	sg := Test("TreeEnsembleClassifier")
+	doubleStump(sg)
+	sg.addAttribute("classlabels_int64s", []int64{0, 1})
+	sg.addAttribute("class_treeids", []int64{0, 0, 0, 0})
+	sg.addAttribute("class_nodeids", []int64{1, 1, 2, 2})
+	sg.addAttribute("class_ids", []int64{0, 1, 0, 1})
+	sg.addAttribute("class_weights_as_tensor", doubleTensor(0.75, 0.25, 0.2, 0.8))
+	sg.addInput("X", []int{2, 1}, []float64{0.1, 0.1000000001})
+	sg.addOutput("label", []int64{0, 1})
+	sg.addOutput("probabilities", [][]float32{{0.75, 0.25}, {0.2, 0.8}})
+	sg.errorBound = 0.00001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+// Two stumps with two targets, the first target of the first tree's right leaf is missing
+func aggregateRegressor(aggregate string) *SingleNodeGraph {
+	sg := Test("TreeEnsembleRegressor")
+	sg.addAttribute("n_targets", int64(2))
+	sg.addAttribute("aggregate_function", []byte(aggregate))
+	sg.addAttribute("base_values", []float32{10, 20})
+	sg.addAttribute("nodes_treeids", []int64{0, 0, 0, 1, 1, 1})
+	sg.addAttribute("nodes_nodeids", []int64{0, 1, 2, 0, 1, 2})
+	sg.addAttribute("nodes_featureids", []int64{0, 0, 0, 0, 0, 0})
+	sg.addAttribute("nodes_modes", []string{"BRANCH_LEQ", "LEAF", "LEAF", "BRANCH_LEQ", "LEAF", "LEAF"})
+	sg.addAttribute("nodes_values", []float32{0.5, 0, 0, 1.5, 0, 0})
+	sg.addAttribute("nodes_truenodeids", []int64{1, 0, 0, 1, 0, 0})
+	sg.addAttribute("nodes_falsenodeids", []int64{2, 0, 0, 2, 0, 0})
+	sg.addAttribute("target_treeids", []int64{0, 0, 0, 1, 1, 1, 1})
+	sg.addAttribute("target_nodeids", []int64{1, 1, 2, 1, 1, 2, 2})
+	sg.addAttribute("target_ids", []int64{0, 1, 0, 0, 1, 0, 1})
+	sg.addAttribute("target_weights", []float32{1, -2, 3, 5, 4, -1, 6})
+	sg.addInput("X", []int{3, 1}, []float32{0, 1, 2})
+	sg.errorBound = 0.00001
+	return sg
+}
+
+func TestTreeEnsembleRegressorAggregate(t *testing.T) {
+	expected := map[string][][]float32{
+		"SUM":     {{16, 22}, {18, 24}, {12, 26}},
+		"AVERAGE": {{13, 21}, {14, 22}, {11, 23}},
+		"MIN":     {{11, 18}, {13, 24}, {9, 26}},
+		"MAX":     {{15, 24}, {15, 24}, {13, 26}},
+	}
+	for aggregate, output := range expected {
+		sg := aggregateRegressor(aggregate)
+		sg.addOutput("Y", output)
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", aggregate, err)
+		}
+	}
+}
+
+func TestTreeEnsembleRegressorInvalidAggregate(t *testing.T) {
+	sg := aggregateRegressor("MEDIAN")
+	sg.addOutput("Y", [][]float32{})
+	err := sg.Execute(t)
+	if err == nil {
+		t.Fatalf("MEDIAN isn't an aggregate function")
+	}
+}
+
+// Two trees made of a single leaf: a weight of 0 is a minimum like any other, and the maximum of
+// negative weights stays negative
+func TestTreeEnsembleRegressorAggregateSigns(t *testing.T) {
+	cases := map[string]struct {
+		weights  []float32
+		expected float32
+	}{
+		"MIN": {[]float32{0, 2}, 0},
+		"MAX": {[]float32{-3, -1}, -1},
+	}
+	for aggregate, c := range cases {
+		sg := Test("TreeEnsembleRegressor")
+		sg.addAttribute("n_targets", int64(1))
+		sg.addAttribute("aggregate_function", []byte(aggregate))
+		sg.addAttribute("nodes_treeids", []int64{0, 1})
+		sg.addAttribute("nodes_nodeids", []int64{0, 0})
+		sg.addAttribute("nodes_featureids", []int64{0, 0})
+		sg.addAttribute("nodes_modes", []string{"LEAF", "LEAF"})
+		sg.addAttribute("nodes_values", []float32{0, 0})
+		sg.addAttribute("nodes_truenodeids", []int64{0, 0})
+		sg.addAttribute("nodes_falsenodeids", []int64{0, 0})
+		sg.addAttribute("target_treeids", []int64{0, 1})
+		sg.addAttribute("target_nodeids", []int64{0, 0})
+		sg.addAttribute("target_ids", []int64{0, 0})
+		sg.addAttribute("target_weights", c.weights)
+		sg.addInput("X", []int{1, 1}, []float32{0})
+		sg.addOutput("Y", [][]float32{{c.expected}})
+		sg.errorBound = 0.00001
+		err := sg.Execute(t)
+		if err != nil {
+			t.Fatalf("%s: error shouldn't exist: %v", aggregate, err)
+		}
+	}
+}
+
+// The strategy is an option of each graph: QuickScorer can't evaluate equality splits, so only the
+// graph that asks for it fails to load
+func TestTreeEnsembleRegressorStrategy(t *testing.T) {
+	for _, strategy := range []kernel.TreeStrategy{kernel.TreeStrategyAuto, kernel.TreeStrategyTraversal, kernel.TreeStrategyQuickScorer} {
+		for _, mode := range []string{"BRANCH_LEQ", "BRANCH_EQ"} {
+			sg := aggregateRegressor("SUM")
+			sg.options.TreeStrategy = strategy
+			output := [][]float32{{16, 22}, {18, 24}, {12, 26}}
+			if mode == "BRANCH_EQ" {
+				for _, attr := range sg.onnxGraph.Node[0].Attribute {
+					if attr.Name == "nodes_modes" {
+						attr.Strings[0], attr.Strings[3] = []byte(mode), []byte(mode)
+					}
+				}
+				// No input equals a threshold
+				output = [][]float32{{12, 26}, {12, 26}, {12, 26}}
+			}
+			sg.addOutput("Y", output)
+			err := sg.Execute(t)
+			if strategy == kernel.TreeStrategyQuickScorer && mode == "BRANCH_EQ" {
+				if err == nil {
+					t.Fatalf("QuickScorer shouldn't accept BRANCH_EQ splits")
+				}
+			} else if err != nil {
+				t.Fatalf("strategy %d, %s: error shouldn't exist: %v", strategy, mode, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"math"
+	"testing"
+)
+
+// A depth 2 tree scoring 1 when both features are above 0.5, trained on the four corners
+func andTree(aggregate string) *SingleNodeGraph {
+	sg := Test("TreeEnsembleRegressor")
+	sg.addAttribute("n_targets", int64(1))
+	sg.addAttribute("aggregate_function", []byte(aggregate))
+	sg.addAttribute("nodes_treeids", []int64{0, 0, 0, 0, 0, 0, 0})
+	sg.addAttribute("nodes_nodeids", []int64{0, 1, 2, 3, 4, 5, 6})
+	sg.addAttribute("nodes_featureids", []int64{0, 1, 1, 0, 0, 0, 0})
+	sg.addAttribute("nodes_modes", []string{"BRANCH_LEQ", "BRANCH_LEQ", "BRANCH_LEQ", "LEAF", "LEAF", "LEAF", "LEAF"})
+	sg.addAttribute("nodes_values", []float32{0.5, 0.5, 0.5, 0, 0, 0, 0})
+	sg.addAttribute("nodes_truenodeids", []int64{1, 3, 5, 0, 0, 0, 0})
+	sg.addAttribute("nodes_falsenodeids", []int64{2, 4, 6, 0, 0, 0, 0})
+	sg.addAttribute("nodes_missing_value_tracks_true", []int64{0, 0, 0, 0, 0, 0, 0})
+	sg.addAttribute("nodes_hitrates", []float32{4, 2, 2, 1, 1, 1, 1})
+	sg.addAttribute("target_treeids", []int64{0, 0, 0, 0})
+	sg.addAttribute("target_nodeids", []int64{3, 4, 5, 6})
+	sg.addAttribute("target_ids", []int64{0, 0, 0, 0})
+	sg.addAttribute("target_weights", []float32{0, 0, 0, 1})
+	sg.addInput("X", []int{2, 2}, []float32{1, 1, 0, 1})
+	sg.addOutput("Y", [][]float32{{1}, {0}})
+	return sg
+}
+
+func TestTreeShap(t *testing.T) {
+	sg := andTree("SUM")
+	err := sg.InitOnly()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	e, err := sg.graph.Explain(sg.inputs)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	if len(e.Bias) != 1 || e.Bias[0] != 0.25 {
+		t.Fatalf("expected a bias of [0.25], got %v", e.Bias)
+	}
+	// Knowing one feature is above 0.5 doubles the chance of the other one being too
+	expected := [][]float64{{0.375, 0.375}, {-0.375, 0.125}}
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(expected[i][j]-e.Contributions[i][0][j]) > 1e-12 {
+				t.Fatalf("sample %d: expected %v, got %v", i, expected[i], e.Contributions[i][0])
+			}
+		}
+	}
+}
+
+func TestTreeShapMaxAggregate(t *testing.T) {
+	sg := andTree("MAX")
+	err := sg.InitOnly()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	_, err = sg.graph.Explain(sg.inputs)
+	if err == nil {
+		t.Fatalf("a MAX ensemble isn't additive and shouldn't be explained")
+	}
+}
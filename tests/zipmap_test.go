@@ -23,7 +23,7 @@ func runzipmap[T maptype](t *testing.T, classes []T, mtype string, shape []int,
 	}
 	var output []map[T]float32
 	if expect_success {
-		output := make([]map[T]float32, batch_size)
+		output = make([]map[T]float32, batch_size)
 		for i := range batch_size {
 			output[i] = make(map[T]float32)
 			for j := range classes {
@@ -67,3 +67,55 @@ func TestZipMapIntFloatColMoreThanLabels(t *testing.T) {
 func TestZipMapIntFloatColLessThanLabels(t *testing.T) {
 	runzipmap(t, []int64{10, 20, 30}, "int64", []int{3, 2}, false)
 }
+
+func TestZipMapStringDouble(t *testing.T) {
+	sg := Test("ZipMap")
+	sg.addAttribute("classlabels_strings", []string{"class1", "class2", "class3"})
+	sg.addInput("X", []int{2, 3}, [][]float64{{0.1, 0.2, 0.7}, {0.5, 0.25, 0.25}})
+	sg.addOutput("Y", []map[string]float64{
+		{"class1": 0.1, "class2": 0.2, "class3": 0.7},
+		{"class1": 0.5, "class2": 0.25, "class3": 0.25},
+	})
+	sg.errorBound = 0.0000001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestZipMapIntDouble(t *testing.T) {
+	sg := Test("ZipMap")
+	sg.addAttribute("classlabels_int64s", []int64{10, 20})
+	sg.addInput("X", []int{3, 2}, [][]float64{{0.1, 0.9}, {0.6, 0.4}, {1, 0}})
+	sg.addOutput("Y", []map[int64]float64{{10: 0.1, 20: 0.9}, {10: 0.6, 20: 0.4}, {10: 1, 20: 0}})
+	sg.errorBound = 0.0000001
+	err := sg.Execute(t)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}
+
+func TestZipMapRawProbabilities(t *testing.T) {
+	sg := Test("ZipMap")
+	sg.addAttribute("classlabels_int64s", []int64{10, 20, 30})
+	sg.addInput("X", []int{2, 3}, [][]float32{{0.1, 0.2, 0.7}, {0.5, 0.25, 0.25}})
+	sg.addOutput("Y", [][]float32{{0.1, 0.2, 0.7}, {0.5, 0.25, 0.25}})
+	sg.errorBound = 0.0000001
+	err := sg.InitOnly()
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+	sg.graph.RawProbabilities(true)
+	err = sg.RunOnly(t, false)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+
+	// Switching back gives maps again
+	sg.graph.RawProbabilities(false)
+	sg.expected[0] = []map[int64]float32{{10: 0.1, 20: 0.2, 30: 0.7}, {10: 0.5, 20: 0.25, 30: 0.25}}
+	err = sg.RunOnly(t, false)
+	if err != nil {
+		t.Fatalf("error shouldn't exist: %v", err)
+	}
+}